@@ -0,0 +1,152 @@
+// Command trapsink is a local SNMP trap receiver that prints the traps sent
+// by the gateway, to check trap targets and SNMPv3 credentials without an
+// NMS. Point a target of notifications.snmp at it:
+//
+//	go run ./cmd/trapsink -listen 127.0.0.1:9162
+//	go run ./cmd/trapsink -listen 127.0.0.1:9162 -user gateway -level authPriv \
+//	    -auth SHA -auth-pass change-me-auth -priv AES -priv-pass change-me-priv \
+//	    -engine-id 80007ed904676174657761792d31
+//
+// SNMPv3 traps can only be decoded with the engine ID of the gateway, which
+// is logged when the gateway starts.
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// names of the NEXTRANET-GATEWAY-MIB notifications and objects, relative to
+// the enterprise OID
+var names = map[string]string{
+	".1.0.1": "ntgAlarmRaised",
+	".1.0.2": "ntgAlarmAcknowledged",
+	".1.0.3": "ntgAlarmCleared",
+	".1.0.4": "ntgAlarmResyncStart",
+	".1.0.5": "ntgAlarmActive",
+	".1.0.6": "ntgAlarmResyncEnd",
+
+	".1.1.1.1":  "ntgAlarmId",
+	".1.1.1.2":  "ntgDeviceId",
+	".1.1.1.3":  "ntgDeviceSerial",
+	".1.1.1.4":  "ntgDeviceModel",
+	".1.1.1.5":  "ntgAlarmCode",
+	".1.1.1.6":  "ntgAlarmSeverity",
+	".1.1.1.7":  "ntgAlarmMessage",
+	".1.1.1.8":  "ntgAlarmTime",
+	".1.1.1.9":  "ntgAlarmOperator",
+	".1.1.1.10": "ntgEventSequence",
+	".1.1.1.11": "ntgResyncId",
+	".1.1.1.12": "ntgResyncAlarmCount",
+}
+
+func main() {
+	var (
+		listen     = flag.String("listen", "127.0.0.1:9162", "UDP address to receive traps on")
+		enterprise = flag.String("enterprise", "1.3.6.1.4.1.32473", "Enterprise OID of the gateway MIB")
+		community  = flag.String("community", "", "Only accept SNMPv2c traps with this community")
+		user       = flag.String("user", "", "SNMPv3 user name")
+		level      = flag.String("level", "noAuthNoPriv", "SNMPv3 security level: noAuthNoPriv, authNoPriv or authPriv")
+		authProto  = flag.String("auth", "SHA", "SNMPv3 authentication protocol: MD5, SHA, SHA224, SHA256, SHA384, SHA512")
+		authPass   = flag.String("auth-pass", "", "SNMPv3 authentication password")
+		privProto  = flag.String("priv", "AES", "SNMPv3 privacy protocol: DES, AES, AES192, AES256")
+		privPass   = flag.String("priv-pass", "", "SNMPv3 privacy password")
+		engineID   = flag.String("engine-id", "", "Hex encoded SNMPv3 engine ID of the gateway")
+	)
+	flag.Parse()
+
+	listener := gosnmp.NewTrapListener()
+	listener.Params = &gosnmp.GoSNMP{
+		Version: gosnmp.Version2c,
+		Timeout: 5 * time.Second,
+	}
+
+	if *user != "" {
+		engine, err := hex.DecodeString(*engineID)
+		if err != nil || len(engine) == 0 {
+			log.Fatalf("SNMPv3 needs the hex encoded engine ID of the gateway")
+		}
+
+		usm := &gosnmp.UsmSecurityParameters{
+			UserName:                 *user,
+			AuthoritativeEngineID:    string(engine),
+			AuthenticationPassphrase: *authPass,
+			PrivacyPassphrase:        *privPass,
+		}
+		flags := gosnmp.NoAuthNoPriv
+		switch *level {
+		case "authPriv":
+			flags = gosnmp.AuthPriv
+			usm.PrivacyProtocol = map[string]gosnmp.SnmpV3PrivProtocol{
+				"DES": gosnmp.DES, "AES": gosnmp.AES, "AES192": gosnmp.AES192, "AES256": gosnmp.AES256,
+			}[*privProto]
+			fallthrough
+		case "authNoPriv":
+			if flags == gosnmp.NoAuthNoPriv {
+				flags = gosnmp.AuthNoPriv
+			}
+			usm.AuthenticationProtocol = map[string]gosnmp.SnmpV3AuthProtocol{
+				"MD5": gosnmp.MD5, "SHA": gosnmp.SHA, "SHA224": gosnmp.SHA224,
+				"SHA256": gosnmp.SHA256, "SHA384": gosnmp.SHA384, "SHA512": gosnmp.SHA512,
+			}[*authProto]
+		}
+
+		listener.Params.Version = gosnmp.Version3
+		listener.Params.SecurityModel = gosnmp.UserSecurityModel
+		listener.Params.MsgFlags = flags
+		listener.Params.SecurityParameters = usm
+	}
+
+	prefix := "." + strings.TrimPrefix(*enterprise, ".")
+	listener.OnNewTrap = func(packet *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+		if packet.Version == gosnmp.Version2c && *community != "" && packet.Community != *community {
+			fmt.Printf("%s dropped trap from %s with community %q\n", time.Now().Format(time.RFC3339), addr, packet.Community)
+			return
+		}
+		printTrap(packet, addr, prefix)
+	}
+
+	fmt.Printf("Receiving traps on %s\n", *listen)
+	if err := listener.Listen(*listen); err != nil {
+		log.Fatalf("Listen failed: %v", err)
+	}
+}
+
+// printTrap prints a trap with its varbinds, naming the gateway MIB objects
+func printTrap(packet *gosnmp.SnmpPacket, addr *net.UDPAddr, prefix string) {
+	fmt.Printf("%s trap from %s (%s)\n", time.Now().Format(time.RFC3339), addr, packet.Version)
+	for _, variable := range packet.Variables {
+		name := variable.Name
+		value := variable.Value
+		if oid, ok := value.(string); ok && variable.Type == gosnmp.ObjectIdentifier {
+			value = resolve(oid, prefix)
+		}
+		if bytes, ok := value.([]byte); ok {
+			value = string(bytes)
+		}
+		fmt.Printf("  %-24s %v\n", resolve(name, prefix), value)
+	}
+}
+
+// resolve names an OID of the gateway MIB
+func resolve(oid, prefix string) string {
+	if suffix, ok := strings.CutPrefix(oid, prefix); ok {
+		if name, ok := names[suffix]; ok {
+			return name
+		}
+	}
+	switch oid {
+	case ".1.3.6.1.2.1.1.3.0":
+		return "sysUpTime.0"
+	case ".1.3.6.1.6.3.1.1.4.1.0":
+		return "snmpTrapOID.0"
+	}
+	return oid
+}
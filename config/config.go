@@ -12,6 +12,13 @@ type Config struct {
 	Web      *Web      `yaml:"web"`
 	Database *Database `yaml:"database"`
 	GenieACS *GenieACS `yaml:"genieacs"`
+	Faults   *Faults   `yaml:"faults,omitempty"`
+	Search   *Search   `yaml:"search,omitempty"`
+	RBAC     *RBAC     `yaml:"rbac,omitempty"`
+	Audit    *Audit    `yaml:"audit,omitempty"`
+	Tenants  []Tenant  `yaml:"tenants,omitempty"`
+
+	Notifications *Notifications `yaml:"notifications,omitempty"`
 }
 
 type Info struct {
@@ -37,6 +44,60 @@ type NBI struct {
 	ReadTimeout  time.Duration `yaml:"readTimeout"`
 	WriteTimeout time.Duration `yaml:"writeTimeout"`
 	TLS          *TLS          `yaml:"tls,omitempty"`
+	Auth         *NBIAuth      `yaml:"auth,omitempty"`
+
+	// AllowedOrigins are the web origins, as https://noc.example.com, whose
+	// pages may open the WebSocket besides pages of the gateway itself
+	AllowedOrigins []string `yaml:"allowedOrigins,omitempty"`
+}
+
+// NBIAuth configures how NBI clients authenticate. Clients send an API key
+// or a JWT as bearer token.
+type NBIAuth struct {
+	Enabled bool `yaml:"enabled"`
+
+	// APIKeyStateFile persists the API keys managed through the NBI
+	APIKeyStateFile string `yaml:"apiKeyStateFile,omitempty"`
+
+	// APIKeys are fixed keys, for instance to bootstrap the first admin key
+	APIKeys []StaticAPIKey `yaml:"apiKeys,omitempty"`
+
+	JWT *JWT `yaml:"jwt,omitempty"`
+}
+
+// StaticAPIKey is an API key given by the hex SHA-256 hash of the key
+type StaticAPIKey struct {
+	Name   string `yaml:"name"`
+	Hash   string `yaml:"hash"`
+	Role   string `yaml:"role,omitempty"`
+	Tenant string `yaml:"tenant,omitempty"`
+}
+
+// JWT configures validation of tokens issued by an external identity
+// provider
+type JWT struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Algorithms accepted, HS256 verifies with Secret, RS256 with the keys
+	// of JWKSFile
+	Algorithms []string `yaml:"algorithms,omitempty"`
+	Secret     string   `yaml:"secret,omitempty"`
+	JWKSFile   string   `yaml:"jwksFile,omitempty"`
+
+	// Issuer and Audience are checked when set
+	Issuer   string `yaml:"issuer,omitempty"`
+	Audience string `yaml:"audience,omitempty"`
+
+	// NameClaim identifies the caller, RolesClaim holds its roles as a
+	// string or a list. Tokens without a known role get DefaultRole.
+	NameClaim   string        `yaml:"nameClaim,omitempty"`
+	RolesClaim  string        `yaml:"rolesClaim,omitempty"`
+	DefaultRole string        `yaml:"defaultRole,omitempty"`
+	Leeway      time.Duration `yaml:"leeway,omitempty"`
+
+	// TenantClaim holds the tenant of the caller. Tokens without it see
+	// across tenants, tokens naming an unknown tenant are refused.
+	TenantClaim string `yaml:"tenantClaim,omitempty"`
 }
 
 type UI struct {
@@ -48,6 +109,103 @@ type UI struct {
 	WriteTimeout time.Duration `yaml:"writeTimeout"`
 	TLS          *TLS          `yaml:"tls,omitempty"`
 	Theme        string        `yaml:"theme"`
+	Auth         *UIAuth       `yaml:"auth,omitempty"`
+
+	// AnonymousRole is the role of UI visitors while login is disabled,
+	// viewer unless set
+	AnonymousRole string `yaml:"anonymousRole,omitempty"`
+
+	// AllowedOrigins are the web origins, as https://noc.example.com, whose
+	// pages may open the WebSocket besides pages of the gateway itself
+	AllowedOrigins []string `yaml:"allowedOrigins,omitempty"`
+}
+
+// UIAuth configures the login of the web UI with local users
+type UIAuth struct {
+	Enabled       bool   `yaml:"enabled"`
+	UserStateFile string `yaml:"userStateFile,omitempty"`
+
+	// Sessions end after IdleTimeout without requests, and AbsoluteTimeout
+	// after login at the latest
+	IdleTimeout     time.Duration `yaml:"idleTimeout,omitempty"`
+	AbsoluteTimeout time.Duration `yaml:"absoluteTimeout,omitempty"`
+
+	// MaxFailedLogins failed logins in a row lock a user for
+	// LockoutDuration
+	MaxFailedLogins int           `yaml:"maxFailedLogins,omitempty"`
+	LockoutDuration time.Duration `yaml:"lockoutDuration,omitempty"`
+
+	// SecureCookie restricts the session cookie to HTTPS. It defaults to
+	// true for the https scheme, set it when TLS ends at a proxy.
+	SecureCookie bool `yaml:"secureCookie,omitempty"`
+
+	OIDC *OIDC `yaml:"oidc,omitempty"`
+}
+
+// OIDC configures single sign-on with an OpenID Connect provider using
+// the authorization code flow with PKCE
+type OIDC struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Name labels the login button
+	Name string `yaml:"name,omitempty"`
+
+	// Issuer is the URL of the provider, its endpoints are discovered from
+	// /.well-known/openid-configuration
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"clientId"`
+	ClientSecret string   `yaml:"clientSecret,omitempty"`
+	RedirectURL  string   `yaml:"redirectUrl"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+
+	// UsernameClaim names the user, GroupsClaim holds its groups as a
+	// string or a list
+	UsernameClaim string `yaml:"usernameClaim,omitempty"`
+	GroupsClaim   string `yaml:"groupsClaim,omitempty"`
+
+	// RoleMappings give users the role and tenant of their first matching
+	// group. Users without one get DefaultRole and DefaultTenant, or are
+	// refused when DefaultRole is empty.
+	RoleMappings  []OIDCRoleMapping `yaml:"roleMappings,omitempty"`
+	DefaultRole   string            `yaml:"defaultRole,omitempty"`
+	DefaultTenant string            `yaml:"defaultTenant,omitempty"`
+}
+
+// OIDCRoleMapping maps a group of the identity provider to a role, and
+// optionally a tenant
+type OIDCRoleMapping struct {
+	Group  string `yaml:"group"`
+	Role   string `yaml:"role"`
+	Tenant string `yaml:"tenant,omitempty"`
+}
+
+// RBAC configures role-based access control of the NBI and UI
+type RBAC struct {
+	// Roles maps role names to permissions. Built-in roles listed here get
+	// the given permissions instead of their defaults.
+	Roles map[string][]string `yaml:"roles,omitempty"`
+}
+
+// Tenant is a customer network, its devices are those matching any of the
+// listed OUIs, tags or provisioning codes
+type Tenant struct {
+	ID                string   `yaml:"id"`
+	Name              string   `yaml:"name,omitempty"`
+	Description       string   `yaml:"description,omitempty"`
+	OUIs              []string `yaml:"ouis,omitempty"`
+	Tags              []string `yaml:"tags,omitempty"`
+	ProvisioningCodes []string `yaml:"provisioningCodes,omitempty"`
+}
+
+// Audit configures the audit log of state-changing NBI and UI requests
+type Audit struct {
+	// File is the append-only, hash-chained log. Empty keeps the log in
+	// memory only.
+	File string `yaml:"file,omitempty"`
+
+	// MemoryRecords is how many of the newest records are kept in memory
+	// for queries
+	MemoryRecords int `yaml:"memoryRecords,omitempty"`
 }
 
 type TLS struct {
@@ -86,4 +244,279 @@ type GenieACS struct {
 	Username string        `yaml:"username,omitempty"`
 	Password string        `yaml:"password,omitempty"`
 	Timeout  time.Duration `yaml:"timeout"`
+
+	// SyncInterval is how often devices and faults are pulled from GenieACS
+	// and alarm rules are evaluated
+	SyncInterval time.Duration `yaml:"syncInterval,omitempty"`
+
+	Heartbeat *Heartbeat `yaml:"heartbeat,omitempty"`
+}
+
+type Heartbeat struct {
+	DefaultInterval time.Duration `yaml:"defaultInterval"`
+	Tolerance       float64       `yaml:"tolerance"`
+	MissedInforms   int           `yaml:"missedInforms"`
+	FaultSeverity   string        `yaml:"faultSeverity"`
+}
+
+type Search struct {
+	Parameters []string `yaml:"parameters,omitempty"`
+}
+
+type Faults struct {
+	StateFile   string            `yaml:"stateFile,omitempty"`
+	Correlation *FaultCorrelation `yaml:"correlation,omitempty"`
+	Escalation  *FaultEscalation  `yaml:"escalation,omitempty"`
+	Rules       []AlarmRule       `yaml:"rules,omitempty"`
+}
+
+type FaultCorrelation struct {
+	Enabled   bool          `yaml:"enabled"`
+	Window    time.Duration `yaml:"window"`
+	MinFaults int           `yaml:"minFaults"`
+	// Retention is how long resolved incidents are kept
+	Retention time.Duration `yaml:"retention,omitempty"`
+}
+
+type FaultEscalation struct {
+	Enabled  bool               `yaml:"enabled"`
+	Interval time.Duration      `yaml:"interval"`
+	Policies []EscalationPolicy `yaml:"policies,omitempty"`
+}
+
+type EscalationPolicy struct {
+	Name       string            `yaml:"name"`
+	Severities []string          `yaml:"severities,omitempty"`
+	Tags       []string          `yaml:"tags,omitempty"`
+	Levels     []EscalationLevel `yaml:"levels"`
+}
+
+type EscalationLevel struct {
+	Level  int           `yaml:"level"`
+	After  time.Duration `yaml:"after"`
+	Notify []string      `yaml:"notify,omitempty"`
+}
+
+type AlarmRule struct {
+	Name           string        `yaml:"name"`
+	Description    string        `yaml:"description,omitempty"`
+	Code           string        `yaml:"code"`
+	Severity       string        `yaml:"severity"`
+	Condition      string        `yaml:"condition"`
+	ClearCondition string        `yaml:"clearCondition,omitempty"`
+	RaiseAfter     time.Duration `yaml:"raiseAfter,omitempty"`
+	ClearAfter     time.Duration `yaml:"clearAfter,omitempty"`
+	Tags           []string      `yaml:"tags,omitempty"`
+	Models         []string      `yaml:"models,omitempty"`
+}
+
+type Notifications struct {
+	Webhooks *Webhooks  `yaml:"webhooks,omitempty"`
+	SNMP     *SNMPTraps `yaml:"snmp,omitempty"`
+	Syslog   *Syslog    `yaml:"syslog,omitempty"`
+	MQTT     *MQTT      `yaml:"mqtt,omitempty"`
+	Email    *Email     `yaml:"email,omitempty"`
+}
+
+type Webhooks struct {
+	Enabled bool `yaml:"enabled"`
+
+	// StateFile persists webhook subscriptions, delivery history is kept in
+	// memory only
+	StateFile string `yaml:"stateFile,omitempty"`
+
+	Timeout        time.Duration `yaml:"timeout,omitempty"`
+	MaxAttempts    int           `yaml:"maxAttempts,omitempty"`
+	InitialBackoff time.Duration `yaml:"initialBackoff,omitempty"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff,omitempty"`
+	Workers        int           `yaml:"workers,omitempty"`
+
+	// DeliveryHistory is the number of deliveries kept per webhook
+	DeliveryHistory int `yaml:"deliveryHistory,omitempty"`
+}
+
+type SNMPTraps struct {
+	Enabled bool `yaml:"enabled"`
+
+	// EnterpriseOID is the root of the NEXTRANET-GATEWAY-MIB, change it
+	// together with the bundled MIB
+	EnterpriseOID string `yaml:"enterpriseOid,omitempty"`
+
+	// EngineID is the hex encoded SNMPv3 engine ID of the gateway, derived
+	// from the host name when empty
+	EngineID string `yaml:"engineId,omitempty"`
+
+	// EngineBootsFile keeps the SNMPv3 snmpEngineBoots counter, which is
+	// incremented on every start so receivers accept the restarted engine
+	// time
+	EngineBootsFile string `yaml:"engineBootsFile,omitempty"`
+
+	// ResyncInterval is how often the active alarms are sent again so the
+	// NMS can reconcile its alarm list
+	ResyncInterval time.Duration `yaml:"resyncInterval,omitempty"`
+
+	Targets []SNMPTarget `yaml:"targets,omitempty"`
+}
+
+type SNMPTarget struct {
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"`
+	Version string `yaml:"version"`
+
+	// Community is used by SNMPv2c targets, User by SNMPv3 targets
+	Community string    `yaml:"community,omitempty"`
+	User      *SNMPUser `yaml:"user,omitempty"`
+
+	// Severities limits the faults sent to the target, empty sends all
+	Severities []string `yaml:"severities,omitempty"`
+}
+
+type SNMPUser struct {
+	Name          string `yaml:"name"`
+	SecurityLevel string `yaml:"securityLevel"`
+	AuthProtocol  string `yaml:"authProtocol,omitempty"`
+	AuthPassword  string `yaml:"authPassword,omitempty"`
+	PrivProtocol  string `yaml:"privProtocol,omitempty"`
+	PrivPassword  string `yaml:"privPassword,omitempty"`
+}
+
+type Syslog struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Network is udp, tcp or tls. TCP and TLS frame messages with octet
+	// counting as in RFC 6587 and RFC 5425.
+	Network string     `yaml:"network,omitempty"`
+	Address string     `yaml:"address"`
+	TLS     *ClientTLS `yaml:"tls,omitempty"`
+
+	// Facility is a syslog facility keyword such as local0
+	Facility string `yaml:"facility,omitempty"`
+	// Hostname and AppName fill the message header, the hostname defaults
+	// to the one of the gateway
+	Hostname string `yaml:"hostname,omitempty"`
+	AppName  string `yaml:"appName,omitempty"`
+	// EnterpriseID is the private enterprise number qualifying the
+	// structured data IDs, as in fault@32473
+	EnterpriseID string `yaml:"enterpriseId,omitempty"`
+
+	// Events selects the forwarded event types or categories
+	Events []string `yaml:"events,omitempty"`
+	// Audit forwards the operator actions of the audit log, such as
+	// reboots, parameter changes and user and API key management
+	Audit bool `yaml:"audit,omitempty"`
+	// AuditFacility is the facility of logins and of user and API key
+	// management, other operator actions use Facility
+	AuditFacility string `yaml:"auditFacility,omitempty"`
+
+	// BufferSize is the number of messages held while the collector is
+	// unreachable, the oldest are dropped beyond it
+	BufferSize          int           `yaml:"bufferSize,omitempty"`
+	WriteTimeout        time.Duration `yaml:"writeTimeout,omitempty"`
+	ReconnectBackoff    time.Duration `yaml:"reconnectBackoff,omitempty"`
+	MaxReconnectBackoff time.Duration `yaml:"maxReconnectBackoff,omitempty"`
+}
+
+// ClientTLS configures TLS connections the gateway opens to notification
+// servers
+type ClientTLS struct {
+	// CA verifies the server, the system roots are used when empty
+	CA string `yaml:"ca,omitempty"`
+	// Cert and Key authenticate the gateway to collectors requiring it
+	Cert               string `yaml:"cert,omitempty"`
+	Key                string `yaml:"key,omitempty"`
+	ServerName         string `yaml:"serverName,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
+}
+
+type MQTT struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Broker is a URL such as tcp://host:1883, ssl://host:8883 or
+	// wss://host/mqtt
+	Broker   string     `yaml:"broker"`
+	ClientID string     `yaml:"clientId,omitempty"`
+	Username string     `yaml:"username,omitempty"`
+	Password string     `yaml:"password,omitempty"`
+	TLS      *ClientTLS `yaml:"tls,omitempty"`
+
+	// TopicPrefix is the first level of every topic, as in
+	// cplane/faults/critical
+	TopicPrefix string `yaml:"topicPrefix,omitempty"`
+	QoS         byte   `yaml:"qos,omitempty"`
+
+	// Events selects the published event types or categories
+	Events []string `yaml:"events,omitempty"`
+
+	// QueueSize is the number of messages held while the broker is
+	// unreachable, the oldest are dropped beyond it
+	QueueSize            int           `yaml:"queueSize,omitempty"`
+	KeepAlive            time.Duration `yaml:"keepAlive,omitempty"`
+	ConnectTimeout       time.Duration `yaml:"connectTimeout,omitempty"`
+	PublishTimeout       time.Duration `yaml:"publishTimeout,omitempty"`
+	MaxReconnectInterval time.Duration `yaml:"maxReconnectInterval,omitempty"`
+}
+
+type Email struct {
+	Enabled bool `yaml:"enabled"`
+
+	SMTP *SMTP  `yaml:"smtp"`
+	From string `yaml:"from"`
+
+	// MaxPerHour limits the emails sent to each recipient, alerts held back
+	// are sent together once the limit allows
+	MaxPerHour int `yaml:"maxPerHour,omitempty"`
+
+	Recipients []EmailRecipient `yaml:"recipients,omitempty"`
+	Digests    []EmailDigest    `yaml:"digests,omitempty"`
+
+	// Templates replace the built-in templates, keyed by alert or by digest
+	// type. They are Go text templates.
+	Templates map[string]EmailTemplate `yaml:"templates,omitempty"`
+}
+
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+
+	// Security is starttls, tls for implicit TLS or none
+	Security string        `yaml:"security,omitempty"`
+	TLS      *ClientTLS    `yaml:"tls,omitempty"`
+	Timeout  time.Duration `yaml:"timeout,omitempty"`
+}
+
+type EmailRecipient struct {
+	// Name is also the group named by escalation levels, a recipient gets
+	// the escalations that notify it
+	Name string   `yaml:"name"`
+	To   []string `yaml:"to"`
+
+	// Severities of the faults alerted immediately, none when empty
+	Severities []string `yaml:"severities,omitempty"`
+	// Tags limits alerts and digests to devices with one of the tags
+	Tags []string `yaml:"tags,omitempty"`
+	// Digests lists the digest types sent to the recipient
+	Digests []string `yaml:"digests,omitempty"`
+}
+
+type EmailDigest struct {
+	// Type is offline-devices, unacknowledged-faults or firmware-compliance
+	Type string `yaml:"type"`
+	// At is the local time of day the digest is sent, as 08:00
+	At string `yaml:"at,omitempty"`
+
+	// Firmware lists the approved software versions per model, for the
+	// firmware-compliance digest
+	Firmware []FirmwareBaseline `yaml:"firmware,omitempty"`
+}
+
+type FirmwareBaseline struct {
+	Model    string   `yaml:"model"`
+	Versions []string `yaml:"versions"`
+}
+
+type EmailTemplate struct {
+	Subject string `yaml:"subject"`
+	Body    string `yaml:"body"`
 }
@@ -0,0 +1,237 @@
+// Package audit records the mutating requests of the NBI and UI in the
+// audit log. The middleware records every such request; handlers add the
+// targets and changed values only they know.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// gin context keys of the details handlers add to the record
+const (
+	targetsKey = "auditTargets"
+	changesKey = "auditChanges"
+	actorKey   = "auditActor"
+)
+
+// maxErrorBody bounds the response read for the error of a failed request
+const maxErrorBody = 4096
+
+// AddTargets records objects a request acts on besides those named in its
+// route, such as the devices of a bulk operation
+func AddTargets(c *gin.Context, targets ...string) {
+	existing, _ := c.Get(targetsKey)
+	list, _ := existing.([]string)
+	c.Set(targetsKey, append(list, targets...))
+}
+
+// RecordChange records a value changed by a request. A nil before records
+// that the previous value is not known.
+func RecordChange(c *gin.Context, target, field string, before, after interface{}) {
+	change := models.AuditChange{
+		Target: target,
+		Field:  field,
+		Before: rawValue(before),
+		After:  rawValue(after),
+	}
+
+	existing, _ := c.Get(changesKey)
+	list, _ := existing.([]models.AuditChange)
+	c.Set(changesKey, append(list, change))
+}
+
+// RecordParameterChanges records new parameter values of a device with the
+// values last reported by the device, where the gateway has them
+func RecordParameterChanges(c *gin.Context, appContext *context.Context, deviceID string, parameters map[string]interface{}) {
+	device, _ := appContext.GetDevice(deviceID)
+
+	paths := make([]string, 0, len(parameters))
+	for path := range parameters {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		var before interface{}
+		if device != nil {
+			if param, ok := device.Parameters[path]; ok {
+				before = param.Value
+			}
+		}
+		RecordChange(c, deviceID, path, before, parameters[path])
+	}
+}
+
+// SetActor names the actor of a request that has no principal yet, such as
+// a login
+func SetActor(c *gin.Context, name string) {
+	c.Set(actorKey, name)
+}
+
+// Middleware writes an audit record for each state-changing request once it
+// is handled, including requests that fail or are denied. It goes before
+// the authentication middleware, so rejected credentials are recorded too.
+func Middleware(appContext *context.Context, source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		requestID := c.GetString("requestID")
+		if requestID == "" {
+			requestID = c.GetHeader("X-Request-ID")
+		}
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Set("requestID", requestID)
+		c.Writer.Header().Set("X-Request-ID", requestID)
+
+		writer := &errorCapture{ResponseWriter: c.Writer}
+		c.Writer = writer
+		started := time.Now()
+
+		c.Next()
+
+		// Requests for unknown routes change nothing
+		if c.FullPath() == "" {
+			return
+		}
+
+		status := c.Writer.Status()
+		record := &models.AuditRecord{
+			Timestamp: started,
+			ClientIP:  c.ClientIP(),
+			RequestID: requestID,
+			Source:    source,
+			Action:    c.Request.Method + " " + c.FullPath(),
+			Targets:   targets(c),
+			Status:    status,
+			Outcome:   Outcome(status),
+		}
+		record.Actor, record.AuthMethod = actor(c)
+		if changes, ok := c.Get(changesKey); ok {
+			record.Changes, _ = changes.([]models.AuditChange)
+		}
+		if status >= http.StatusBadRequest {
+			record.Error = writer.errorMessage()
+		}
+
+		if err := appContext.AppendAuditRecord(record); err != nil {
+			logger.AuditLog.Errorf("Failed to record %s by %s: %v", record.Action, record.Actor, err)
+		}
+	}
+}
+
+// actor returns the name and authentication method of the caller
+func actor(c *gin.Context) (string, string) {
+	if principal, ok := auth.GetPrincipal(c); ok {
+		if principal.IsAnonymous() {
+			return "anonymous", principal.Method
+		}
+		return principal.Name, principal.Method
+	}
+	if name := c.GetString(actorKey); name != "" {
+		return name, ""
+	}
+	return "anonymous", ""
+}
+
+// targets returns the route parameters followed by the targets added by
+// the handler, without duplicates
+func targets(c *gin.Context) []string {
+	seen := make(map[string]bool)
+	var list []string
+	add := func(target string) {
+		if target != "" && !seen[target] {
+			seen[target] = true
+			list = append(list, target)
+		}
+	}
+
+	for _, param := range c.Params {
+		add(param.Value)
+	}
+	if added, ok := c.Get(targetsKey); ok {
+		extra, _ := added.([]string)
+		for _, target := range extra {
+			add(target)
+		}
+	}
+	return list
+}
+
+// Outcome classifies the status of a response
+func Outcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return models.AuditOutcomeDenied
+	case status >= http.StatusBadRequest:
+		return models.AuditOutcomeFailure
+	default:
+		return models.AuditOutcomeSuccess
+	}
+}
+
+// rawValue encodes a changed value, nil stays empty
+func rawValue(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// errorCapture keeps the start of the response body, failed requests
+// record the error message of their JSON response
+type errorCapture struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *errorCapture) Write(data []byte) (int, error) {
+	if remaining := maxErrorBody - w.body.Len(); remaining > 0 {
+		if len(data) < remaining {
+			remaining = len(data)
+		}
+		w.body.Write(data[:remaining])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *errorCapture) WriteString(s string) (int, error) {
+	if remaining := maxErrorBody - w.body.Len(); remaining > 0 {
+		if len(s) < remaining {
+			remaining = len(s)
+		}
+		w.body.WriteString(s[:remaining])
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// errorMessage returns the error field of a JSON error response
+func (w *errorCapture) errorMessage() string {
+	var response struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(w.body.Bytes(), &response) != nil {
+		return ""
+	}
+	return response.Error
+}
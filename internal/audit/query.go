@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// ParseFilter reads an audit filter from the query parameters actor,
+// action, target, outcome, source, from and to. Times are RFC 3339.
+func ParseFilter(c *gin.Context) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Actor:   c.Query("actor"),
+		Action:  c.Query("action"),
+		Target:  c.Query("target"),
+		Outcome: c.Query("outcome"),
+		Source:  c.Query("source"),
+	}
+
+	for name, field := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if s := c.Query(name); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return filter, fmt.Errorf("Invalid %s timestamp, expected RFC 3339", name)
+			}
+			*field = t
+		}
+	}
+	return filter, nil
+}
+
+// WriteCSV writes records as CSV with a header row. Changes are written as
+// target:field=before->after, separated by semicolons.
+func WriteCSV(w io.Writer, records []*models.AuditRecord) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"Seq", "Timestamp", "Actor", "Auth Method", "Client IP", "Request ID",
+		"Source", "Action", "Targets", "Changes", "Status", "Outcome",
+		"Error", "Hash",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		changes := make([]string, 0, len(record.Changes))
+		for _, change := range record.Changes {
+			before := string(change.Before)
+			if before == "" {
+				before = "?"
+			}
+			changes = append(changes, fmt.Sprintf("%s:%s=%s->%s", change.Target, change.Field, before, string(change.After)))
+		}
+
+		row := []string{
+			strconv.FormatUint(record.Seq, 10),
+			record.Timestamp.Format(time.RFC3339),
+			record.Actor,
+			record.AuthMethod,
+			record.ClientIP,
+			record.RequestID,
+			record.Source,
+			record.Action,
+			strings.Join(record.Targets, ";"),
+			strings.Join(changes, ";"),
+			strconv.Itoa(record.Status),
+			record.Outcome,
+			record.Error,
+			record.Hash,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
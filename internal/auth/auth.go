@@ -0,0 +1,153 @@
+// Package auth carries the principal of NBI and UI requests through the gin
+// context and enforces the permissions and tenant of their routes.
+// Authentication itself is done by the middleware of each interface.
+package auth
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// principalKey is the gin context key of the authenticated caller
+const principalKey = "principal"
+
+// SetPrincipal records the caller of a request
+func SetPrincipal(c *gin.Context, principal *models.Principal) {
+	c.Set(principalKey, principal)
+}
+
+// GetPrincipal returns the caller of a request
+func GetPrincipal(c *gin.Context) (*models.Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*models.Principal)
+	return principal, ok
+}
+
+// Can reports whether the caller of a request has a permission
+func Can(c *gin.Context, permission string) bool {
+	principal, ok := GetPrincipal(c)
+	return ok && principal.Can(permission)
+}
+
+// ActorName returns the name recorded for a change: the authenticated
+// caller, or the name given in the request for anonymous callers
+func ActorName(c *gin.Context, given string) string {
+	if principal, ok := GetPrincipal(c); ok && !principal.IsAnonymous() {
+		return principal.Name
+	}
+	return given
+}
+
+// RequirePermission rejects requests whose caller lacks the permission.
+// Requests without a principal are rejected too, so a route is never open
+// because its authentication middleware is missing.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Can(c, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Permission denied, " + permission + " required",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Tenant returns the tenant the caller is limited to, empty for callers
+// that see across tenants
+func Tenant(c *gin.Context) string {
+	if principal, ok := GetPrincipal(c); ok {
+		return principal.Tenant
+	}
+	return ""
+}
+
+// RequireAllTenants rejects callers limited to a tenant. It guards the
+// resources that are not scoped to tenants, such as the audit log, which
+// would show them the other tenants.
+func RequireAllTenants() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if Tenant(c) != "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Permission denied, not available to callers limited to a tenant",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CanAssignTenant reports whether the caller may give a user or API key the
+// tenant. Callers limited to a tenant may only assign their own.
+func CanAssignTenant(c *gin.Context, tenant string) bool {
+	own := Tenant(c)
+	return own == "" || own == tenant
+}
+
+// RequireDeviceAccess rejects requests for a device outside the tenant of
+// the caller as if the device did not exist. Routes without a deviceId
+// parameter pass.
+func RequireDeviceAccess(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceID := c.Param("deviceId")
+		if deviceID != "" && !appContext.DeviceInTenant(Tenant(c), deviceID) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error": "Device not found",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireFaultAccess rejects requests for a fault of a device outside the
+// tenant of the caller as if the fault did not exist
+func RequireFaultAccess(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := Tenant(c)
+		if faultID := c.Param("faultId"); faultID != "" && tenant != "" {
+			fault, exists := appContext.GetFault(faultID)
+			if !exists || !appContext.DeviceInTenant(tenant, fault.DeviceID) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+					"error": "Fault not found",
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireIncidentAccess rejects requests for an incident that affects no
+// device of the tenant of the caller as if the incident did not exist
+func RequireIncidentAccess(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := Tenant(c)
+		if incidentID := c.Param("incidentId"); incidentID != "" && tenant != "" {
+			incident, exists := appContext.GetIncident(incidentID)
+			if !exists || len(appContext.FilterTenantIncidents(tenant, []*models.Incident{incident})) == 0 {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+					"error": "Incident not found",
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequestTenant returns the tenant queries of a request are scoped to: the
+// tenant of the caller, or for callers that see across tenants the tenant
+// query parameter, which may be empty
+func RequestTenant(c *gin.Context) string {
+	if tenant := Tenant(c); tenant != "" {
+		return tenant
+	}
+	return c.Query("tenant")
+}
@@ -0,0 +1,136 @@
+package context
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/rules"
+)
+
+// alarmRuleTagPrefix tags gateway-rule faults with the rule that raised them
+const alarmRuleTagPrefix = "rule:"
+
+// Alarm Rule Functions
+
+// AddAlarmRule stores a new alarm rule and assigns its ID
+func (c *Context) AddAlarmRule(rule *models.AlarmRule) error {
+	if err := validateAlarmRule(rule); err != nil {
+		return err
+	}
+
+	c.alarmRulesMutex.Lock()
+	defer c.alarmRulesMutex.Unlock()
+
+	now := time.Now()
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	c.alarmRules[rule.ID] = rule
+	return nil
+}
+
+// UpdateAlarmRule replaces an existing alarm rule
+func (c *Context) UpdateAlarmRule(rule *models.AlarmRule) error {
+	if err := validateAlarmRule(rule); err != nil {
+		return err
+	}
+
+	c.alarmRulesMutex.Lock()
+	defer c.alarmRulesMutex.Unlock()
+
+	existing, exists := c.alarmRules[rule.ID]
+	if !exists {
+		return models.ErrAlarmRuleNotFound
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	c.alarmRules[rule.ID] = rule
+	return nil
+}
+
+// RemoveAlarmRule deletes an alarm rule. Faults it raised are cleared on the
+// next rule evaluation.
+func (c *Context) RemoveAlarmRule(ruleID string) error {
+	c.alarmRulesMutex.Lock()
+	defer c.alarmRulesMutex.Unlock()
+
+	if _, exists := c.alarmRules[ruleID]; !exists {
+		return models.ErrAlarmRuleNotFound
+	}
+	delete(c.alarmRules, ruleID)
+	return nil
+}
+
+// GetAlarmRule retrieves an alarm rule by ID
+func (c *Context) GetAlarmRule(ruleID string) (*models.AlarmRule, bool) {
+	c.alarmRulesMutex.RLock()
+	defer c.alarmRulesMutex.RUnlock()
+	rule, exists := c.alarmRules[ruleID]
+	return rule, exists
+}
+
+// GetAlarmRules returns all alarm rules sorted by name
+func (c *Context) GetAlarmRules() []*models.AlarmRule {
+	c.alarmRulesMutex.RLock()
+	defer c.alarmRulesMutex.RUnlock()
+
+	alarmRules := make([]*models.AlarmRule, 0, len(c.alarmRules))
+	for _, rule := range c.alarmRules {
+		alarmRules = append(alarmRules, rule)
+	}
+
+	sort.Slice(alarmRules, func(i, j int) bool {
+		return alarmRules[i].Name < alarmRules[j].Name
+	})
+	return alarmRules
+}
+
+// GetOpenRuleFaults returns the open gateway-rule faults keyed by the ID of
+// the rule that raised them, used to restore alarm state after a restart
+func (c *Context) GetOpenRuleFaults() map[string][]*models.Fault {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	open := make(map[string][]*models.Fault)
+	for _, fault := range c.faults {
+		if fault.Channel != models.FaultChannelGatewayRule || !isOpenFault(fault) {
+			continue
+		}
+		if ruleID := AlarmRuleID(fault); ruleID != "" {
+			open[ruleID] = append(open[ruleID], fault)
+		}
+	}
+	return open
+}
+
+// AlarmRuleID returns the ID of the rule that raised a gateway-rule fault
+func AlarmRuleID(fault *models.Fault) string {
+	for _, tag := range fault.Tags {
+		if strings.HasPrefix(tag, alarmRuleTagPrefix) {
+			return strings.TrimPrefix(tag, alarmRuleTagPrefix)
+		}
+	}
+	return ""
+}
+
+// AlarmRuleTag returns the fault tag that links a fault to its rule
+func AlarmRuleTag(ruleID string) string {
+	return alarmRuleTagPrefix + ruleID
+}
+
+// validateAlarmRule checks the rule fields and compiles its expressions
+func validateAlarmRule(rule *models.AlarmRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if err := rules.Validate(rule); err != nil {
+		return models.ValidationErrors{Errors: []models.ValidationError{{Field: "condition", Message: err.Error()}}}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package context
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// apiKeyPrefixLength is the part of a key kept in clear to recognise it
+const apiKeyPrefixLength = len(models.APIKeyPrefix) + 8
+
+// API Key Functions
+
+// AddAPIKey generates a key, stores its hash and returns the key. The key
+// cannot be recovered later.
+func (c *Context) AddAPIKey(key *models.APIKey) (string, error) {
+	if err := key.Validate(); err != nil {
+		return "", err
+	}
+	if !c.IsValidRole(key.Role) {
+		return "", models.ValidationErrors{Errors: []models.ValidationError{{Field: "role", Message: "unknown role " + key.Role}}}
+	}
+	if err := c.validateTenant(key.Tenant); err != nil {
+		return "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := models.APIKeyPrefix + hex.EncodeToString(secret)
+
+	c.apiKeysMutex.Lock()
+	defer c.apiKeysMutex.Unlock()
+
+	key.ID = uuid.New().String()
+	key.Prefix = token[:apiKeyPrefixLength]
+	key.Hash = HashAPIKey(token)
+	key.LastUsedAt = nil
+	key.CreatedAt = time.Now()
+	c.apiKeys[key.ID] = key
+	c.apiKeysByHash[key.Hash] = key.ID
+	c.saveAPIKeyState()
+	return token, nil
+}
+
+// RemoveAPIKey revokes an API key
+func (c *Context) RemoveAPIKey(keyID string) error {
+	c.apiKeysMutex.Lock()
+	defer c.apiKeysMutex.Unlock()
+
+	key, exists := c.apiKeys[keyID]
+	if !exists {
+		return models.ErrAPIKeyNotFound
+	}
+	delete(c.apiKeys, keyID)
+	delete(c.apiKeysByHash, key.Hash)
+	c.saveAPIKeyState()
+	return nil
+}
+
+// GetAPIKey retrieves an API key by ID. Stored keys are replaced, never
+// modified, so the result may be read without holding a lock.
+func (c *Context) GetAPIKey(keyID string) (*models.APIKey, bool) {
+	c.apiKeysMutex.RLock()
+	defer c.apiKeysMutex.RUnlock()
+	key, exists := c.apiKeys[keyID]
+	return key, exists
+}
+
+// GetAPIKeys returns all API keys, oldest first
+func (c *Context) GetAPIKeys() []*models.APIKey {
+	c.apiKeysMutex.RLock()
+	defer c.apiKeysMutex.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(c.apiKeys))
+	for _, key := range c.apiKeys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys
+}
+
+// AuthenticateAPIKey looks up the stored key matching token and records its
+// use. The time of last use is saved with the next change to the keys
+// rather than on every request.
+func (c *Context) AuthenticateAPIKey(token string) (*models.APIKey, error) {
+	hash := HashAPIKey(token)
+	now := time.Now()
+
+	c.apiKeysMutex.Lock()
+	defer c.apiKeysMutex.Unlock()
+
+	keyID, exists := c.apiKeysByHash[hash]
+	if !exists {
+		return nil, models.ErrAPIKeyNotFound
+	}
+	key := c.apiKeys[keyID]
+	if key.IsExpired(now) {
+		return nil, models.ErrAPIKeyExpired
+	}
+
+	used := *key
+	used.LastUsedAt = &now
+	c.apiKeys[keyID] = &used
+	return &used, nil
+}
+
+// HashAPIKey returns the hex SHA-256 of an API key. Keys are random 256 bit
+// values, so an unsalted fast hash is enough.
+func HashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// API Key Persistence Functions
+
+// SetAPIKeyStateFile sets the file used to persist API keys. An empty path
+// disables persistence.
+func (c *Context) SetAPIKeyStateFile(path string) {
+	c.apiKeysMutex.Lock()
+	defer c.apiKeysMutex.Unlock()
+	c.apiKeyStateFile = path
+}
+
+// LoadAPIKeyState restores the API keys saved by a previous run
+func (c *Context) LoadAPIKeyState() error {
+	c.apiKeysMutex.Lock()
+	defer c.apiKeysMutex.Unlock()
+
+	if c.apiKeyStateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.apiKeyStateFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var keys []*models.APIKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		c.apiKeys[key.ID] = key
+		c.apiKeysByHash[key.Hash] = key.ID
+	}
+
+	logger.ContextLog.Infof("Loaded %d API keys from %s", len(keys), c.apiKeyStateFile)
+	return nil
+}
+
+// saveAPIKeyState writes all API keys with their hashes to the state file.
+// Must be called with apiKeysMutex held.
+func (c *Context) saveAPIKeyState() {
+	if c.apiKeyStateFile == "" {
+		return
+	}
+
+	keys := make([]*models.APIKey, 0, len(c.apiKeys))
+	for _, key := range c.apiKeys {
+		keys = append(keys, key)
+	}
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		logger.ContextLog.Errorf("Failed to encode API key state: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.apiKeyStateFile), 0o755); err != nil {
+		logger.ContextLog.Errorf("Failed to create API key state directory: %v", err)
+		return
+	}
+
+	tmp := c.apiKeyStateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		logger.ContextLog.Errorf("Failed to write API key state: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.apiKeyStateFile); err != nil {
+		logger.ContextLog.Errorf("Failed to replace API key state: %v", err)
+	}
+}
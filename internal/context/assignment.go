@@ -0,0 +1,129 @@
+package context
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Fault Ownership Functions
+
+// AssignFault sets the owner of a fault. An empty assignee clears it.
+func (c *Context) AssignFault(faultID, assignee, assignedBy string) error {
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+
+	fault, exists := c.faults[faultID]
+	if !exists {
+		return models.ErrFaultNotFound
+	}
+
+	if assignee == "" {
+		fault.Assignee = ""
+		fault.AssignedBy = ""
+		fault.AssignedAt = nil
+	} else {
+		now := time.Now()
+		fault.Assignee = assignee
+		fault.AssignedBy = assignedBy
+		fault.AssignedAt = &now
+	}
+
+	c.saveFaultState()
+	return nil
+}
+
+// AddFaultNote appends a note to the thread of a fault
+func (c *Context) AddFaultNote(faultID, author, text, replyTo string) (*models.FaultNote, error) {
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+
+	fault, exists := c.faults[faultID]
+	if !exists {
+		return nil, models.ErrFaultNotFound
+	}
+
+	if replyTo != "" {
+		found := false
+		for _, note := range fault.Notes {
+			if note.ID == replyTo {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, models.ErrFaultNoteNotFound
+		}
+	}
+
+	note := models.FaultNote{
+		ID:        uuid.New().String(),
+		Author:    author,
+		Text:      text,
+		ReplyTo:   replyTo,
+		Timestamp: time.Now(),
+	}
+	fault.Notes = append(fault.Notes, note)
+
+	c.saveFaultState()
+	return &note, nil
+}
+
+// GetFaultNotes returns the note thread of a fault
+func (c *Context) GetFaultNotes(faultID string) ([]models.FaultNote, error) {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	fault, exists := c.faults[faultID]
+	if !exists {
+		return nil, models.ErrFaultNotFound
+	}
+
+	notes := make([]models.FaultNote, len(fault.Notes))
+	copy(notes, fault.Notes)
+	return notes, nil
+}
+
+// GetAssignedFaults returns the open faults owned by an assignee, most recent
+// first. The comparison is case-insensitive.
+func (c *Context) GetAssignedFaults(assignee string) []*models.Fault {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	assigned := make([]*models.Fault, 0)
+	for _, fault := range c.faults {
+		if isOpenFault(fault) && strings.EqualFold(fault.Assignee, assignee) {
+			assigned = append(assigned, fault)
+		}
+	}
+
+	sort.Slice(assigned, func(i, j int) bool {
+		return assigned[i].Timestamp.After(assigned[j].Timestamp)
+	})
+	return assigned
+}
+
+// copyFaultLifecycle carries operator state from a known fault over to a
+// fresh copy reported by GenieACS
+func copyFaultLifecycle(dst, src *models.Fault) {
+	dst.Status = src.Status
+	dst.AcknowledgedBy = src.AcknowledgedBy
+	dst.AcknowledgedAt = src.AcknowledgedAt
+	dst.ResolvedBy = src.ResolvedBy
+	dst.ResolvedAt = src.ResolvedAt
+	dst.IncidentID = src.IncidentID
+	dst.Suppressed = src.Suppressed
+	dst.MaintenanceWindowID = src.MaintenanceWindowID
+	dst.Assignee = src.Assignee
+	dst.AssignedBy = src.AssignedBy
+	dst.AssignedAt = src.AssignedAt
+	dst.Notes = src.Notes
+	dst.ResolutionCategory = src.ResolutionCategory
+	dst.Resolution = src.Resolution
+	dst.EscalationLevel = src.EscalationLevel
+	dst.EscalationPolicyID = src.EscalationPolicyID
+	dst.EscalatedAt = src.EscalatedAt
+}
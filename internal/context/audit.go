@@ -0,0 +1,295 @@
+package context
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// DefaultAuditMemoryRecords is how many of the newest audit records are
+// kept in memory for queries. The log file keeps all of them.
+const DefaultAuditMemoryRecords = 100000
+
+// maxAuditLineSize bounds a record in the audit log file
+const maxAuditLineSize = 1 << 20
+
+// Audit Log Functions
+
+// AppendAuditRecord chains a record to the audit log. The record is written
+// to the log file before it becomes visible, and is dropped when that
+// fails, so the file never misses a record that was reported.
+func (c *Context) AppendAuditRecord(record *models.AuditRecord) error {
+	c.auditMutex.Lock()
+	defer c.auditMutex.Unlock()
+
+	record.Seq = c.auditSeq + 1
+	record.Timestamp = record.Timestamp.UTC()
+	record.PrevHash = c.auditLastHash
+	hash, err := auditHash(record)
+	if err != nil {
+		return err
+	}
+	record.Hash = hash
+
+	if c.auditLogFile != "" {
+		if err := c.writeAuditRecord(record); err != nil {
+			return err
+		}
+	}
+
+	c.auditSeq = record.Seq
+	c.auditLastHash = record.Hash
+	c.auditRecords = append(c.auditRecords, record)
+	if len(c.auditRecords) > c.auditMemoryRecords {
+		c.auditRecords = c.auditRecords[len(c.auditRecords)-c.auditMemoryRecords:]
+	}
+	c.notifyAuditWatchers(record)
+	return nil
+}
+
+// WatchAudit subscribes to new audit records, in the order they were
+// chained. Audit records are not published on the event bus, which any
+// viewer can stream. A watcher that falls more than buffer records behind
+// is dropped and its channel closed. cancel ends the subscription.
+func (c *Context) WatchAudit(buffer int) (records <-chan *models.AuditRecord, cancel func()) {
+	c.auditMutex.Lock()
+	defer c.auditMutex.Unlock()
+
+	c.nextAuditWatcherID++
+	id := c.nextAuditWatcherID
+	watch := make(chan *models.AuditRecord, buffer)
+	c.auditWatchers[id] = watch
+
+	cancel = func() {
+		c.auditMutex.Lock()
+		defer c.auditMutex.Unlock()
+		if _, active := c.auditWatchers[id]; active {
+			delete(c.auditWatchers, id)
+			close(watch)
+		}
+	}
+	return watch, cancel
+}
+
+// notifyAuditWatchers hands a chained record to the watchers. Must be
+// called with auditMutex held.
+func (c *Context) notifyAuditWatchers(record *models.AuditRecord) {
+	for id, watch := range c.auditWatchers {
+		select {
+		case watch <- record:
+		default:
+			logger.AuditLog.Warnf("Audit watcher %d fell behind, dropping it", id)
+			delete(c.auditWatchers, id)
+			close(watch)
+		}
+	}
+}
+
+// GetAuditRecords returns the records in memory that pass the filter,
+// newest first
+func (c *Context) GetAuditRecords(filter models.AuditFilter) []*models.AuditRecord {
+	c.auditMutex.RLock()
+	defer c.auditMutex.RUnlock()
+
+	records := make([]*models.AuditRecord, 0)
+	for i := len(c.auditRecords) - 1; i >= 0; i-- {
+		if filter.Matches(c.auditRecords[i]) {
+			records = append(records, c.auditRecords[i])
+		}
+	}
+	return records
+}
+
+// VerifyAuditLog checks the hash chain of the whole log file, or of the
+// records in memory when there is no file
+func (c *Context) VerifyAuditLog() (*models.AuditVerification, error) {
+	c.auditMutex.RLock()
+	defer c.auditMutex.RUnlock()
+
+	verification := &models.AuditVerification{Valid: true}
+	if c.auditLogFile == "" {
+		prevHash := ""
+		if len(c.auditRecords) > 0 {
+			prevHash = c.auditRecords[0].PrevHash
+		}
+		for _, record := range c.auditRecords {
+			if err := checkAuditRecord(record, prevHash); err != nil {
+				verification.Valid = false
+				verification.BrokenAt = record.Seq
+				verification.Error = err.Error()
+				break
+			}
+			prevHash = record.Hash
+			verification.Records++
+		}
+		return verification, nil
+	}
+
+	err := readAuditLog(c.auditLogFile, func(record *models.AuditRecord, prevHash string) error {
+		if err := checkAuditRecord(record, prevHash); err != nil {
+			verification.Valid = false
+			verification.BrokenAt = record.Seq
+			verification.Error = err.Error()
+			return errStopAuditRead
+		}
+		verification.Records++
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopAuditRead) {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// Audit Persistence Functions
+
+// SetAuditLog sets the append-only file of the audit log and how many
+// records are kept in memory. An empty path keeps the log in memory only.
+func (c *Context) SetAuditLog(path string, memoryRecords int) {
+	c.auditMutex.Lock()
+	defer c.auditMutex.Unlock()
+
+	c.auditLogFile = path
+	if memoryRecords > 0 {
+		c.auditMemoryRecords = memoryRecords
+	}
+}
+
+// LoadAuditLog reads the records of a previous run, so new records continue
+// its chain. A broken chain is reported but does not stop the gateway, the
+// log stays as evidence.
+func (c *Context) LoadAuditLog() error {
+	c.auditMutex.Lock()
+	defer c.auditMutex.Unlock()
+
+	if c.auditLogFile == "" {
+		return nil
+	}
+
+	records := make([]*models.AuditRecord, 0)
+	broken := false
+	err := readAuditLog(c.auditLogFile, func(record *models.AuditRecord, prevHash string) error {
+		if !broken {
+			if err := checkAuditRecord(record, prevHash); err != nil {
+				logger.AuditLog.Errorf("Audit log %s is broken at record %d: %v", c.auditLogFile, record.Seq, err)
+				broken = true
+			}
+		}
+		records = append(records, record)
+		if len(records) > c.auditMemoryRecords {
+			records = records[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	c.auditRecords = records
+	if len(records) > 0 {
+		last := records[len(records)-1]
+		c.auditSeq = last.Seq
+		c.auditLastHash = last.Hash
+	}
+
+	logger.AuditLog.Infof("Loaded %d audit records from %s", c.auditSeq, c.auditLogFile)
+	return nil
+}
+
+// writeAuditRecord appends a record to the log file and syncs it. Must be
+// called with auditMutex held.
+func (c *Context) writeAuditRecord(record *models.AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.auditLogFile), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(c.auditLogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// errStopAuditRead ends reading the audit log early
+var errStopAuditRead = errors.New("stop reading audit log")
+
+// readAuditLog calls fn for each record of a log file with the hash of the
+// record before it
+func readAuditLog(path string, fn func(record *models.AuditRecord, prevHash string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxAuditLineSize)
+
+	prevHash := ""
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := &models.AuditRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := fn(record, prevHash); err != nil {
+			return err
+		}
+		prevHash = record.Hash
+	}
+	return scanner.Err()
+}
+
+// checkAuditRecord verifies that a record follows its predecessor and
+// matches its hash
+func checkAuditRecord(record *models.AuditRecord, prevHash string) error {
+	if record.PrevHash != prevHash {
+		return errors.New("previous hash does not match")
+	}
+	hash, err := auditHash(record)
+	if err != nil {
+		return err
+	}
+	if hash != record.Hash {
+		return errors.New("hash does not match")
+	}
+	return nil
+}
+
+// auditHash returns the hex SHA-256 of a record without its own hash. The
+// previous hash is part of the record, which chains them.
+func auditHash(record *models.AuditRecord) (string, error) {
+	unhashed := *record
+	unhashed.Hash = ""
+	data, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,153 @@
+package context
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+var (
+	manufacturers = []string{"Nokia", "Huawei", "ZTE", "Sercomm", "Askey"}
+	modelNames    = []string{"G-240W", "HG8245", "F670L", "FG1000", "RTL960", "SCE4255W", "AP3915", "NR5103"}
+	tagNames      = []string{"pilot", "smallcell", "enterprise", "residential", "rollout-q3"}
+)
+
+// fleetSizes are the fleet sizes every store benchmark runs at
+var fleetSizes = []int{1000, 10000, 50000}
+
+// faultEvery raises a fault on every Nth device of a synthetic fleet
+const faultEvery = 20
+
+// fleets caches populated contexts by size, building one takes longer than
+// most benchmarks
+var fleets = make(map[int]*Context)
+
+// fleet returns a context holding a synthetic fleet of the given size
+func fleet(size int) *Context {
+	if appContext, exists := fleets[size]; exists {
+		return appContext
+	}
+	appContext := populate(size, faultEvery)
+	fleets[size] = appContext
+	return appContext
+}
+
+// populate builds a standalone context with a synthetic fleet
+func populate(size, faultRatio int) *Context {
+	appContext := NewContext()
+	for i := 0; i < size; i++ {
+		appContext.AddDevice(syntheticDevice(i))
+		if faultRatio > 0 && i%faultRatio == 0 {
+			appContext.AddFault(syntheticFault(i))
+		}
+	}
+	return appContext
+}
+
+// syntheticDevice returns the i-th device of the synthetic fleet
+func syntheticDevice(i int) *models.Device {
+	device := &models.Device{
+		ID: fmt.Sprintf("A1B2C3-CPE-%08d", i),
+		DeviceID: models.DeviceID{
+			Manufacturer: manufacturers[i%len(manufacturers)],
+			OUI:          "A1B2C3",
+			ProductClass: "CPE",
+			SerialNumber: fmt.Sprintf("%08d", i),
+			ModelName:    modelNames[i%len(modelNames)],
+		},
+		LastInform: time.Now(),
+		Tags:       map[string]bool{tagNames[i%len(tagNames)]: true},
+		DeviceInfo: make(map[string]interface{}),
+	}
+	device.Status.Online = i%3 != 0
+	return device
+}
+
+// syntheticFault returns a fault raised by the i-th device
+func syntheticFault(i int) *models.Fault {
+	severity := models.SeverityMajor
+	if i%2 == 0 {
+		severity = models.SeverityCritical
+	}
+	return &models.Fault{
+		ID:        fmt.Sprintf("fault-%08d", i),
+		DeviceID:  fmt.Sprintf("A1B2C3-CPE-%08d", i),
+		Channel:   "default",
+		Code:      "cwmp.9002",
+		Severity:  severity,
+		Status:    models.FaultStatusActive,
+		Timestamp: time.Now(),
+	}
+}
+
+// benchmarkFleets runs a store operation at every fleet size
+func benchmarkFleets(b *testing.B, fn func(b *testing.B, appContext *Context, devices int)) {
+	for _, size := range fleetSizes {
+		appContext := fleet(size)
+		b.Run(fmt.Sprintf("devices=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			fn(b, appContext, size)
+		})
+	}
+}
+
+func BenchmarkGetDeviceBySerial(b *testing.B) {
+	benchmarkFleets(b, func(b *testing.B, appContext *Context, devices int) {
+		for i := 0; i < b.N; i++ {
+			if _, exists := appContext.GetDeviceBySerial(fmt.Sprintf("%08d", i%devices)); !exists {
+				b.Fatal("device not found")
+			}
+		}
+	})
+}
+
+func BenchmarkGetDeviceFaults(b *testing.B) {
+	benchmarkFleets(b, func(b *testing.B, appContext *Context, devices int) {
+		for i := 0; i < b.N; i++ {
+			appContext.GetDeviceFaults(fmt.Sprintf("A1B2C3-CPE-%08d", i%devices))
+		}
+	})
+}
+
+func BenchmarkGetFilteredDevicesByModel(b *testing.B) {
+	benchmarkFleets(b, func(b *testing.B, appContext *Context, devices int) {
+		for i := 0; i < b.N; i++ {
+			appContext.GetFilteredDevices(&models.DeviceFilter{ModelName: modelNames[i%len(modelNames)]})
+		}
+	})
+}
+
+func BenchmarkGetFilteredDevicesByTagOnline(b *testing.B) {
+	benchmarkFleets(b, func(b *testing.B, appContext *Context, devices int) {
+		online := false
+		for i := 0; i < b.N; i++ {
+			appContext.GetFilteredDevices(&models.DeviceFilter{
+				Tags:   []string{tagNames[i%len(tagNames)]},
+				Online: &online,
+			})
+		}
+	})
+}
+
+func BenchmarkGetDeviceStats(b *testing.B) {
+	benchmarkFleets(b, func(b *testing.B, appContext *Context, devices int) {
+		for i := 0; i < b.N; i++ {
+			appContext.GetDeviceStats()
+		}
+	})
+}
+
+// BenchmarkAddDeviceGetDeviceStats measures the old worst case, a device
+// change followed by a stats read which used to rebuild the whole cache
+func BenchmarkAddDeviceGetDeviceStats(b *testing.B) {
+	benchmarkFleets(b, func(b *testing.B, appContext *Context, devices int) {
+		for i := 0; i < b.N; i++ {
+			device := syntheticDevice(i % devices)
+			device.Status.Online = i%2 == 0
+			appContext.AddDevice(device)
+			appContext.GetDeviceStats()
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package context
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// The tests in this file are meant for the race detector:
+//
+//	go test -race ./internal/context
+
+const (
+	// stressDevices is the fleet size, small enough for writers to collide
+	// on the same devices
+	stressDevices = 200
+
+	// stressWorkers is the number of concurrent writers and readers each
+	stressWorkers = 8
+
+	// stressRounds is how many changes every writer makes
+	stressRounds = 500
+
+	// stressCounter is the DeviceInfo key writers increment
+	stressCounter = "_stressCounter"
+)
+
+// TestConcurrentDeviceUpdates races writers, readers, a device watcher and
+// an event bus subscriber and checks that no update was lost, versions
+// only grow and the indexes agree with the stored devices
+func TestConcurrentDeviceUpdates(t *testing.T) {
+	appContext := populate(stressDevices, 10)
+
+	changes, cancel := appContext.WatchDevices(stressWorkers * stressRounds * 2)
+	defer cancel()
+	sub, err := appContext.SubscribeEvents(EventFilter{Types: []string{"device.*"}}, stressWorkers*stressRounds*4, 0)
+	if err != nil {
+		t.Fatalf("SubscribeEvents: %v", err)
+	}
+
+	// Watcher, versions of a device must only grow
+	versions := make(map[string]uint64)
+	watchErr := make(chan error, 1)
+	go func() {
+		for change := range changes {
+			if change.Version <= versions[change.DeviceID] {
+				watchErr <- fmt.Errorf("device %s went from version %d to %d",
+					change.DeviceID, versions[change.DeviceID], change.Version)
+				return
+			}
+			versions[change.DeviceID] = change.Version
+		}
+		watchErr <- nil
+	}()
+
+	// Subscriber, sequences must only grow
+	subErr := make(chan error, 1)
+	go func() {
+		var last uint64
+		for event := range sub.C {
+			if event.Sequence <= last {
+				subErr <- fmt.Errorf("event sequence went from %d to %d", last, event.Sequence)
+				return
+			}
+			last = event.Sequence
+		}
+		subErr <- sub.Err()
+	}()
+
+	var (
+		wg      sync.WaitGroup
+		applied = make([]atomic.Int64, stressDevices)
+	)
+
+	// Writers increment a counter kept on the device and flip its status,
+	// racing on the same devices
+	for w := 0; w < stressWorkers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < stressRounds; i++ {
+				index := (i + worker) % stressDevices
+				deviceID := syntheticDevice(index).ID
+				if _, err := appContext.UpdateDevice(deviceID, func(device *models.Device) error {
+					count, _ := device.DeviceInfo[stressCounter].(int64)
+					device.DeviceInfo[stressCounter] = count + 1
+					return nil
+				}); err == nil {
+					applied[index].Add(1)
+				}
+				appContext.UpdateDeviceStatus(deviceID, i%2 == 0)
+			}
+		}(w)
+	}
+
+	// Readers walk the shared snapshots the way the templates do
+	for r := 0; r < stressWorkers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < stressRounds; i++ {
+				for _, device := range appContext.GetAllDevices() {
+					_ = device.DeviceInfo[stressCounter]
+				}
+				for _, device := range appContext.GetFilteredDevices(&models.DeviceFilter{Tags: []string{tagNames[i%len(tagNames)]}}) {
+					for range device.Tags {
+					}
+					_ = device.Status.Online
+				}
+				appContext.GetDeviceStats()
+			}
+		}()
+	}
+
+	wg.Wait()
+	cancel()
+	sub.Close()
+
+	if err := <-watchErr; err != nil {
+		t.Fatal(err)
+	}
+	if err := <-subErr; err != nil {
+		t.Fatalf("subscriber: %v", err)
+	}
+
+	// Every successful update must still be visible, with the last version
+	// the watcher saw
+	for index := range applied {
+		device, exists := appContext.GetDevice(syntheticDevice(index).ID)
+		if !exists {
+			t.Fatalf("device %s disappeared", syntheticDevice(index).ID)
+		}
+		count, _ := device.DeviceInfo[stressCounter].(int64)
+		if count != applied[index].Load() {
+			t.Errorf("device %s counted %d of %d updates", device.ID, count, applied[index].Load())
+		}
+		if device.Version != versions[device.ID] {
+			t.Errorf("device %s is at version %d, the watcher last saw %d", device.ID, device.Version, versions[device.ID])
+		}
+	}
+
+	checkIndexes(t, appContext)
+}
+
+// TestConcurrentFaults raises faults from several writers while readers
+// list them and subscribers come and go, then checks every fault was
+// stored, indexed and announced exactly once
+func TestConcurrentFaults(t *testing.T) {
+	appContext := populate(stressDevices, 0)
+	total := stressWorkers * stressRounds
+
+	// A subscriber that stays for the whole run sees every fault once
+	sub, err := appContext.SubscribeEvents(EventFilter{Types: []string{EventFaultRaised}}, total, 0)
+	if err != nil {
+		t.Fatalf("SubscribeEvents: %v", err)
+	}
+	raised := make(chan map[string]int, 1)
+	go func() {
+		seen := make(map[string]int)
+		for event := range sub.C {
+			seen[event.Fault.ID]++
+		}
+		raised <- seen
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < stressWorkers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < stressRounds; i++ {
+				fault := syntheticFault((worker*stressRounds + i) % stressDevices)
+				fault.ID = fmt.Sprintf("fault-%d-%d", worker, i)
+				appContext.AddFault(fault)
+			}
+		}(w)
+	}
+
+	for r := 0; r < stressWorkers; r++ {
+		wg.Add(1)
+		go func(reader int) {
+			defer wg.Done()
+			for i := 0; i < stressRounds; i++ {
+				for _, fault := range appContext.GetActiveFaults() {
+					_ = fault.Severity
+				}
+				appContext.GetDeviceFaults(syntheticDevice(i % stressDevices).ID)
+				appContext.GetDeviceStats()
+
+				// Short-lived subscribers close while events are published
+				if reader == 0 {
+					if churn, err := appContext.SubscribeEvents(EventFilter{}, 1, 0); err == nil {
+						churn.Close()
+					}
+				}
+			}
+		}(r)
+	}
+
+	wg.Wait()
+	sub.Close()
+
+	seen := <-raised
+	if len(seen) != total {
+		t.Errorf("subscriber saw %d faults raised, want %d", len(seen), total)
+	}
+	for faultID, count := range seen {
+		if count != 1 {
+			t.Errorf("fault %s was raised %d times", faultID, count)
+		}
+	}
+
+	if got := len(appContext.GetActiveFaults()); got != total {
+		t.Errorf("%d active faults stored, want %d", got, total)
+	}
+	indexed := 0
+	for i := 0; i < stressDevices; i++ {
+		indexed += len(appContext.GetDeviceFaults(syntheticDevice(i).ID))
+	}
+	if indexed != total {
+		t.Errorf("%d faults indexed by device, want %d", indexed, total)
+	}
+	if stats := appContext.GetDeviceStats(); stats.ActiveFaults != total {
+		t.Errorf("stats count %d active faults, want %d", stats.ActiveFaults, total)
+	}
+}
+
+// checkIndexes compares the indexed lookups and counters with a full scan
+func checkIndexes(t *testing.T, appContext *Context) {
+	t.Helper()
+
+	devices := appContext.GetAllDevices()
+	stats := appContext.GetDeviceStats()
+
+	online := 0
+	for _, device := range devices {
+		if device.Status.Online {
+			online++
+		}
+		if found, exists := appContext.GetDeviceBySerial(device.DeviceID.SerialNumber); !exists || found.ID != device.ID {
+			t.Errorf("serial %s does not resolve to device %s", device.DeviceID.SerialNumber, device.ID)
+		}
+	}
+	if stats.TotalDevices != len(devices) || stats.OnlineDevices != online {
+		t.Errorf("stats report %d/%d online, scan found %d/%d",
+			stats.OnlineDevices, stats.TotalDevices, online, len(devices))
+	}
+
+	for _, tag := range tagNames {
+		expected := 0
+		for _, device := range devices {
+			if device.Tags[tag] {
+				expected++
+			}
+		}
+		if got := len(appContext.GetFilteredDevices(&models.DeviceFilter{Tags: []string{tag}})); got != expected {
+			t.Errorf("tag %s indexes %d devices, scan found %d", tag, got, expected)
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package context
 
 import (
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/search"
 )
 
 var (
@@ -16,18 +18,88 @@ var (
 type Context struct {
 	mutex sync.RWMutex
 
-	// Device management
-	devices      map[string]*models.Device
-	devicesMutex sync.RWMutex
+	// Device management, stored devices are immutable snapshots
+	devices        map[string]*models.Device
+	deviceIndex    *deviceIndex
+	deviceWatchers map[int]chan DeviceChange
+	nextWatcherID  int
+	devicesMutex   sync.RWMutex
 
-	// Fault management
-	faults      map[string]*models.Fault
-	faultsMutex sync.RWMutex
+	// Device search index
+	searchIndex *search.Index
+
+	// Event bus announcing device, fault, task and system changes
+	eventBus *EventBus
+
+	// Device status history
+	deviceHistory map[string][]*models.DeviceEvent
+	historyMutex  sync.RWMutex
 
-	// Cache for device statistics
-	statsCache      *models.DeviceStats
-	statsCacheMutex sync.RWMutex
-	statsCacheTime  time.Time
+	// Fault management
+	faults     map[string]*models.Fault
+	faultIndex *faultIndex
+	incidents  map[string]*models.Incident
+	// incidentsByKey holds the newest incident of each correlation key,
+	// resolvedIncidents the resolved incidents in the order they resolved
+	incidentsByKey    map[string]*models.Incident
+	resolvedIncidents []resolvedIncident
+	correlation       CorrelationConfig
+	faultsMutex       sync.RWMutex
+
+	// Fault state persistence
+	faultStateFile string
+
+	// Maintenance windows
+	maintenanceWindows map[string]*models.MaintenanceWindow
+	maintenanceMutex   sync.RWMutex
+
+	// Escalation policies
+	escalationPolicies map[string]*models.EscalationPolicy
+	escalationMutex    sync.RWMutex
+
+	// Gateway alarm rules
+	alarmRules      map[string]*models.AlarmRule
+	alarmRulesMutex sync.RWMutex
+
+	// Outbound webhooks and their delivery history, oldest delivery first
+	webhooks            map[string]*models.Webhook
+	webhookDeliveries   map[string]*models.WebhookDelivery
+	deliveriesByWebhook map[string][]string
+	webhookHistory      int
+	webhookStateFile    string
+	webhooksMutex       sync.RWMutex
+
+	// Permissions of each role
+	roles      map[string][]string
+	rolesMutex sync.RWMutex
+
+	// NBI API keys, indexed by the hash of the key
+	apiKeys         map[string]*models.APIKey
+	apiKeysByHash   map[string]string
+	apiKeyStateFile string
+	apiKeysMutex    sync.RWMutex
+
+	// Local UI users, indexed by username
+	users         map[string]*models.User
+	usersByName   map[string]string
+	userStateFile string
+	usersMutex    sync.RWMutex
+
+	// UI sessions by session ID
+	sessions      map[string]*models.Session
+	sessionConfig SessionConfig
+	sessionsMutex sync.RWMutex
+
+	// Audit log, the newest records for queries and the head of the
+	// hash chain
+	auditRecords       []*models.AuditRecord
+	auditMemoryRecords int
+	auditLogFile       string
+	auditSeq           uint64
+	auditLastHash      string
+	auditWatchers      map[int]chan *models.AuditRecord
+	nextAuditWatcherID int
+	auditMutex         sync.RWMutex
 
 	// GenieACS connection status
 	genieACSStatus GenieACSStatus
@@ -49,29 +121,72 @@ type GenieACSStatus struct {
 // GetContext returns the singleton context instance
 func GetContext() *Context {
 	once.Do(func() {
-		context = &Context{
-			devices: make(map[string]*models.Device),
-			faults:  make(map[string]*models.Fault),
-			statsCache: &models.DeviceStats{
-				DevicesByVendor: make(map[string]int),
-				DevicesByModel:  make(map[string]int),
-			},
-		}
+		context = NewContext()
 	})
 	return context
 }
 
+// NewContext creates an empty context. The application shares the instance
+// returned by GetContext, standalone contexts are meant for tools such as
+// the store benchmark.
+func NewContext() *Context {
+	return &Context{
+		devices:        make(map[string]*models.Device),
+		deviceIndex:    newDeviceIndex(),
+		deviceWatchers: make(map[int]chan DeviceChange),
+		faults:         make(map[string]*models.Fault),
+		faultIndex:     newFaultIndex(),
+		incidents:      make(map[string]*models.Incident),
+		incidentsByKey: make(map[string]*models.Incident),
+
+		searchIndex:   search.NewIndex(DefaultSearchParameters),
+		eventBus:      NewEventBus(DefaultEventHistory),
+		deviceHistory: make(map[string][]*models.DeviceEvent),
+
+		maintenanceWindows: make(map[string]*models.MaintenanceWindow),
+		escalationPolicies: make(map[string]*models.EscalationPolicy),
+		alarmRules:         make(map[string]*models.AlarmRule),
+
+		webhooks:            make(map[string]*models.Webhook),
+		webhookDeliveries:   make(map[string]*models.WebhookDelivery),
+		deliveriesByWebhook: make(map[string][]string),
+		webhookHistory:      DefaultWebhookDeliveryHistory,
+
+		roles: defaultRoles(),
+
+		apiKeys:       make(map[string]*models.APIKey),
+		apiKeysByHash: make(map[string]string),
+
+		users:       make(map[string]*models.User),
+		usersByName: make(map[string]string),
+
+		sessions:      make(map[string]*models.Session),
+		sessionConfig: DefaultSessionConfig,
+
+		auditMemoryRecords: DefaultAuditMemoryRecords,
+		auditWatchers:      make(map[int]chan *models.AuditRecord),
+	}
+}
+
 // Device Management Functions
 
-// AddDevice adds or updates a device in the context
+// AddDevice adds or replaces a device in the context regardless of its
+// version. The context takes ownership of device, the caller must not modify
+// it afterwards.
 func (c *Context) AddDevice(device *models.Device) {
 	c.devicesMutex.Lock()
 	defer c.devicesMutex.Unlock()
-	c.devices[device.ID] = device
-	c.invalidateStatsCache()
+
+	// The stored snapshot is immutable, adding it again changes nothing
+	previous := c.devices[device.ID]
+	if previous == device {
+		return
+	}
+	c.storeDevice(previous, device)
 }
 
-// GetDevice retrieves a device by ID
+// GetDevice retrieves a device by ID. The device is a snapshot shared with
+// other readers and must not be modified, use UpdateDevice to change it.
 func (c *Context) GetDevice(deviceID string) (*models.Device, bool) {
 	c.devicesMutex.RLock()
 	defer c.devicesMutex.RUnlock()
@@ -84,8 +199,8 @@ func (c *Context) GetDeviceBySerial(serial string) (*models.Device, bool) {
 	c.devicesMutex.RLock()
 	defer c.devicesMutex.RUnlock()
 
-	for _, device := range c.devices {
-		if device.DeviceID.SerialNumber == serial {
+	for deviceID := range c.deviceIndex.bySerial[serial] {
+		if device, exists := c.devices[deviceID]; exists {
 			return device, true
 		}
 	}
@@ -111,12 +226,37 @@ func (c *Context) GetFilteredDevices(filter *models.DeviceFilter) []*models.Devi
 
 	var filtered []*models.Device
 
-	for _, device := range c.devices {
-		if matchesFilter(device, filter) {
-			filtered = append(filtered, device)
+	// Tenants are resolved by the index, not by the device itself
+	matches := func(device *models.Device) bool {
+		return matchesFilter(device, filter) && (filter == nil || c.deviceIndex.inTenant(device.ID, filter.Tenant))
+	}
+
+	// A search ranks the result, best match first
+	if filter != nil && filter.Search != "" {
+		for _, hit := range c.searchIndex.Search(filter.Search, 0) {
+			if device, exists := c.devices[hit.DeviceID]; exists && matches(device) {
+				filtered = append(filtered, device)
+			}
 		}
+		return filtered
 	}
 
+	// Narrow down to the smallest indexed set, the filter still decides
+	candidates, indexed := c.deviceIndex.candidates(filter)
+	if !indexed {
+		for _, device := range c.devices {
+			if matches(device) {
+				filtered = append(filtered, device)
+			}
+		}
+		return filtered
+	}
+
+	for deviceID := range candidates {
+		if device, exists := c.devices[deviceID]; exists && matches(device) {
+			filtered = append(filtered, device)
+		}
+	}
 	return filtered
 }
 
@@ -124,8 +264,10 @@ func (c *Context) GetFilteredDevices(filter *models.DeviceFilter) []*models.Devi
 func (c *Context) RemoveDevice(deviceID string) {
 	c.devicesMutex.Lock()
 	defer c.devicesMutex.Unlock()
-	delete(c.devices, deviceID)
-	c.invalidateStatsCache()
+
+	if previous, exists := c.devices[deviceID]; exists {
+		c.deleteDevice(previous)
+	}
 }
 
 // UpdateDeviceStatus updates the status of a device
@@ -133,26 +275,62 @@ func (c *Context) UpdateDeviceStatus(deviceID string, online bool) {
 	c.devicesMutex.Lock()
 	defer c.devicesMutex.Unlock()
 
-	if device, exists := c.devices[deviceID]; exists {
+	if previous, exists := c.devices[deviceID]; exists {
+		device := previous.Clone()
 		device.Status.Online = online
 		device.Status.LastSeen = time.Now()
 		if online {
-			device.Status.ConnectionStatus = "connected"
+			device.Status.ConnectionStatus = models.ConnectionStatusOnline
 		} else {
-			device.Status.ConnectionStatus = "disconnected"
+			device.Status.ConnectionStatus = models.ConnectionStatusOffline
 		}
-		c.invalidateStatsCache()
+		c.storeDevice(previous, device)
 	}
 }
 
 // Fault Management Functions
 
-// AddFault adds a new fault
+// AddFault adds a new fault or refreshes an existing one. Operator lifecycle
+// state is kept when GenieACS reports the same fault again.
 func (c *Context) AddFault(fault *models.Fault) {
+	// Resolve maintenance windows before taking faultsMutex, the lookup
+	// needs devicesMutex
+	windowID := c.findSuppressingWindow(fault)
+
 	c.faultsMutex.Lock()
 	defer c.faultsMutex.Unlock()
+
+	existing, exists := c.faults[fault.ID]
+	if exists && existing != fault {
+		copyFaultLifecycle(fault, existing)
+	}
+
 	c.faults[fault.ID] = fault
-	c.invalidateStatsCache()
+	if !exists {
+		if windowID != "" {
+			fault.Suppressed = true
+			fault.MaintenanceWindowID = windowID
+		}
+		c.correlateFault(fault)
+	} else if fault.IncidentID != "" {
+		c.refreshIncident(fault.IncidentID)
+	}
+	c.faultIndex.update(fault)
+
+	// Subscribers check Suppressed before notifying anyone
+	if !exists {
+		c.publishFaultEvent(EventFaultRaised, fault)
+	}
+}
+
+// AddGatewayFault adds a fault raised by the gateway itself, such as alarm
+// rule and heartbeat faults, and persists it so it survives a restart
+func (c *Context) AddGatewayFault(fault *models.Fault) {
+	c.AddFault(fault)
+
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+	c.saveFaultState()
 }
 
 // GetFault retrieves a fault by ID
@@ -169,14 +347,30 @@ func (c *Context) GetDeviceFaults(deviceID string) []*models.Fault {
 	defer c.faultsMutex.RUnlock()
 
 	var deviceFaults []*models.Fault
-	for _, fault := range c.faults {
-		if fault.DeviceID == deviceID {
+	for faultID := range c.faultIndex.byDevice[deviceID] {
+		if fault, exists := c.faults[faultID]; exists {
 			deviceFaults = append(deviceFaults, fault)
 		}
 	}
 	return deviceFaults
 }
 
+// GetAllFaults returns every known fault, most recent first
+func (c *Context) GetAllFaults() []*models.Fault {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	faults := make([]*models.Fault, 0, len(c.faults))
+	for _, fault := range c.faults {
+		faults = append(faults, fault)
+	}
+
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].Timestamp.After(faults[j].Timestamp)
+	})
+	return faults
+}
+
 // GetActiveFaults returns all active faults
 func (c *Context) GetActiveFaults() []*models.Fault {
 	c.faultsMutex.RLock()
@@ -206,12 +400,23 @@ func (c *Context) AcknowledgeFault(faultID, acknowledgedBy string) error {
 	fault.AcknowledgedBy = acknowledgedBy
 	fault.AcknowledgedAt = &now
 
-	c.invalidateStatsCache()
+	if fault.IncidentID != "" {
+		c.refreshIncident(fault.IncidentID)
+	}
+
+	c.faultIndex.update(fault)
+	c.saveFaultState()
+	c.publishFaultEvent(EventFaultAcknowledged, fault)
 	return nil
 }
 
-// ResolveFault resolves a fault
-func (c *Context) ResolveFault(faultID, resolvedBy string) error {
+// ResolveFault resolves a fault with an optional resolution category and
+// description
+func (c *Context) ResolveFault(faultID, resolvedBy, category, resolution string) error {
+	if !models.IsValidResolutionCategory(category) {
+		return models.ErrInvalidResolution
+	}
+
 	c.faultsMutex.Lock()
 	defer c.faultsMutex.Unlock()
 
@@ -224,95 +429,36 @@ func (c *Context) ResolveFault(faultID, resolvedBy string) error {
 	fault.Status = models.FaultStatusResolved
 	fault.ResolvedBy = resolvedBy
 	fault.ResolvedAt = &now
+	fault.ResolutionCategory = category
+	fault.Resolution = resolution
 
-	c.invalidateStatsCache()
+	if fault.IncidentID != "" {
+		c.refreshIncident(fault.IncidentID)
+	}
+
+	c.faultIndex.update(fault)
+	c.saveFaultState()
+	c.publishFaultEvent(EventFaultResolved, fault)
 	return nil
 }
 
 // Statistics Functions
 
-// GetDeviceStats returns cached device statistics
+// GetDeviceStats returns device statistics. The counters are maintained as
+// devices and faults change, so this costs no more than copying them.
 func (c *Context) GetDeviceStats() *models.DeviceStats {
-	c.statsCacheMutex.RLock()
-
-	// Check if cache is valid (less than 1 minute old)
-	if time.Since(c.statsCacheTime) < time.Minute {
-		defer c.statsCacheMutex.RUnlock()
-		return c.statsCache
-	}
-
-	c.statsCacheMutex.RUnlock()
-
-	// Update cache
-	c.updateStatsCache()
-
-	c.statsCacheMutex.RLock()
-	defer c.statsCacheMutex.RUnlock()
-	return c.statsCache
-}
-
-// updateStatsCache updates the statistics cache
-func (c *Context) updateStatsCache() {
-	c.statsCacheMutex.Lock()
-	defer c.statsCacheMutex.Unlock()
-
 	c.devicesMutex.RLock()
 	c.faultsMutex.RLock()
 	defer c.devicesMutex.RUnlock()
 	defer c.faultsMutex.RUnlock()
 
 	stats := &models.DeviceStats{
-		TotalDevices:    len(c.devices),
-		OnlineDevices:   0,
-		OfflineDevices:  0,
 		DevicesByVendor: make(map[string]int),
 		DevicesByModel:  make(map[string]int),
-		ActiveFaults:    0,
-		CriticalFaults:  0,
 	}
-
-	// Count devices
-	for _, device := range c.devices {
-		if device.Status.Online {
-			stats.OnlineDevices++
-		} else {
-			stats.OfflineDevices++
-		}
-
-		// Count by vendor
-		vendor := device.DeviceID.Manufacturer
-		if vendor == "" {
-			vendor = "Unknown"
-		}
-		stats.DevicesByVendor[vendor]++
-
-		// Count by model
-		model := device.DeviceID.ModelName
-		if model == "" {
-			model = "Unknown"
-		}
-		stats.DevicesByModel[model]++
-	}
-
-	// Count faults
-	for _, fault := range c.faults {
-		if fault.Status == models.FaultStatusActive || fault.Status == models.FaultStatusAcknowledged {
-			stats.ActiveFaults++
-			if fault.Severity == models.SeverityCritical {
-				stats.CriticalFaults++
-			}
-		}
-	}
-
-	c.statsCache = stats
-	c.statsCacheTime = time.Now()
-}
-
-// invalidateStatsCache marks the stats cache as invalid
-func (c *Context) invalidateStatsCache() {
-	c.statsCacheMutex.Lock()
-	defer c.statsCacheMutex.Unlock()
-	c.statsCacheTime = time.Time{} // Set to zero time
+	c.deviceIndex.fillStats(stats)
+	c.faultIndex.fillStats(stats)
+	return stats
 }
 
 // GenieACS Status Functions
@@ -324,12 +470,23 @@ func (c *Context) GetGenieACSStatus() GenieACSStatus {
 	return c.genieACSStatus
 }
 
-// UpdateGenieACSStatus updates the GenieACS connection status
+// UpdateGenieACSStatus updates the GenieACS connection status and announces
+// changes of connectivity
 func (c *Context) UpdateGenieACSStatus(status GenieACSStatus) {
 	c.statusMutex.Lock()
 	defer c.statusMutex.Unlock()
+
+	previous := c.genieACSStatus
 	c.genieACSStatus = status
 	c.genieACSStatus.LastCheck = time.Now()
+
+	if previous.LastCheck.IsZero() ||
+		previous.CWMPConnected != status.CWMPConnected ||
+		previous.NBIConnected != status.NBIConnected ||
+		previous.FSConnected != status.FSConnected {
+		current := c.genieACSStatus
+		c.eventBus.Publish(Event{Type: EventACSConnectivityChanged, ACSStatus: &current})
+	}
 }
 
 // Helper Functions
@@ -341,10 +498,8 @@ func matchesFilter(device *models.Device, filter *models.DeviceFilter) bool {
 	}
 
 	// Check IP range
-	if filter.IPRange != nil {
-		if !isIPInRange(device.DeviceID.IPAddress, filter.IPRange.StartIP, filter.IPRange.EndIP) {
-			return false
-		}
+	if filter.IPRange != nil && !filter.IPRange.MatchesDevice(device) {
+		return false
 	}
 
 	// Check manufacturer
@@ -379,13 +534,6 @@ func matchesFilter(device *models.Device, filter *models.DeviceFilter) bool {
 	return true
 }
 
-// isIPInRange checks if an IP address is within the specified range
-func isIPInRange(ip, startIP, endIP string) bool {
-	// TODO: Implement IP range checking
-	// This is a placeholder implementation
-	return true
-}
-
 // SetConfig sets the application configuration
 func (c *Context) SetConfig(config interface{}) {
 	c.mutex.Lock()
@@ -0,0 +1,176 @@
+package context
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Escalation Policy Functions
+
+// AddEscalationPolicy stores a new escalation policy and assigns its ID
+func (c *Context) AddEscalationPolicy(policy *models.EscalationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	c.escalationMutex.Lock()
+	defer c.escalationMutex.Unlock()
+
+	now := time.Now()
+	if policy.ID == "" {
+		policy.ID = uuid.New().String()
+	}
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+	c.escalationPolicies[policy.ID] = policy
+	return nil
+}
+
+// UpdateEscalationPolicy replaces an existing escalation policy
+func (c *Context) UpdateEscalationPolicy(policy *models.EscalationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	c.escalationMutex.Lock()
+	defer c.escalationMutex.Unlock()
+
+	existing, exists := c.escalationPolicies[policy.ID]
+	if !exists {
+		return models.ErrEscalationPolicyNotFound
+	}
+
+	policy.CreatedAt = existing.CreatedAt
+	policy.UpdatedAt = time.Now()
+	c.escalationPolicies[policy.ID] = policy
+	return nil
+}
+
+// RemoveEscalationPolicy deletes an escalation policy
+func (c *Context) RemoveEscalationPolicy(policyID string) error {
+	c.escalationMutex.Lock()
+	defer c.escalationMutex.Unlock()
+
+	if _, exists := c.escalationPolicies[policyID]; !exists {
+		return models.ErrEscalationPolicyNotFound
+	}
+	delete(c.escalationPolicies, policyID)
+	return nil
+}
+
+// GetEscalationPolicy retrieves an escalation policy by ID
+func (c *Context) GetEscalationPolicy(policyID string) (*models.EscalationPolicy, bool) {
+	c.escalationMutex.RLock()
+	defer c.escalationMutex.RUnlock()
+	policy, exists := c.escalationPolicies[policyID]
+	return policy, exists
+}
+
+// GetEscalationPolicies returns all escalation policies, oldest first.
+// Policies are evaluated in this order and the first match wins.
+func (c *Context) GetEscalationPolicies() []*models.EscalationPolicy {
+	c.escalationMutex.RLock()
+	defer c.escalationMutex.RUnlock()
+
+	policies := make([]*models.EscalationPolicy, 0, len(c.escalationPolicies))
+	for _, policy := range c.escalationPolicies {
+		policies = append(policies, policy)
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].CreatedAt.Equal(policies[j].CreatedAt) {
+			return policies[i].Name < policies[j].Name
+		}
+		return policies[i].CreatedAt.Before(policies[j].CreatedAt)
+	})
+	return policies
+}
+
+// EscalateFaults moves unacknowledged faults up their escalation policy and
+// returns a fault.escalated event per fault that reached a new level, for
+// the caller to dispatch on the event bus. Suppressed faults are never
+// escalated.
+func (c *Context) EscalateFaults(now time.Time) []Event {
+	policies := c.GetEscalationPolicies()
+	if len(policies) == 0 {
+		return nil
+	}
+
+	// Pick a policy and target level for every candidate without holding
+	// faultsMutex, device tags need devicesMutex
+	type pending struct {
+		policy *models.EscalationPolicy
+		level  *models.EscalationLevel
+	}
+	targets := make(map[string]pending)
+
+	for _, fault := range c.GetActiveFaults() {
+		if fault.Status != models.FaultStatusActive || fault.Suppressed || fault.Timestamp.IsZero() {
+			continue
+		}
+
+		var tags map[string]bool
+		if device, exists := c.GetDevice(fault.DeviceID); exists {
+			tags = device.Tags
+		}
+
+		for _, policy := range policies {
+			if !policy.Matches(fault, tags) {
+				continue
+			}
+			if level := policy.LevelFor(now.Sub(fault.Timestamp)); level != nil {
+				targets[fault.ID] = pending{policy: policy, level: level}
+			}
+			break
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+
+	events := make([]Event, 0)
+	for faultID, target := range targets {
+		fault, exists := c.faults[faultID]
+		// The fault may have been acknowledged in the meantime
+		if !exists || fault.Status != models.FaultStatusActive {
+			continue
+		}
+		if fault.EscalationLevel >= target.level.Level {
+			continue
+		}
+
+		escalatedAt := now
+		fault.EscalationLevel = target.level.Level
+		fault.EscalationPolicyID = target.policy.ID
+		fault.EscalatedAt = &escalatedAt
+
+		events = append(events, Event{
+			Type:     EventFaultEscalated,
+			DeviceID: fault.DeviceID,
+			Severity: fault.Severity,
+			Fault:    cloneFault(fault),
+			Escalation: &models.EscalationEvent{
+				FaultID:    fault.ID,
+				DeviceID:   fault.DeviceID,
+				Severity:   fault.Severity,
+				PolicyID:   target.policy.ID,
+				PolicyName: target.policy.Name,
+				Level:      target.level.Level,
+				Notify:     target.level.Notify,
+				Timestamp:  now,
+			},
+		})
+	}
+
+	if len(events) > 0 {
+		c.saveFaultState()
+	}
+	return events
+}
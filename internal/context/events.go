@@ -0,0 +1,389 @@
+package context
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Event types published on the event bus. A subscriber can select a whole
+// category with a "device.*" style pattern.
+const (
+	EventDeviceAdded       = "device.added"
+	EventDeviceUpdated     = "device.updated"
+	EventDeviceRemoved     = "device.removed"
+	EventDeviceWentOnline  = "device.online"
+	EventDeviceWentOffline = "device.offline"
+
+	EventFaultRaised       = "fault.raised"
+	EventFaultAcknowledged = "fault.acknowledged"
+	EventFaultResolved     = "fault.resolved"
+	EventFaultEscalated    = "fault.escalated"
+
+	EventTaskQueued    = "task.queued"
+	EventTaskCompleted = "task.completed"
+	EventTaskFailed    = "task.failed"
+
+	EventACSConnectivityChanged = "system.acs_connectivity"
+)
+
+// EventTypes lists every event type, in the order they are documented
+var EventTypes = []string{
+	EventDeviceAdded, EventDeviceUpdated, EventDeviceRemoved, EventDeviceWentOnline, EventDeviceWentOffline,
+	EventFaultRaised, EventFaultAcknowledged, EventFaultResolved, EventFaultEscalated,
+	EventTaskQueued, EventTaskCompleted, EventTaskFailed,
+	EventACSConnectivityChanged,
+}
+
+// Event bus defaults
+const (
+	// DefaultEventHistory is the number of recent events kept for resuming
+	DefaultEventHistory = 10000
+	// DefaultEventBuffer is the subscriber buffer used when none is given
+	DefaultEventBuffer = 256
+)
+
+// Event is a state change announced on the event bus. Only the payload
+// matching the event category is set. Devices are shared immutable
+// snapshots and faults are copies taken when the event was published.
+type Event struct {
+	Sequence  uint64    `json:"sequence"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	DeviceID  string    `json:"deviceId,omitempty"`
+	Severity  string    `json:"severity,omitempty"`
+
+	Device     *models.Device          `json:"device,omitempty"`
+	Fault      *models.Fault           `json:"fault,omitempty"`
+	Escalation *models.EscalationEvent `json:"escalation,omitempty"`
+	Task       *models.Task            `json:"task,omitempty"`
+	ACSStatus  *GenieACSStatus         `json:"acsStatus,omitempty"`
+}
+
+// EventFilter selects events for a subscriber. Empty fields match
+// everything.
+type EventFilter struct {
+	// Types holds event types or category patterns such as "fault.*"
+	Types      []string `json:"types,omitempty"`
+	DeviceID   string   `json:"deviceId,omitempty"`
+	Severities []string `json:"severities,omitempty"`
+}
+
+// Matches checks if an event passes the filter. Events without a device or
+// severity, such as ACS connectivity changes, are not excluded by the
+// device and severity criteria.
+func (f *EventFilter) Matches(event *Event) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.Types) > 0 {
+		matched := false
+		for _, pattern := range f.Types {
+			if matchesEventType(pattern, event.Type) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if f.DeviceID != "" && event.DeviceID != "" && event.DeviceID != f.DeviceID {
+		return false
+	}
+
+	if len(f.Severities) > 0 && event.Severity != "" {
+		matched := false
+		for _, severity := range f.Severities {
+			if severity == event.Severity {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesEventType matches an event type against an exact type, a
+// "category.*" pattern or "*"
+func matchesEventType(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	if category, ok := strings.CutSuffix(pattern, ".*"); ok {
+		return strings.HasPrefix(eventType, category+".")
+	}
+	return false
+}
+
+// IsValidEventPattern checks if an event type filter names a known event
+// type, a known category as in "fault.*", or "*"
+func IsValidEventPattern(pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	category, wildcard := strings.CutSuffix(pattern, ".*")
+	for _, eventType := range EventTypes {
+		if eventType == pattern || (wildcard && strings.HasPrefix(eventType, category+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription delivers the events matching its filter. When the subscriber
+// does not keep up and its buffer fills, the subscription is closed and Err
+// returns ErrSlowConsumer. The subscriber can then resume from the sequence
+// of the last event it handled.
+type Subscription struct {
+	C <-chan Event
+
+	id     uint64
+	events chan Event
+	filter EventFilter
+	bus    *EventBus
+	err    error
+}
+
+// Err returns why the bus closed the subscription, nil while it is open or
+// when the subscriber closed it
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription and closes its channel
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.drop(s, nil)
+}
+
+// EventBus is an in-process publish/subscribe bus. Every event gets a
+// sequence number and the most recent events are retained so that
+// subscribers can resume after a disconnect. Publishing never blocks.
+type EventBus struct {
+	mu sync.Mutex
+
+	sequence uint64
+	// ring buffer of retained events, history[next] is the oldest once full
+	history []Event
+	next    int
+	full    bool
+
+	subscribers map[uint64]*Subscription
+	nextID      uint64
+}
+
+// NewEventBus creates an event bus retaining the given number of events
+func NewEventBus(historySize int) *EventBus {
+	if historySize <= 0 {
+		historySize = DefaultEventHistory
+	}
+	return &EventBus{
+		history:     make([]Event, historySize),
+		subscribers: make(map[uint64]*Subscription),
+	}
+}
+
+// Publish assigns the next sequence number to an event, retains it and
+// delivers it to the matching subscribers
+func (b *EventBus) Publish(event Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sequence++
+	event.Sequence = b.sequence
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.history[b.next] = event
+	b.next = (b.next + 1) % len(b.history)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for _, sub := range b.subscribers {
+		if !sub.filter.Matches(&event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			logger.ContextLog.Warnf("Event subscriber %d fell behind at sequence %d, dropping it", sub.id, event.Sequence)
+			b.drop(sub, models.ErrSlowConsumer)
+		}
+	}
+	return event
+}
+
+// Subscribe registers a subscriber with room for buffer undelivered events.
+// A non-zero after resumes the stream: retained events with a higher
+// sequence are delivered first. ErrEventsExpired is returned when events
+// after that sequence are no longer retained, the subscriber has to resync
+// its state and subscribe again from 0. Sequences restart with the process.
+func (b *EventBus) Subscribe(filter EventFilter, buffer int, after uint64) (*Subscription, error) {
+	if buffer <= 0 {
+		buffer = DefaultEventBuffer
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A sequence from the future was issued before a restart
+	if after > b.sequence {
+		return nil, models.ErrEventsExpired
+	}
+
+	var replay []Event
+	if after > 0 && after < b.sequence {
+		retained := b.retained()
+		if len(retained) == 0 || retained[0].Sequence > after+1 {
+			return nil, models.ErrEventsExpired
+		}
+		for _, event := range retained {
+			if event.Sequence > after && filter.Matches(&event) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	b.nextID++
+	events := make(chan Event, buffer+len(replay))
+	for _, event := range replay {
+		events <- event
+	}
+
+	sub := &Subscription{
+		C:      events,
+		id:     b.nextID,
+		events: events,
+		filter: filter,
+		bus:    b,
+	}
+	b.subscribers[sub.id] = sub
+	return sub, nil
+}
+
+// Sequence returns the sequence number of the last published event
+func (b *EventBus) Sequence() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sequence
+}
+
+// retained returns the retained events, oldest first. Must be called with
+// mu held.
+func (b *EventBus) retained() []Event {
+	if !b.full {
+		return b.history[:b.next]
+	}
+	events := make([]Event, 0, len(b.history))
+	events = append(events, b.history[b.next:]...)
+	return append(events, b.history[:b.next]...)
+}
+
+// drop removes a subscriber and closes its channel, err tells it why.
+// Must be called with mu held.
+func (b *EventBus) drop(sub *Subscription, err error) {
+	if _, active := b.subscribers[sub.id]; !active {
+		return
+	}
+	delete(b.subscribers, sub.id)
+	sub.err = err
+	close(sub.events)
+}
+
+// Event Bus Functions
+
+// PublishEvent publishes an event on the application event bus
+func (c *Context) PublishEvent(event Event) Event {
+	return c.eventBus.Publish(event)
+}
+
+// SubscribeEvents subscribes to the application event bus, see
+// EventBus.Subscribe
+func (c *Context) SubscribeEvents(filter EventFilter, buffer int, after uint64) (*Subscription, error) {
+	return c.eventBus.Subscribe(filter, buffer, after)
+}
+
+// EventSequence returns the sequence number of the last published event
+func (c *Context) EventSequence() uint64 {
+	return c.eventBus.Sequence()
+}
+
+// publishDeviceEvents announces a stored device change. Inventory syncs
+// store every device again, so device.updated is only published when
+// identity, status, tags or the last inform changed.
+func (c *Context) publishDeviceEvents(previous, device *models.Device) {
+	if previous == nil {
+		c.eventBus.Publish(Event{Type: EventDeviceAdded, DeviceID: device.ID, Device: device})
+		return
+	}
+
+	if !deviceChanged(previous, device) {
+		return
+	}
+	c.eventBus.Publish(Event{Type: EventDeviceUpdated, DeviceID: device.ID, Device: device})
+
+	wasOffline := connectionState(previous) == models.ConnectionStatusOffline
+	isOffline := connectionState(device) == models.ConnectionStatusOffline
+	switch {
+	case !wasOffline && isOffline:
+		c.eventBus.Publish(Event{Type: EventDeviceWentOffline, DeviceID: device.ID, Device: device})
+	case wasOffline && !isOffline:
+		c.eventBus.Publish(Event{Type: EventDeviceWentOnline, DeviceID: device.ID, Device: device})
+	}
+}
+
+// publishFaultEvent announces a fault lifecycle change with a copy of the
+// fault, stored faults keep changing after the event was published
+func (c *Context) publishFaultEvent(eventType string, fault *models.Fault) {
+	c.eventBus.Publish(Event{
+		Type:     eventType,
+		DeviceID: fault.DeviceID,
+		Severity: fault.Severity,
+		Fault:    cloneFault(fault),
+	})
+}
+
+// deviceChanged checks if a device differs from its previous snapshot in a
+// way worth announcing
+func deviceChanged(previous, device *models.Device) bool {
+	// Compare instants, not the monotonic clock readings
+	prevStatus, status := previous.Status, device.Status
+	prevStatus.LastSeen, status.LastSeen = time.Time{}, time.Time{}
+
+	if previous.DeviceID != device.DeviceID ||
+		prevStatus != status ||
+		!previous.Status.LastSeen.Equal(device.Status.LastSeen) ||
+		!previous.LastInform.Equal(device.LastInform) ||
+		len(previous.Tags) != len(device.Tags) {
+		return true
+	}
+	for tag, set := range device.Tags {
+		if previous.Tags[tag] != set {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneFault copies a fault including its notes and tags
+func cloneFault(fault *models.Fault) *models.Fault {
+	clone := *fault
+	clone.Notes = append([]models.FaultNote(nil), fault.Notes...)
+	clone.Tags = append([]string(nil), fault.Tags...)
+	return &clone
+}
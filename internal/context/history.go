@@ -0,0 +1,132 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// maxDeviceEvents caps the history kept per device, oldest events are
+// dropped first
+const maxDeviceEvents = 1000
+
+// Device History Functions
+
+// GetDeviceHistory returns the events of a device since the given time in
+// chronological order. A zero time returns the full history.
+func (c *Context) GetDeviceHistory(deviceID string, since time.Time) []*models.DeviceEvent {
+	c.historyMutex.RLock()
+	defer c.historyMutex.RUnlock()
+
+	history := c.deviceHistory[deviceID]
+	start := sort.Search(len(history), func(i int) bool {
+		return !history[i].Timestamp.Before(since)
+	})
+
+	events := make([]*models.DeviceEvent, len(history)-start)
+	copy(events, history[start:])
+	return events
+}
+
+// GetDeviceAvailability computes the availability of a device over a range
+func (c *Context) GetDeviceAvailability(deviceID string, from, to time.Time) models.Availability {
+	c.historyMutex.RLock()
+	defer c.historyMutex.RUnlock()
+	return models.CalculateAvailability(c.deviceHistory[deviceID], from, to)
+}
+
+// recordDeviceChanges records the differences between the stored and the
+// refreshed copy of a device. previous is nil for a newly seen device.
+// Must be called with devicesMutex held.
+func (c *Context) recordDeviceChanges(previous, device *models.Device) {
+	now := time.Now()
+	state := connectionState(device)
+
+	if previous == nil {
+		c.recordDeviceEvent(&models.DeviceEvent{
+			DeviceID:  device.ID,
+			Type:      state,
+			To:        state,
+			Message:   "First seen " + state,
+			Timestamp: now,
+		})
+		return
+	}
+
+	if prevState := connectionState(previous); prevState != state {
+		c.recordDeviceEvent(&models.DeviceEvent{
+			DeviceID:  device.ID,
+			Type:      state,
+			From:      prevState,
+			To:        state,
+			Message:   fmt.Sprintf("Went %s (was %s)", state, prevState),
+			Timestamp: now,
+		})
+	}
+
+	if !device.LastBoot.IsZero() && !device.LastBoot.Equal(previous.LastBoot) {
+		c.recordDeviceEvent(&models.DeviceEvent{
+			DeviceID:  device.ID,
+			Type:      models.DeviceEventBoot,
+			Message:   "Device booted",
+			Timestamp: device.LastBoot,
+		})
+	}
+
+	if !device.LastBootstrap.IsZero() && !device.LastBootstrap.Equal(previous.LastBootstrap) {
+		c.recordDeviceEvent(&models.DeviceEvent{
+			DeviceID:  device.ID,
+			Type:      models.DeviceEventBootstrap,
+			Message:   "Device bootstrapped",
+			Timestamp: device.LastBootstrap,
+		})
+	}
+
+	from, to := previous.DeviceID.SoftwareVersion, device.DeviceID.SoftwareVersion
+	if from != "" && to != "" && from != to {
+		c.recordDeviceEvent(&models.DeviceEvent{
+			DeviceID:  device.ID,
+			Type:      models.DeviceEventFirmware,
+			From:      from,
+			To:        to,
+			Message:   fmt.Sprintf("Firmware changed from %s to %s", from, to),
+			Timestamp: now,
+		})
+	}
+}
+
+// recordDeviceEvent appends an event to the history of its device, keeping
+// it sorted by timestamp
+func (c *Context) recordDeviceEvent(event *models.DeviceEvent) {
+	c.historyMutex.Lock()
+	defer c.historyMutex.Unlock()
+
+	history := append(c.deviceHistory[event.DeviceID], event)
+
+	// Boot and bootstrap events carry the time reported by the CPE, which
+	// can be earlier than events already recorded
+	if n := len(history); n > 1 && event.Timestamp.Before(history[n-2].Timestamp) {
+		sort.SliceStable(history, func(i, j int) bool {
+			return history[i].Timestamp.Before(history[j].Timestamp)
+		})
+	}
+
+	if len(history) > maxDeviceEvents {
+		history = history[len(history)-maxDeviceEvents:]
+	}
+	c.deviceHistory[event.DeviceID] = history
+}
+
+// connectionState returns the connectivity state of a device as an event type
+func connectionState(device *models.Device) string {
+	switch device.Status.ConnectionStatus {
+	case models.ConnectionStatusOnline, models.ConnectionStatusLate, models.ConnectionStatusOffline:
+		return device.Status.ConnectionStatus
+	}
+	if device.Status.Online {
+		return models.DeviceEventOnline
+	}
+	return models.DeviceEventOffline
+}
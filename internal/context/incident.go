@@ -0,0 +1,385 @@
+package context
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// DefaultIncidentRetention is how long resolved incidents are kept when no
+// retention is configured
+const DefaultIncidentRetention = 7 * 24 * time.Hour
+
+// CorrelationConfig controls how faults are grouped into incidents
+type CorrelationConfig struct {
+	Enabled   bool
+	Window    time.Duration
+	MinFaults int
+	// Retention is how long resolved incidents are kept
+	Retention time.Duration
+}
+
+// resolvedIncident queues a resolved incident for eviction
+type resolvedIncident struct {
+	id         string
+	resolvedAt time.Time
+}
+
+// SetCorrelationConfig sets the fault correlation settings
+func (c *Context) SetCorrelationConfig(cfg CorrelationConfig) {
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+	c.correlation = cfg
+}
+
+// GetCorrelationConfig returns the fault correlation settings
+func (c *Context) GetCorrelationConfig() CorrelationConfig {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+	return c.correlation
+}
+
+// Incident Management Functions
+
+// GetIncident retrieves an incident by ID
+func (c *Context) GetIncident(incidentID string) (*models.Incident, bool) {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+	incident, exists := c.incidents[incidentID]
+	return incident, exists
+}
+
+// GetIncidents returns incidents matching the filter, most recent first
+func (c *Context) GetIncidents(filter *models.IncidentFilter) []*models.Incident {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	incidents := make([]*models.Incident, 0)
+	for _, incident := range c.incidents {
+		if matchesIncidentFilter(incident, filter) {
+			incidents = append(incidents, incident)
+		}
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].LastSeen.After(incidents[j].LastSeen)
+	})
+	return incidents
+}
+
+// GetIncidentFaults returns the member faults of an incident
+func (c *Context) GetIncidentFaults(incidentID string) ([]*models.Fault, error) {
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	incident, exists := c.incidents[incidentID]
+	if !exists {
+		return nil, models.ErrIncidentNotFound
+	}
+
+	faults := make([]*models.Fault, 0, len(incident.FaultIDs))
+	for _, faultID := range incident.FaultIDs {
+		if fault, ok := c.faults[faultID]; ok {
+			faults = append(faults, fault)
+		}
+	}
+	return faults, nil
+}
+
+// AcknowledgeIncident acknowledges an incident and all of its active member
+// faults. A tenant only acknowledges the faults of its devices, the
+// incident follows once no member is left active.
+func (c *Context) AcknowledgeIncident(tenantID, incidentID, acknowledgedBy string) error {
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+
+	incident, err := c.tenantOpenIncident(tenantID, incidentID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, faultID := range incident.FaultIDs {
+		fault, ok := c.faults[faultID]
+		if !ok || fault.Status != models.FaultStatusActive || !c.deviceIndex.inTenant(fault.DeviceID, tenantID) {
+			continue
+		}
+		fault.Status = models.FaultStatusAcknowledged
+		fault.AcknowledgedBy = acknowledgedBy
+		fault.AcknowledgedAt = &now
+		c.faultIndex.update(fault)
+		c.publishFaultEvent(EventFaultAcknowledged, fault)
+	}
+
+	if tenantID != "" {
+		c.refreshIncident(incidentID)
+	} else {
+		incident.Status = models.FaultStatusAcknowledged
+	}
+	if incident.Status == models.FaultStatusAcknowledged {
+		incident.AcknowledgedBy = acknowledgedBy
+		incident.AcknowledgedAt = &now
+	}
+
+	c.saveFaultState()
+	return nil
+}
+
+// ResolveIncident resolves an incident and all of its open member faults.
+// It returns the IDs of the faults that were resolved. A tenant only
+// resolves the faults of its devices, the incident follows once no member
+// is left open.
+func (c *Context) ResolveIncident(tenantID, incidentID, resolvedBy, category, resolution string) ([]string, error) {
+	if !models.IsValidResolutionCategory(category) {
+		return nil, models.ErrInvalidResolution
+	}
+
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+
+	incident, err := c.tenantOpenIncident(tenantID, incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resolved := make([]string, 0, len(incident.FaultIDs))
+	for _, faultID := range incident.FaultIDs {
+		fault, ok := c.faults[faultID]
+		if !ok || !isOpenFault(fault) || !c.deviceIndex.inTenant(fault.DeviceID, tenantID) {
+			continue
+		}
+		fault.Status = models.FaultStatusResolved
+		fault.ResolvedBy = resolvedBy
+		fault.ResolvedAt = &now
+		fault.ResolutionCategory = category
+		fault.Resolution = resolution
+		c.faultIndex.update(fault)
+		c.publishFaultEvent(EventFaultResolved, fault)
+		resolved = append(resolved, faultID)
+	}
+
+	if tenantID != "" {
+		c.refreshIncident(incidentID)
+	} else {
+		c.resolveIncident(incident, now)
+	}
+	if incident.Status == models.FaultStatusResolved {
+		incident.ResolvedBy = resolvedBy
+	}
+
+	c.saveFaultState()
+	return resolved, nil
+}
+
+// tenantOpenIncident looks up an incident that is not resolved and affects
+// a device of the tenant. Must be called with devicesMutex and faultsMutex
+// held.
+func (c *Context) tenantOpenIncident(tenantID, incidentID string) (*models.Incident, error) {
+	incident, exists := c.incidents[incidentID]
+	if !exists || (tenantID != "" && c.tenantIncident(tenantID, incident) == nil) {
+		return nil, models.ErrIncidentNotFound
+	}
+	if incident.Status == models.FaultStatusResolved {
+		return nil, models.ErrFaultAlreadyResolved
+	}
+	return incident, nil
+}
+
+// correlateFault attaches a newly seen fault to an open incident with the
+// same correlation key inside the window, or opens a new one.
+// Must be called with faultsMutex held.
+func (c *Context) correlateFault(fault *models.Fault) {
+	if !c.correlation.Enabled {
+		return
+	}
+
+	timestamp := fault.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	c.pruneIncidents(time.Now())
+
+	// Only the newest incident of a key takes new members, the older ones
+	// stopped correlating when it was opened
+	key := correlationKey(fault)
+	incident := c.incidentsByKey[key]
+	if incident != nil && (incident.Status == models.FaultStatusResolved ||
+		timestamp.Sub(incident.LastSeen) > c.correlation.Window || incident.FirstSeen.Sub(timestamp) > c.correlation.Window) {
+		incident = nil
+	}
+
+	if incident == nil {
+		incident = &models.Incident{
+			ID:          uuid.New().String(),
+			Key:         key,
+			Code:        fault.Code,
+			Channel:     fault.Channel,
+			DeviceModel: fault.DeviceModel,
+			Message:     fault.Message,
+			Severity:    fault.Severity,
+			Status:      models.FaultStatusActive,
+			FirstSeen:   timestamp,
+			LastSeen:    timestamp,
+		}
+		c.incidents[incident.ID] = incident
+		c.incidentsByKey[key] = incident
+	}
+
+	incident.FaultIDs = append(incident.FaultIDs, fault.ID)
+	incident.FaultCount = len(incident.FaultIDs)
+	if !containsString(incident.DeviceIDs, fault.DeviceID) {
+		incident.DeviceIDs = append(incident.DeviceIDs, fault.DeviceID)
+		incident.DeviceCount = len(incident.DeviceIDs)
+	}
+	if models.SeverityRank(fault.Severity) > models.SeverityRank(incident.Severity) {
+		incident.Severity = fault.Severity
+	}
+	if timestamp.After(incident.LastSeen) {
+		incident.LastSeen = timestamp
+	}
+	if timestamp.Before(incident.FirstSeen) {
+		incident.FirstSeen = timestamp
+	}
+
+	// A new active member reopens an acknowledged incident
+	if fault.Status == models.FaultStatusActive {
+		incident.Status = models.FaultStatusActive
+	}
+
+	fault.IncidentID = incident.ID
+}
+
+// refreshIncident recomputes the status of an incident from its member faults.
+// Must be called with faultsMutex held.
+func (c *Context) refreshIncident(incidentID string) {
+	incident, exists := c.incidents[incidentID]
+	if !exists {
+		return
+	}
+
+	active, acknowledged := 0, 0
+	for _, faultID := range incident.FaultIDs {
+		fault, ok := c.faults[faultID]
+		if !ok {
+			continue
+		}
+		switch fault.Status {
+		case models.FaultStatusActive:
+			active++
+		case models.FaultStatusAcknowledged:
+			acknowledged++
+		}
+	}
+
+	switch {
+	case active > 0:
+		incident.Status = models.FaultStatusActive
+	case acknowledged > 0:
+		incident.Status = models.FaultStatusAcknowledged
+	default:
+		if incident.Status != models.FaultStatusResolved {
+			c.resolveIncident(incident, time.Now())
+		}
+	}
+}
+
+// resolveIncident marks an incident resolved and queues it for eviction.
+// Must be called with faultsMutex held.
+func (c *Context) resolveIncident(incident *models.Incident, now time.Time) {
+	incident.Status = models.FaultStatusResolved
+	incident.ResolvedAt = &now
+	c.resolvedIncidents = append(c.resolvedIncidents, resolvedIncident{id: incident.ID, resolvedAt: now})
+}
+
+// pruneIncidents evicts the incidents resolved longer than the retention
+// ago. Incidents that reopened after they were queued are left alone.
+// Must be called with faultsMutex held.
+func (c *Context) pruneIncidents(now time.Time) {
+	retention := c.correlation.Retention
+	if retention <= 0 {
+		retention = DefaultIncidentRetention
+	}
+	cutoff := now.Add(-retention)
+
+	expired := 0
+	for _, queued := range c.resolvedIncidents {
+		if queued.resolvedAt.After(cutoff) {
+			break
+		}
+		expired++
+
+		incident, exists := c.incidents[queued.id]
+		if !exists || incident.Status != models.FaultStatusResolved || !incident.ResolvedAt.Equal(queued.resolvedAt) {
+			continue
+		}
+		delete(c.incidents, incident.ID)
+		if c.incidentsByKey[incident.Key] == incident {
+			delete(c.incidentsByKey, incident.Key)
+		}
+	}
+	if expired > 0 {
+		c.resolvedIncidents = append(c.resolvedIncidents[:0], c.resolvedIncidents[expired:]...)
+	}
+}
+
+// correlationKey builds the grouping key for a fault
+func correlationKey(fault *models.Fault) string {
+	return strings.Join([]string{fault.Code, fault.Channel, fault.DeviceModel}, "|")
+}
+
+// matchesIncidentFilter checks if an incident matches the given filter criteria
+func matchesIncidentFilter(incident *models.Incident, filter *models.IncidentFilter) bool {
+	if filter == nil {
+		return true
+	}
+
+	if filter.Status != "" && incident.Status != filter.Status {
+		return false
+	}
+
+	if filter.Severity != "" && incident.Severity != filter.Severity {
+		return false
+	}
+
+	if filter.Code != "" && incident.Code != filter.Code {
+		return false
+	}
+
+	if filter.Channel != "" && incident.Channel != filter.Channel {
+		return false
+	}
+
+	if filter.DeviceModel != "" && incident.DeviceModel != filter.DeviceModel {
+		return false
+	}
+
+	if filter.MinFaults > 0 && incident.FaultCount < filter.MinFaults {
+		return false
+	}
+
+	return true
+}
+
+// isOpenFault reports whether a fault still needs operator attention
+func isOpenFault(fault *models.Fault) bool {
+	return fault.Status == models.FaultStatusActive || fault.Status == models.FaultStatusAcknowledged
+}
+
+// containsString checks if a string slice contains a value
+func containsString(slice []string, value string) bool {
+	for _, s := range slice {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package context
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// newTestIncident creates a context with the devices of two tenants and
+// one incident of a fault on each device
+func newTestIncident(t *testing.T) (*Context, *models.Incident) {
+	t.Helper()
+	appContext := NewContext()
+	appContext.SetCorrelationConfig(CorrelationConfig{Enabled: true, Window: time.Hour})
+	appContext.SetTenants([]*models.Tenant{
+		{ID: "north", Selector: models.TenantSelector{OUIs: []string{"00A0B1"}}},
+		{ID: "south", Selector: models.TenantSelector{OUIs: []string{"00C0D2"}}},
+	})
+
+	now := time.Now()
+	for deviceID, oui := range map[string]string{"cpe-north": "00A0B1", "cpe-south": "00C0D2"} {
+		appContext.AddDevice(&models.Device{ID: deviceID, DeviceID: models.DeviceID{OUI: oui}})
+		appContext.AddFault(&models.Fault{
+			ID:          deviceID + ":wan",
+			DeviceID:    deviceID,
+			DeviceModel: "HG8245",
+			Code:        "wan.down",
+			Channel:     "wan",
+			Severity:    models.SeverityMajor,
+			Status:      models.FaultStatusActive,
+			Timestamp:   now,
+		})
+	}
+
+	incidents := appContext.GetIncidents(nil)
+	if len(incidents) != 1 || incidents[0].FaultCount != 2 {
+		t.Fatalf("faults correlated into %d incidents, want one of both faults", len(incidents))
+	}
+	return appContext, incidents[0]
+}
+
+func TestTenantIncidentView(t *testing.T) {
+	appContext, incident := newTestIncident(t)
+
+	scoped := appContext.FilterTenantIncidents("north", []*models.Incident{incident})
+	if len(scoped) != 1 {
+		t.Fatalf("tenant sees %d incidents, want 1", len(scoped))
+	}
+	view := scoped[0]
+	if len(view.DeviceIDs) != 1 || view.DeviceIDs[0] != "cpe-north" || view.DeviceCount != 1 {
+		t.Errorf("tenant sees devices %v", view.DeviceIDs)
+	}
+	if len(view.FaultIDs) != 1 || view.FaultIDs[0] != "cpe-north:wan" || view.FaultCount != 1 {
+		t.Errorf("tenant sees faults %v", view.FaultIDs)
+	}
+	if incident.FaultCount != 2 || len(incident.DeviceIDs) != 2 {
+		t.Error("scoping changed the stored incident")
+	}
+
+	if scoped := appContext.FilterTenantIncidents("west", []*models.Incident{incident}); len(scoped) != 0 {
+		t.Errorf("unrelated tenant sees %d incidents", len(scoped))
+	}
+}
+
+func TestTenantIncidentActions(t *testing.T) {
+	appContext, incident := newTestIncident(t)
+
+	status := func(faultID string) string {
+		fault, _ := appContext.GetFault(faultID)
+		return fault.Status
+	}
+
+	if err := appContext.AcknowledgeIncident("west", incident.ID, "mallory"); !errors.Is(err, models.ErrIncidentNotFound) {
+		t.Errorf("unrelated tenant acknowledged the incident: %v", err)
+	}
+
+	if err := appContext.AcknowledgeIncident("north", incident.ID, "alice"); err != nil {
+		t.Fatalf("AcknowledgeIncident: %v", err)
+	}
+	if status("cpe-north:wan") != models.FaultStatusAcknowledged || status("cpe-south:wan") != models.FaultStatusActive {
+		t.Errorf("acknowledged faults north %s, south %s", status("cpe-north:wan"), status("cpe-south:wan"))
+	}
+	if incident.Status != models.FaultStatusActive || incident.AcknowledgedBy != "" {
+		t.Errorf("incident %s by %q with a member of another tenant still active", incident.Status, incident.AcknowledgedBy)
+	}
+
+	resolved, err := appContext.ResolveIncident("north", incident.ID, "alice", models.ResolutionFixed, "")
+	if err != nil {
+		t.Fatalf("ResolveIncident: %v", err)
+	}
+	if len(resolved) != 1 || resolved[0] != "cpe-north:wan" || status("cpe-south:wan") != models.FaultStatusActive {
+		t.Errorf("tenant resolved %v", resolved)
+	}
+	if incident.Status == models.FaultStatusResolved {
+		t.Error("incident resolved with a member of another tenant still active")
+	}
+
+	// The last open member resolves the incident
+	if _, err := appContext.ResolveIncident("south", incident.ID, "bob", models.ResolutionFixed, ""); err != nil {
+		t.Fatalf("ResolveIncident: %v", err)
+	}
+	if incident.Status != models.FaultStatusResolved || incident.ResolvedBy != "bob" || incident.ResolvedAt == nil {
+		t.Errorf("incident %s by %q once every member is resolved", incident.Status, incident.ResolvedBy)
+	}
+}
+
+// wanFault is a fault correlated under the same key on every device
+func wanFault(deviceID string, timestamp time.Time) *models.Fault {
+	return &models.Fault{
+		ID:          deviceID + ":wan:" + timestamp.Format(time.RFC3339Nano),
+		DeviceID:    deviceID,
+		DeviceModel: "HG8245",
+		Code:        "wan.down",
+		Channel:     "wan",
+		Severity:    models.SeverityMajor,
+		Status:      models.FaultStatusActive,
+		Timestamp:   timestamp,
+	}
+}
+
+func TestCorrelateByKey(t *testing.T) {
+	appContext := NewContext()
+	appContext.SetCorrelationConfig(CorrelationConfig{Enabled: true, Window: time.Minute})
+	start := time.Now()
+
+	first, second, late := wanFault("cpe-1", start), wanFault("cpe-2", start.Add(30*time.Second)), wanFault("cpe-3", start.Add(5*time.Minute))
+	for _, fault := range []*models.Fault{first, second, late} {
+		appContext.AddFault(fault)
+	}
+	if first.IncidentID == "" || second.IncidentID != first.IncidentID {
+		t.Errorf("faults inside the window in incidents %q and %q", first.IncidentID, second.IncidentID)
+	}
+	if late.IncidentID == first.IncidentID {
+		t.Error("fault after the window joined the earlier incident")
+	}
+
+	// A resolved incident takes no new members
+	if _, err := appContext.ResolveIncident("", late.IncidentID, "alice", models.ResolutionFixed, ""); err != nil {
+		t.Fatalf("ResolveIncident: %v", err)
+	}
+	again := wanFault("cpe-4", start.Add(5*time.Minute+time.Second))
+	appContext.AddFault(again)
+	if again.IncidentID == "" || again.IncidentID == late.IncidentID {
+		t.Errorf("fault joined resolved incident %q", again.IncidentID)
+	}
+}
+
+func TestResolvedIncidentEviction(t *testing.T) {
+	appContext := NewContext()
+	appContext.SetCorrelationConfig(CorrelationConfig{Enabled: true, Window: time.Minute, Retention: time.Hour})
+	now := time.Now()
+
+	resolved, reopened, open := wanFault("cpe-1", now), wanFault("cpe-2", now.Add(time.Hour)), wanFault("cpe-3", now.Add(2*time.Hour))
+	for _, fault := range []*models.Fault{resolved, reopened, open} {
+		appContext.AddFault(fault)
+	}
+	for _, fault := range []*models.Fault{resolved, reopened} {
+		if _, err := appContext.ResolveIncident("", fault.IncidentID, "alice", models.ResolutionFixed, ""); err != nil {
+			t.Fatalf("ResolveIncident: %v", err)
+		}
+	}
+
+	appContext.faultsMutex.Lock()
+	// The fault of cpe-2 turns active again, which reopens its incident
+	appContext.faults[reopened.ID].Status = models.FaultStatusActive
+	appContext.refreshIncident(reopened.IncidentID)
+	appContext.pruneIncidents(now.Add(30 * time.Minute))
+	kept := len(appContext.incidents)
+	appContext.pruneIncidents(now.Add(2 * time.Hour))
+	appContext.faultsMutex.Unlock()
+
+	if kept != 3 {
+		t.Errorf("%d incidents kept inside the retention, want 3", kept)
+	}
+	if _, exists := appContext.GetIncident(resolved.IncidentID); exists {
+		t.Error("resolved incident kept past the retention")
+	}
+	for _, fault := range []*models.Fault{reopened, open} {
+		if _, exists := appContext.GetIncident(fault.IncidentID); !exists {
+			t.Errorf("open incident of %s evicted", fault.DeviceID)
+		}
+	}
+	if len(appContext.resolvedIncidents) != 0 || len(appContext.incidentsByKey) != 1 {
+		t.Errorf("%d incidents queued and %d keys indexed after eviction", len(appContext.resolvedIncidents), len(appContext.incidentsByKey))
+	}
+}
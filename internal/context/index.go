@@ -0,0 +1,322 @@
+package context
+
+import (
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// unknownStatsKey groups devices without a manufacturer or model in DeviceStats
+const unknownStatsKey = "Unknown"
+
+// idSet is a set of device or fault IDs
+type idSet map[string]struct{}
+
+// addToSet adds id to the set stored under key, creating the set on first use
+func addToSet(sets map[string]idSet, key, id string) {
+	set, exists := sets[key]
+	if !exists {
+		set = make(idSet)
+		sets[key] = set
+	}
+	set[id] = struct{}{}
+}
+
+// removeFromSet removes id from the set stored under key, dropping the set
+// once it is empty
+func removeFromSet(sets map[string]idSet, key, id string) {
+	set, exists := sets[key]
+	if !exists {
+		return
+	}
+	delete(set, id)
+	if len(set) == 0 {
+		delete(sets, key)
+	}
+}
+
+// deviceIndexEntry records the keys a device was indexed under, so a device
+// can be unindexed without looking at its stored snapshot
+type deviceIndexEntry struct {
+	serial       string
+	manufacturer string
+	model        string
+	tags         []string
+	tenants      []string
+	online       bool
+}
+
+// deviceIndex holds the secondary device indexes. The device counters of
+// DeviceStats are derived from the set sizes, so they are always current.
+// Devices are indexed under the tenants whose selector they match.
+// Guarded by devicesMutex.
+type deviceIndex struct {
+	entries        map[string]deviceIndexEntry
+	bySerial       map[string]idSet
+	byManufacturer map[string]idSet
+	byModel        map[string]idSet
+	byTag          map[string]idSet
+	byTenant       map[string]idSet
+	byOnline       map[bool]idSet
+
+	tenants []*models.Tenant
+}
+
+// newDeviceIndex creates an empty device index
+func newDeviceIndex() *deviceIndex {
+	return &deviceIndex{
+		entries:        make(map[string]deviceIndexEntry),
+		bySerial:       make(map[string]idSet),
+		byManufacturer: make(map[string]idSet),
+		byModel:        make(map[string]idSet),
+		byTag:          make(map[string]idSet),
+		byTenant:       make(map[string]idSet),
+		byOnline:       map[bool]idSet{true: make(idSet), false: make(idSet)},
+	}
+}
+
+// update indexes a device, replacing the keys it was indexed under before
+func (idx *deviceIndex) update(device *models.Device) {
+	idx.remove(device.ID)
+
+	entry := deviceIndexEntry{
+		serial:       device.DeviceID.SerialNumber,
+		manufacturer: device.DeviceID.Manufacturer,
+		model:        device.DeviceID.ModelName,
+		online:       device.Status.Online,
+	}
+	for tag, set := range device.Tags {
+		if set {
+			entry.tags = append(entry.tags, tag)
+		}
+	}
+	for _, tenant := range idx.tenants {
+		if tenant.Matches(device) {
+			entry.tenants = append(entry.tenants, tenant.ID)
+		}
+	}
+
+	if entry.serial != "" {
+		addToSet(idx.bySerial, entry.serial, device.ID)
+	}
+	addToSet(idx.byManufacturer, entry.manufacturer, device.ID)
+	addToSet(idx.byModel, entry.model, device.ID)
+	for _, tag := range entry.tags {
+		addToSet(idx.byTag, tag, device.ID)
+	}
+	for _, tenant := range entry.tenants {
+		addToSet(idx.byTenant, tenant, device.ID)
+	}
+	idx.byOnline[entry.online][device.ID] = struct{}{}
+
+	idx.entries[device.ID] = entry
+}
+
+// remove drops a device from every index
+func (idx *deviceIndex) remove(deviceID string) {
+	entry, exists := idx.entries[deviceID]
+	if !exists {
+		return
+	}
+
+	if entry.serial != "" {
+		removeFromSet(idx.bySerial, entry.serial, deviceID)
+	}
+	removeFromSet(idx.byManufacturer, entry.manufacturer, deviceID)
+	removeFromSet(idx.byModel, entry.model, deviceID)
+	for _, tag := range entry.tags {
+		removeFromSet(idx.byTag, tag, deviceID)
+	}
+	for _, tenant := range entry.tenants {
+		removeFromSet(idx.byTenant, tenant, deviceID)
+	}
+	delete(idx.byOnline[entry.online], deviceID)
+
+	delete(idx.entries, deviceID)
+}
+
+// candidates returns the smallest indexed set of device IDs that can match
+// the filter. ok is false when the filter uses no indexed field and every
+// device has to be checked.
+func (idx *deviceIndex) candidates(filter *models.DeviceFilter) (ids idSet, ok bool) {
+	if filter == nil {
+		return nil, false
+	}
+
+	consider := func(set idSet) {
+		if !ok || len(set) < len(ids) {
+			ids, ok = set, true
+		}
+	}
+
+	if filter.Manufacturer != "" {
+		consider(idx.byManufacturer[filter.Manufacturer])
+	}
+	if filter.ModelName != "" {
+		consider(idx.byModel[filter.ModelName])
+	}
+	if filter.Online != nil {
+		consider(idx.byOnline[*filter.Online])
+	}
+	for _, tag := range filter.Tags {
+		consider(idx.byTag[tag])
+	}
+	if filter.Tenant != "" {
+		consider(idx.byTenant[filter.Tenant])
+	}
+	return ids, ok
+}
+
+// inTenant reports whether a device is indexed under the tenant. Every
+// device is in the empty tenant.
+func (idx *deviceIndex) inTenant(deviceID, tenant string) bool {
+	if tenant == "" {
+		return true
+	}
+	_, exists := idx.byTenant[tenant][deviceID]
+	return exists
+}
+
+// fillTenantStats sets the device counters of stats from the devices of a
+// tenant
+func (idx *deviceIndex) fillTenantStats(stats *models.DeviceStats, tenant string) {
+	for deviceID := range idx.byTenant[tenant] {
+		entry := idx.entries[deviceID]
+
+		stats.TotalDevices++
+		if entry.online {
+			stats.OnlineDevices++
+		} else {
+			stats.OfflineDevices++
+		}
+
+		vendor := entry.manufacturer
+		if vendor == "" {
+			vendor = unknownStatsKey
+		}
+		stats.DevicesByVendor[vendor]++
+
+		model := entry.model
+		if model == "" {
+			model = unknownStatsKey
+		}
+		stats.DevicesByModel[model]++
+	}
+}
+
+// fillStats sets the device counters of stats
+func (idx *deviceIndex) fillStats(stats *models.DeviceStats) {
+	stats.TotalDevices = len(idx.entries)
+	stats.OnlineDevices = len(idx.byOnline[true])
+	stats.OfflineDevices = len(idx.byOnline[false])
+
+	for vendor, set := range idx.byManufacturer {
+		if vendor == "" {
+			vendor = unknownStatsKey
+		}
+		stats.DevicesByVendor[vendor] += len(set)
+	}
+	for model, set := range idx.byModel {
+		if model == "" {
+			model = unknownStatsKey
+		}
+		stats.DevicesByModel[model] += len(set)
+	}
+}
+
+// faultIndexEntry records how a fault was indexed and counted
+type faultIndexEntry struct {
+	deviceID   string
+	active     bool
+	suppressed bool
+	critical   bool
+}
+
+// faultIndex maps devices to their faults and keeps the fault counters of
+// DeviceStats. Guarded by faultsMutex.
+type faultIndex struct {
+	entries  map[string]faultIndexEntry
+	byDevice map[string]idSet
+
+	active     int
+	suppressed int
+	critical   int
+}
+
+// newFaultIndex creates an empty fault index
+func newFaultIndex() *faultIndex {
+	return &faultIndex{
+		entries:  make(map[string]faultIndexEntry),
+		byDevice: make(map[string]idSet),
+	}
+}
+
+// update indexes a fault and adjusts the counters to its current lifecycle
+// state
+func (idx *faultIndex) update(fault *models.Fault) {
+	idx.remove(fault.ID)
+
+	entry := faultIndexEntry{
+		deviceID: fault.DeviceID,
+		active:   fault.Status == models.FaultStatusActive || fault.Status == models.FaultStatusAcknowledged,
+	}
+	if entry.active {
+		// Suppressed faults are expected during maintenance, not counted
+		// as critical
+		entry.suppressed = fault.Suppressed
+		entry.critical = !fault.Suppressed && fault.Severity == models.SeverityCritical
+	}
+
+	addToSet(idx.byDevice, entry.deviceID, fault.ID)
+	idx.count(entry, 1)
+	idx.entries[fault.ID] = entry
+}
+
+// remove drops a fault from the index and the counters
+func (idx *faultIndex) remove(faultID string) {
+	entry, exists := idx.entries[faultID]
+	if !exists {
+		return
+	}
+
+	removeFromSet(idx.byDevice, entry.deviceID, faultID)
+	idx.count(entry, -1)
+	delete(idx.entries, faultID)
+}
+
+// count adds delta to the counters the entry contributes to
+func (idx *faultIndex) count(entry faultIndexEntry, delta int) {
+	if entry.active {
+		idx.active += delta
+	}
+	if entry.suppressed {
+		idx.suppressed += delta
+	}
+	if entry.critical {
+		idx.critical += delta
+	}
+}
+
+// fillStats sets the fault counters of stats
+func (idx *faultIndex) fillStats(stats *models.DeviceStats) {
+	stats.ActiveFaults = idx.active
+	stats.SuppressedFaults = idx.suppressed
+	stats.CriticalFaults = idx.critical
+}
+
+// fillDeviceStats sets the fault counters of stats from the faults of the
+// given devices
+func (idx *faultIndex) fillDeviceStats(stats *models.DeviceStats, deviceIDs idSet) {
+	for deviceID := range deviceIDs {
+		for faultID := range idx.byDevice[deviceID] {
+			entry := idx.entries[faultID]
+			if entry.active {
+				stats.ActiveFaults++
+			}
+			if entry.suppressed {
+				stats.SuppressedFaults++
+			}
+			if entry.critical {
+				stats.CriticalFaults++
+			}
+		}
+	}
+}
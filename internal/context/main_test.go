@@ -0,0 +1,14 @@
+package context
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	// Context operations log at debug level, keep the output readable
+	logger.SetLogLevel("error")
+	os.Exit(m.Run())
+}
@@ -0,0 +1,129 @@
+package context
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Maintenance Window Functions
+
+// AddMaintenanceWindow stores a new maintenance window and assigns its ID
+func (c *Context) AddMaintenanceWindow(window *models.MaintenanceWindow) error {
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	c.maintenanceMutex.Lock()
+	defer c.maintenanceMutex.Unlock()
+
+	now := time.Now()
+	if window.ID == "" {
+		window.ID = uuid.New().String()
+	}
+	window.CreatedAt = now
+	window.UpdatedAt = now
+	c.maintenanceWindows[window.ID] = window
+	return nil
+}
+
+// UpdateMaintenanceWindow replaces an existing maintenance window
+func (c *Context) UpdateMaintenanceWindow(window *models.MaintenanceWindow) error {
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	c.maintenanceMutex.Lock()
+	defer c.maintenanceMutex.Unlock()
+
+	existing, exists := c.maintenanceWindows[window.ID]
+	if !exists {
+		return models.ErrMaintenanceWindowNotFound
+	}
+
+	window.CreatedBy = existing.CreatedBy
+	window.CreatedAt = existing.CreatedAt
+	window.UpdatedAt = time.Now()
+	c.maintenanceWindows[window.ID] = window
+	return nil
+}
+
+// RemoveMaintenanceWindow deletes a maintenance window
+func (c *Context) RemoveMaintenanceWindow(windowID string) error {
+	c.maintenanceMutex.Lock()
+	defer c.maintenanceMutex.Unlock()
+
+	if _, exists := c.maintenanceWindows[windowID]; !exists {
+		return models.ErrMaintenanceWindowNotFound
+	}
+	delete(c.maintenanceWindows, windowID)
+	return nil
+}
+
+// GetMaintenanceWindow retrieves a maintenance window by ID
+func (c *Context) GetMaintenanceWindow(windowID string) (*models.MaintenanceWindow, bool) {
+	c.maintenanceMutex.RLock()
+	defer c.maintenanceMutex.RUnlock()
+	window, exists := c.maintenanceWindows[windowID]
+	return window, exists
+}
+
+// GetMaintenanceWindows returns all maintenance windows ordered by start time
+func (c *Context) GetMaintenanceWindows() []*models.MaintenanceWindow {
+	c.maintenanceMutex.RLock()
+	defer c.maintenanceMutex.RUnlock()
+
+	windows := make([]*models.MaintenanceWindow, 0, len(c.maintenanceWindows))
+	for _, window := range c.maintenanceWindows {
+		windows = append(windows, window)
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].StartTime.Before(windows[j].StartTime)
+	})
+	return windows
+}
+
+// GetActiveMaintenanceWindows returns the windows active at the given time
+func (c *Context) GetActiveMaintenanceWindows(at time.Time) []*models.MaintenanceWindow {
+	active := make([]*models.MaintenanceWindow, 0)
+	for _, window := range c.GetMaintenanceWindows() {
+		if window.IsActiveAt(at) {
+			active = append(active, window)
+		}
+	}
+	return active
+}
+
+// findSuppressingWindow returns the ID of an active maintenance window that
+// covers the device raising the fault, or an empty string.
+// Must be called without faultsMutex held.
+func (c *Context) findSuppressingWindow(fault *models.Fault) string {
+	at := fault.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	windows := c.GetActiveMaintenanceWindows(at)
+	if len(windows) == 0 {
+		return ""
+	}
+
+	model := fault.DeviceModel
+	var tags map[string]bool
+	if device, exists := c.GetDevice(fault.DeviceID); exists {
+		tags = device.Tags
+		if model == "" {
+			model = device.DeviceID.ModelName
+		}
+	}
+
+	for _, window := range windows {
+		if window.MatchesDevice(fault.DeviceID, model, tags) {
+			return window.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// SetFaultStateFile sets the file used to persist fault lifecycle state.
+// An empty path disables persistence.
+func (c *Context) SetFaultStateFile(path string) {
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+	c.faultStateFile = path
+}
+
+// LoadFaultState restores faults saved by a previous run. Open faults are
+// correlated again since incidents are not persisted.
+func (c *Context) LoadFaultState() error {
+	c.faultsMutex.Lock()
+	defer c.faultsMutex.Unlock()
+
+	if c.faultStateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.faultStateFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var faults []*models.Fault
+	if err := json.Unmarshal(data, &faults); err != nil {
+		return err
+	}
+
+	for _, fault := range faults {
+		fault.IncidentID = ""
+		c.faults[fault.ID] = fault
+		c.faultIndex.update(fault)
+		if isOpenFault(fault) {
+			c.correlateFault(fault)
+		}
+	}
+
+	logger.ContextLog.Infof("Loaded %d faults from %s", len(faults), c.faultStateFile)
+	return nil
+}
+
+// saveFaultState writes all faults to the state file. The file is replaced
+// atomically so a crash never leaves a truncated state behind.
+// Must be called with faultsMutex held.
+func (c *Context) saveFaultState() {
+	if c.faultStateFile == "" {
+		return
+	}
+
+	faults := make([]*models.Fault, 0, len(c.faults))
+	for _, fault := range c.faults {
+		faults = append(faults, fault)
+	}
+
+	data, err := json.Marshal(faults)
+	if err != nil {
+		logger.ContextLog.Errorf("Failed to encode fault state: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.faultStateFile), 0o755); err != nil {
+		logger.ContextLog.Errorf("Failed to create fault state directory: %v", err)
+		return
+	}
+
+	tmp := c.faultStateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		logger.ContextLog.Errorf("Failed to write fault state: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.faultStateFile); err != nil {
+		logger.ContextLog.Errorf("Failed to replace fault state: %v", err)
+	}
+}
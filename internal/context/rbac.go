@@ -0,0 +1,81 @@
+package context
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Role Functions
+
+// SetRoles sets the permissions of roles. Built-in roles that are not
+// given keep their default permissions.
+func (c *Context) SetRoles(roles map[string][]string) {
+	merged := defaultRoles()
+	for role, permissions := range roles {
+		merged[role] = slices.Clone(permissions)
+	}
+
+	c.rolesMutex.Lock()
+	defer c.rolesMutex.Unlock()
+	c.roles = merged
+}
+
+// IsValidRole checks if a role is built in or configured
+func (c *Context) IsValidRole(role string) bool {
+	c.rolesMutex.RLock()
+	defer c.rolesMutex.RUnlock()
+	_, exists := c.roles[role]
+	return exists
+}
+
+// GetRoles returns the names of all roles, sorted
+func (c *Context) GetRoles() []string {
+	c.rolesMutex.RLock()
+	defer c.rolesMutex.RUnlock()
+
+	roles := make([]string, 0, len(c.roles))
+	for role := range c.roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// RolePermissions returns the union of the permissions of the roles,
+// unknown roles grant nothing
+func (c *Context) RolePermissions(roles []string) []string {
+	c.rolesMutex.RLock()
+	defer c.rolesMutex.RUnlock()
+
+	var permissions []string
+	for _, role := range roles {
+		for _, permission := range c.roles[role] {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
+
+// NewPrincipal returns a principal with the permissions of its roles
+func (c *Context) NewPrincipal(name, method string, roles []string) *models.Principal {
+	return &models.Principal{
+		Name:        name,
+		Method:      method,
+		Roles:       roles,
+		Permissions: c.RolePermissions(roles),
+	}
+}
+
+// defaultRoles returns a copy of the built-in roles
+func defaultRoles() map[string][]string {
+	roles := make(map[string][]string, len(models.DefaultRolePermissions))
+	for role, permissions := range models.DefaultRolePermissions {
+		roles[role] = slices.Clone(permissions)
+	}
+	return roles
+}
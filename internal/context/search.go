@@ -0,0 +1,24 @@
+package context
+
+// DefaultSearchParameters are the parameter values searchable when none are
+// configured: PPPoE usernames and Wi-Fi SSIDs of TR-098 and TR-181 devices
+var DefaultSearchParameters = []string{
+	"InternetGatewayDevice.WANDevice.1.WANConnectionDevice.1.WANPPPConnection.1.Username",
+	"InternetGatewayDevice.LANDevice.1.WLANConfiguration.1.SSID",
+	"Device.PPP.Interface.1.Username",
+	"Device.WiFi.SSID.1.SSID",
+}
+
+// Device Search Functions
+
+// SetSearchParameters sets the parameter paths whose values are searchable
+// and reindexes the known devices
+func (c *Context) SetSearchParameters(parameters []string) {
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+
+	c.searchIndex.SetParameters(parameters)
+	for _, device := range c.devices {
+		c.searchIndex.Update(device)
+	}
+}
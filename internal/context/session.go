@@ -0,0 +1,138 @@
+package context
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// SessionConfig is the login policy of the web UI
+type SessionConfig struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+	MaxFailedLogins int
+	LockoutDuration time.Duration
+}
+
+// DefaultSessionConfig is used until SetSessionConfig is called
+var DefaultSessionConfig = SessionConfig{
+	IdleTimeout:     30 * time.Minute,
+	AbsoluteTimeout: 12 * time.Hour,
+	MaxFailedLogins: 5,
+	LockoutDuration: 15 * time.Minute,
+}
+
+// SetSessionConfig sets the login policy
+func (c *Context) SetSessionConfig(cfg SessionConfig) {
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+	c.sessionConfig = cfg
+}
+
+// GetSessionConfig returns the login policy
+func (c *Context) GetSessionConfig() SessionConfig {
+	c.sessionsMutex.RLock()
+	defer c.sessionsMutex.RUnlock()
+	return c.sessionConfig
+}
+
+// Session Management Functions
+
+// CreateSession starts a session for a user. Sessions are kept in memory
+// only, a restart logs everyone out.
+func (c *Context) CreateSession(userID, clientIP string) (*models.Session, error) {
+	id, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	csrfToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+
+	now := time.Now()
+	session := &models.Session{
+		ID:         id,
+		UserID:     userID,
+		CSRFToken:  csrfToken,
+		ClientIP:   clientIP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(c.sessionConfig.AbsoluteTimeout),
+	}
+	c.sessions[id] = session
+	c.pruneSessions(now)
+	return session, nil
+}
+
+// TouchSession returns a live session and records its use. Sessions past
+// their idle or absolute timeout are removed.
+func (c *Context) TouchSession(sessionID string) (*models.Session, error) {
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+
+	session, exists := c.sessions[sessionID]
+	if !exists {
+		return nil, models.ErrSessionNotFound
+	}
+
+	now := time.Now()
+	if c.sessionExpired(session, now) {
+		delete(c.sessions, sessionID)
+		return nil, models.ErrSessionNotFound
+	}
+
+	touched := *session
+	touched.LastSeenAt = now
+	c.sessions[sessionID] = &touched
+	return &touched, nil
+}
+
+// RemoveSession ends a session
+func (c *Context) RemoveSession(sessionID string) {
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+	delete(c.sessions, sessionID)
+}
+
+// RemoveUserSessions ends all sessions of a user
+func (c *Context) RemoveUserSessions(userID string) {
+	c.sessionsMutex.Lock()
+	defer c.sessionsMutex.Unlock()
+
+	for id, session := range c.sessions {
+		if session.UserID == userID {
+			delete(c.sessions, id)
+		}
+	}
+}
+
+// sessionExpired reports whether a session timed out. Must be called with
+// sessionsMutex held.
+func (c *Context) sessionExpired(session *models.Session, now time.Time) bool {
+	return !now.Before(session.ExpiresAt) || now.Sub(session.LastSeenAt) >= c.sessionConfig.IdleTimeout
+}
+
+// pruneSessions removes timed out sessions, so sessions that are never used
+// again do not pile up. Must be called with sessionsMutex held.
+func (c *Context) pruneSessions(now time.Time) {
+	for id, session := range c.sessions {
+		if c.sessionExpired(session, now) {
+			delete(c.sessions, id)
+		}
+	}
+}
+
+// randomToken returns 256 random bits, URL-safe encoded
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,173 @@
+package context
+
+import (
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// maxUpdateAttempts bounds how often UpdateDevice retries after losing a
+// compare-and-swap race
+const maxUpdateAttempts = 10
+
+// Device change types
+const (
+	DeviceChangeAdded   = "added"
+	DeviceChangeUpdated = "updated"
+	DeviceChangeRemoved = "removed"
+)
+
+// DeviceChange is a change of the device store delivered to watchers.
+// Device is nil for a removed device and Previous is nil for an added one.
+type DeviceChange struct {
+	Type      string         `json:"type"`
+	DeviceID  string         `json:"deviceId"`
+	Version   uint64         `json:"version"`
+	Device    *models.Device `json:"device,omitempty"`
+	Previous  *models.Device `json:"-"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// Device Snapshot Functions
+//
+// Devices held by the context are immutable snapshots shared by every
+// reader. A change never modifies a stored device, it stores a new one:
+// AddDevice replaces a device unconditionally with what GenieACS reported,
+// CompareAndSwapDevice and UpdateDevice apply local changes only on top of
+// the version they were derived from.
+
+// GetDeviceSnapshot returns a private copy of a device that the caller may
+// modify and pass to CompareAndSwapDevice
+func (c *Context) GetDeviceSnapshot(deviceID string) (*models.Device, bool) {
+	device, exists := c.GetDevice(deviceID)
+	if !exists {
+		return nil, false
+	}
+	return device.Clone(), true
+}
+
+// CompareAndSwapDevice stores device if the stored version still equals
+// device.Version, a version of 0 creates a device that does not exist yet.
+// The context takes ownership of device, the caller must not modify it
+// afterwards.
+func (c *Context) CompareAndSwapDevice(device *models.Device) error {
+	c.devicesMutex.Lock()
+	defer c.devicesMutex.Unlock()
+
+	previous, exists := c.devices[device.ID]
+	if !exists && device.Version != 0 {
+		return models.ErrDeviceNotFound
+	}
+	if exists && (previous == device || previous.Version != device.Version) {
+		return models.ErrDeviceConflict
+	}
+
+	c.storeDevice(previous, device)
+	return nil
+}
+
+// UpdateDevice applies update to a private copy of a device and stores the
+// result, which is returned as the new shared snapshot. When another writer
+// stores the device first, update runs again on the newer version. An error
+// returned by update aborts the change.
+func (c *Context) UpdateDevice(deviceID string, update func(device *models.Device) error) (*models.Device, error) {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		device, exists := c.GetDeviceSnapshot(deviceID)
+		if !exists {
+			return nil, models.ErrDeviceNotFound
+		}
+		if err := update(device); err != nil {
+			return nil, err
+		}
+
+		err := c.CompareAndSwapDevice(device)
+		if err == nil {
+			return device, nil
+		}
+		if err != models.ErrDeviceConflict {
+			return nil, err
+		}
+	}
+	return nil, models.ErrDeviceConflict
+}
+
+// WatchDevices subscribes to device changes. Changes are delivered in the
+// order they were stored. A watcher that falls more than buffer changes
+// behind is dropped and its channel closed, it should list the devices
+// again and start a new watch. cancel ends the subscription.
+func (c *Context) WatchDevices(buffer int) (changes <-chan DeviceChange, cancel func()) {
+	c.devicesMutex.Lock()
+	defer c.devicesMutex.Unlock()
+
+	c.nextWatcherID++
+	id := c.nextWatcherID
+	watch := make(chan DeviceChange, buffer)
+	c.deviceWatchers[id] = watch
+
+	cancel = func() {
+		c.devicesMutex.Lock()
+		defer c.devicesMutex.Unlock()
+		if _, active := c.deviceWatchers[id]; active {
+			delete(c.deviceWatchers, id)
+			close(watch)
+		}
+	}
+	return watch, cancel
+}
+
+// storeDevice replaces the stored snapshot of a device, previous is nil for
+// a new device. Must be called with devicesMutex held.
+func (c *Context) storeDevice(previous, device *models.Device) {
+	change := DeviceChange{
+		Type:      DeviceChangeAdded,
+		DeviceID:  device.ID,
+		Device:    device,
+		Previous:  previous,
+		Timestamp: time.Now(),
+	}
+
+	device.Version = 1
+	if previous != nil {
+		device.Version = previous.Version + 1
+		change.Type = DeviceChangeUpdated
+	}
+	change.Version = device.Version
+
+	c.recordDeviceChanges(previous, device)
+	c.devices[device.ID] = device
+	c.deviceIndex.update(device)
+	c.searchIndex.Update(device)
+	c.notifyDeviceWatchers(change)
+	c.publishDeviceEvents(previous, device)
+}
+
+// deleteDevice removes a stored device. Must be called with devicesMutex
+// held.
+func (c *Context) deleteDevice(previous *models.Device) {
+	delete(c.devices, previous.ID)
+	c.deviceIndex.remove(previous.ID)
+	c.searchIndex.Remove(previous.ID)
+	c.notifyDeviceWatchers(DeviceChange{
+		Type:      DeviceChangeRemoved,
+		DeviceID:  previous.ID,
+		Version:   previous.Version,
+		Previous:  previous,
+		Timestamp: time.Now(),
+	})
+	c.eventBus.Publish(Event{Type: EventDeviceRemoved, DeviceID: previous.ID, Device: previous})
+}
+
+// notifyDeviceWatchers delivers a change without blocking the writer.
+// Must be called with devicesMutex held.
+func (c *Context) notifyDeviceWatchers(change DeviceChange) {
+	for id, watch := range c.deviceWatchers {
+		select {
+		case watch <- change:
+		default:
+			logger.ContextLog.Warnf("Device watcher %d fell behind, dropping it", id)
+			delete(c.deviceWatchers, id)
+			close(watch)
+		}
+	}
+}
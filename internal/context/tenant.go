@@ -0,0 +1,202 @@
+package context
+
+import (
+	"sort"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Tenant Functions
+
+// SetTenants replaces the tenants and reindexes the devices under them
+func (c *Context) SetTenants(tenants []*models.Tenant) {
+	c.devicesMutex.Lock()
+	defer c.devicesMutex.Unlock()
+
+	c.deviceIndex.tenants = tenants
+	for _, device := range c.devices {
+		c.deviceIndex.update(device)
+	}
+}
+
+// GetTenants returns all tenants, sorted by ID
+func (c *Context) GetTenants() []*models.Tenant {
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+
+	tenants := make([]*models.Tenant, len(c.deviceIndex.tenants))
+	copy(tenants, c.deviceIndex.tenants)
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].ID < tenants[j].ID
+	})
+	return tenants
+}
+
+// GetTenant retrieves a tenant by ID
+func (c *Context) GetTenant(tenantID string) (*models.Tenant, bool) {
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+	return c.findTenant(tenantID)
+}
+
+// IsValidTenant checks if a tenant is configured
+func (c *Context) IsValidTenant(tenantID string) bool {
+	_, exists := c.GetTenant(tenantID)
+	return exists
+}
+
+// DeviceInTenant reports whether a known device belongs to the tenant. Every
+// device is in the empty tenant of callers that see across tenants.
+func (c *Context) DeviceInTenant(tenantID, deviceID string) bool {
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+	return c.deviceIndex.inTenant(deviceID, tenantID)
+}
+
+// FilterTenantDevices returns the devices of the tenant. The devices need
+// not be known to the context, such as those fetched from GenieACS.
+func (c *Context) FilterTenantDevices(tenantID string, devices []*models.Device) []*models.Device {
+	if tenantID == "" {
+		return devices
+	}
+
+	tenant, exists := c.GetTenant(tenantID)
+	filtered := make([]*models.Device, 0, len(devices))
+	if !exists {
+		return filtered
+	}
+	for _, device := range devices {
+		if tenant.Matches(device) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered
+}
+
+// FilterTenantFaults returns the faults of devices of the tenant
+func (c *Context) FilterTenantFaults(tenantID string, faults []*models.Fault) []*models.Fault {
+	if tenantID == "" {
+		return faults
+	}
+
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+
+	filtered := make([]*models.Fault, 0, len(faults))
+	for _, fault := range faults {
+		if c.deviceIndex.inTenant(fault.DeviceID, tenantID) {
+			filtered = append(filtered, fault)
+		}
+	}
+	return filtered
+}
+
+// FilterTenantTasks returns the tasks of devices of the tenant
+func (c *Context) FilterTenantTasks(tenantID string, tasks []*models.Task) []*models.Task {
+	if tenantID == "" {
+		return tasks
+	}
+
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+
+	filtered := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if c.deviceIndex.inTenant(task.DeviceID, tenantID) {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+// FilterTenantIncidents returns the incidents that affect a device of the
+// tenant. For a tenant they are copies listing only the devices and faults
+// of the tenant, other tenants do not show through a shared incident.
+func (c *Context) FilterTenantIncidents(tenantID string, incidents []*models.Incident) []*models.Incident {
+	if tenantID == "" {
+		return incidents
+	}
+
+	c.devicesMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+	c.faultsMutex.RLock()
+	defer c.faultsMutex.RUnlock()
+
+	filtered := make([]*models.Incident, 0, len(incidents))
+	for _, incident := range incidents {
+		if scoped := c.tenantIncident(tenantID, incident); scoped != nil {
+			filtered = append(filtered, scoped)
+		}
+	}
+	return filtered
+}
+
+// tenantIncident copies an incident limited to the devices and faults of a
+// tenant, nil when it affects none of its devices. Must be called with
+// devicesMutex and faultsMutex held.
+func (c *Context) tenantIncident(tenantID string, incident *models.Incident) *models.Incident {
+	deviceIDs := make([]string, 0, len(incident.DeviceIDs))
+	for _, deviceID := range incident.DeviceIDs {
+		if c.deviceIndex.inTenant(deviceID, tenantID) {
+			deviceIDs = append(deviceIDs, deviceID)
+		}
+	}
+	if len(deviceIDs) == 0 {
+		return nil
+	}
+
+	faultIDs := make([]string, 0, len(incident.FaultIDs))
+	for _, faultID := range incident.FaultIDs {
+		if fault, ok := c.faults[faultID]; ok && c.deviceIndex.inTenant(fault.DeviceID, tenantID) {
+			faultIDs = append(faultIDs, faultID)
+		}
+	}
+
+	scoped := *incident
+	scoped.DeviceIDs = deviceIDs
+	scoped.DeviceCount = len(deviceIDs)
+	scoped.FaultIDs = faultIDs
+	scoped.FaultCount = len(faultIDs)
+	return &scoped
+}
+
+// GetTenantDeviceStats returns the device statistics of a tenant, or of all
+// devices for the empty tenant. Unlike GetDeviceStats they are counted on
+// each call, at a cost proportional to the devices of the tenant.
+func (c *Context) GetTenantDeviceStats(tenantID string) *models.DeviceStats {
+	if tenantID == "" {
+		return c.GetDeviceStats()
+	}
+
+	c.devicesMutex.RLock()
+	c.faultsMutex.RLock()
+	defer c.devicesMutex.RUnlock()
+	defer c.faultsMutex.RUnlock()
+
+	stats := &models.DeviceStats{
+		DevicesByVendor: make(map[string]int),
+		DevicesByModel:  make(map[string]int),
+	}
+	c.deviceIndex.fillTenantStats(stats, tenantID)
+	c.faultIndex.fillDeviceStats(stats, c.deviceIndex.byTenant[tenantID])
+	return stats
+}
+
+// validateTenant checks that a user or API key is given a configured
+// tenant, or none
+func (c *Context) validateTenant(tenantID string) error {
+	if tenantID != "" && !c.IsValidTenant(tenantID) {
+		return models.ValidationErrors{Errors: []models.ValidationError{{Field: "tenant", Message: "unknown tenant " + tenantID}}}
+	}
+	return nil
+}
+
+// findTenant looks up a tenant. Must be called with devicesMutex held.
+func (c *Context) findTenant(tenantID string) (*models.Tenant, bool) {
+	for _, tenant := range c.deviceIndex.tenants {
+		if tenant.ID == tenantID {
+			return tenant, true
+		}
+	}
+	return nil, false
+}
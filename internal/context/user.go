@@ -0,0 +1,394 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// dummyPasswordHash is compared against for unknown usernames, so a login
+// takes as long whether the user exists or not
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("nextranet-gateway"), bcrypt.DefaultCost)
+
+// User Management Functions
+
+// AddUser creates a local user with a password
+func (c *Context) AddUser(user *models.User, password string) error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	if !c.IsValidRole(user.Role) {
+		return models.ValidationErrors{Errors: []models.ValidationError{{Field: "role", Message: "unknown role " + user.Role}}}
+	}
+	if err := c.validateTenant(user.Tenant); err != nil {
+		return err
+	}
+	if err := models.ValidatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	c.usersMutex.Lock()
+	defer c.usersMutex.Unlock()
+
+	if _, exists := c.usersByName[user.Username]; exists {
+		return models.ErrUserExists
+	}
+
+	now := time.Now()
+	user.ID = uuid.New().String()
+	user.PasswordHash = string(hash)
+	user.FailedLogins = 0
+	user.LockedUntil = nil
+	user.LastLoginAt = nil
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	c.users[user.ID] = user
+	c.usersByName[user.Username] = user.ID
+	c.saveUserState()
+	return nil
+}
+
+// UpdateUser changes the role, tenant and disabled flag of a user, and its
+// password unless password is empty. Any change clears a lockout. Changing
+// the password or disabling the user ends its sessions.
+func (c *Context) UpdateUser(userID, role, tenant string, disabled bool, password string) (*models.User, error) {
+	if !c.IsValidRole(role) {
+		return nil, models.ValidationErrors{Errors: []models.ValidationError{{Field: "role", Message: "unknown role " + role}}}
+	}
+	if err := c.validateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	var hash []byte
+	if password != "" {
+		if existing, exists := c.GetUser(userID); exists && existing.IsExternal() {
+			return nil, models.ErrExternalUser
+		}
+		if err := models.ValidatePassword(password); err != nil {
+			return nil, err
+		}
+		var err error
+		if hash, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
+			return nil, err
+		}
+	}
+
+	c.usersMutex.Lock()
+	existing, exists := c.users[userID]
+	if !exists {
+		c.usersMutex.Unlock()
+		return nil, models.ErrUserNotFound
+	}
+
+	updated := *existing
+	updated.Role = role
+	updated.Tenant = tenant
+	updated.Disabled = disabled
+	updated.FailedLogins = 0
+	updated.LockedUntil = nil
+	if hash != nil {
+		updated.PasswordHash = string(hash)
+	}
+	updated.UpdatedAt = time.Now()
+	c.users[userID] = &updated
+	c.saveUserState()
+	c.usersMutex.Unlock()
+
+	if hash != nil || disabled {
+		c.RemoveUserSessions(userID)
+	}
+	return &updated, nil
+}
+
+// ChangeUserPassword sets a new password after checking the current one,
+// and ends all sessions of the user
+func (c *Context) ChangeUserPassword(userID, currentPassword, newPassword string) error {
+	if err := models.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+
+	user, exists := c.GetUser(userID)
+	if !exists {
+		return models.ErrUserNotFound
+	}
+	if user.IsExternal() {
+		return models.ErrExternalUser
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)) != nil {
+		return models.ErrInvalidCredentials
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	c.usersMutex.Lock()
+	current, exists := c.users[userID]
+	if !exists {
+		c.usersMutex.Unlock()
+		return models.ErrUserNotFound
+	}
+	updated := *current
+	updated.PasswordHash = string(hash)
+	updated.UpdatedAt = time.Now()
+	c.users[userID] = &updated
+	c.saveUserState()
+	c.usersMutex.Unlock()
+
+	c.RemoveUserSessions(userID)
+	return nil
+}
+
+// RemoveUser deletes a user and ends its sessions
+func (c *Context) RemoveUser(userID string) error {
+	c.usersMutex.Lock()
+	user, exists := c.users[userID]
+	if !exists {
+		c.usersMutex.Unlock()
+		return models.ErrUserNotFound
+	}
+	delete(c.users, userID)
+	delete(c.usersByName, user.Username)
+	c.saveUserState()
+	c.usersMutex.Unlock()
+
+	c.RemoveUserSessions(userID)
+	return nil
+}
+
+// GetUser retrieves a user by ID. Stored users are replaced, never
+// modified, so the result may be read without holding a lock.
+func (c *Context) GetUser(userID string) (*models.User, bool) {
+	c.usersMutex.RLock()
+	defer c.usersMutex.RUnlock()
+	user, exists := c.users[userID]
+	return user, exists
+}
+
+// GetUsers returns all users sorted by username
+func (c *Context) GetUsers() []*models.User {
+	c.usersMutex.RLock()
+	defer c.usersMutex.RUnlock()
+
+	users := make([]*models.User, 0, len(c.users))
+	for _, user := range c.users {
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users
+}
+
+// AuthenticateUser checks a username and password. Failed logins are
+// counted, and after MaxFailedLogins of them in a row the user is locked
+// for LockoutDuration. Unknown and disabled users fail like a wrong
+// password.
+func (c *Context) AuthenticateUser(username, password string) (*models.User, error) {
+	policy := c.GetSessionConfig()
+
+	c.usersMutex.RLock()
+	var user *models.User
+	if userID, exists := c.usersByName[username]; exists {
+		user = c.users[userID]
+	}
+	c.usersMutex.RUnlock()
+
+	if user == nil || user.IsExternal() {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, models.ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, models.ErrUserLocked
+	}
+
+	// Compare outside the lock, bcrypt is slow on purpose
+	matched := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+
+	c.usersMutex.Lock()
+	defer c.usersMutex.Unlock()
+
+	// The user may have changed meanwhile, record the result on the
+	// current version. Concurrent attempts may have locked it, and a new
+	// password makes the comparison void.
+	current, exists := c.users[user.ID]
+	if !exists || current.IsExternal() {
+		return nil, models.ErrInvalidCredentials
+	}
+	if current.IsLocked(now) {
+		return nil, models.ErrUserLocked
+	}
+	if current.PasswordHash != user.PasswordHash {
+		return nil, models.ErrInvalidCredentials
+	}
+	updated := *current
+
+	if !matched || updated.Disabled {
+		if !matched {
+			updated.FailedLogins++
+			if policy.MaxFailedLogins > 0 && updated.FailedLogins >= policy.MaxFailedLogins {
+				lockedUntil := now.Add(policy.LockoutDuration)
+				updated.LockedUntil = &lockedUntil
+				updated.FailedLogins = 0
+				logger.ContextLog.Warnf("Locked user %s until %s after failed logins", updated.Username, lockedUntil.Format(time.RFC3339))
+			}
+			c.users[updated.ID] = &updated
+			c.saveUserState()
+		}
+		return nil, models.ErrInvalidCredentials
+	}
+
+	updated.FailedLogins = 0
+	updated.LockedUntil = nil
+	updated.LastLoginAt = &now
+	c.users[updated.ID] = &updated
+	c.saveUserState()
+	return &updated, nil
+}
+
+// LoginExternalUser records a single sign-on login. The user is created on
+// its first login, later logins update its role and tenant. A username that
+// belongs to a local user or to another subject is refused, so an identity
+// provider cannot take over existing accounts.
+func (c *Context) LoginExternalUser(provider, subject, username, role, tenant string) (*models.User, error) {
+	user := &models.User{
+		Username:  username,
+		Role:      role,
+		Tenant:    tenant,
+		Provider:  provider,
+		Subject:   subject,
+		CreatedBy: provider,
+	}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	if !c.IsValidRole(role) {
+		return nil, models.ValidationErrors{Errors: []models.ValidationError{{Field: "role", Message: "unknown role " + role}}}
+	}
+	if err := c.validateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	c.usersMutex.Lock()
+	defer c.usersMutex.Unlock()
+
+	now := time.Now()
+	if userID, exists := c.usersByName[username]; exists {
+		existing := c.users[userID]
+		if existing.Provider != provider || existing.Subject != subject {
+			return nil, models.ErrUserExists
+		}
+		if existing.Disabled {
+			return nil, models.ErrInvalidCredentials
+		}
+
+		updated := *existing
+		updated.Role = role
+		updated.Tenant = tenant
+		updated.LastLoginAt = &now
+		updated.UpdatedAt = now
+		c.users[userID] = &updated
+		c.saveUserState()
+		return &updated, nil
+	}
+
+	user.ID = uuid.New().String()
+	user.LastLoginAt = &now
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	c.users[user.ID] = user
+	c.usersByName[user.Username] = user.ID
+	c.saveUserState()
+	return user, nil
+}
+
+// User Persistence Functions
+
+// SetUserStateFile sets the file used to persist users. An empty path
+// disables persistence.
+func (c *Context) SetUserStateFile(path string) {
+	c.usersMutex.Lock()
+	defer c.usersMutex.Unlock()
+	c.userStateFile = path
+}
+
+// LoadUserState restores the users saved by a previous run
+func (c *Context) LoadUserState() error {
+	c.usersMutex.Lock()
+	defer c.usersMutex.Unlock()
+
+	if c.userStateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.userStateFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var users []*models.User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		c.users[user.ID] = user
+		c.usersByName[user.Username] = user.ID
+	}
+
+	logger.ContextLog.Infof("Loaded %d users from %s", len(users), c.userStateFile)
+	return nil
+}
+
+// saveUserState writes all users with their password hashes to the state
+// file. Must be called with usersMutex held.
+func (c *Context) saveUserState() {
+	if c.userStateFile == "" {
+		return
+	}
+
+	users := make([]*models.User, 0, len(c.users))
+	for _, user := range c.users {
+		users = append(users, user)
+	}
+
+	data, err := json.Marshal(users)
+	if err != nil {
+		logger.ContextLog.Errorf("Failed to encode user state: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.userStateFile), 0o755); err != nil {
+		logger.ContextLog.Errorf("Failed to create user state directory: %v", err)
+		return
+	}
+
+	tmp := c.userStateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		logger.ContextLog.Errorf("Failed to write user state: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.userStateFile); err != nil {
+		logger.ContextLog.Errorf("Failed to replace user state: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package context
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+const testPassword = "Correct-Horse-42"
+
+// newTestUser creates a context with one operator and a lockout policy
+func newTestUser(t *testing.T, maxFailedLogins int) (*Context, *models.User) {
+	t.Helper()
+	appContext := NewContext()
+	appContext.SetSessionConfig(SessionConfig{
+		IdleTimeout:     time.Hour,
+		AbsoluteTimeout: time.Hour,
+		MaxFailedLogins: maxFailedLogins,
+		LockoutDuration: time.Hour,
+	})
+
+	user := &models.User{Username: "alice", Role: models.RoleOperator}
+	if err := appContext.AddUser(user, testPassword); err != nil {
+		t.Fatalf("AddUser: %v", err)
+	}
+	return appContext, user
+}
+
+func TestAuthenticateUserLockout(t *testing.T) {
+	appContext, user := newTestUser(t, 3)
+
+	for i := 0; i < 3; i++ {
+		if _, err := appContext.AuthenticateUser("alice", "wrong"); !errors.Is(err, models.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: %v, want invalid credentials", i, err)
+		}
+	}
+	if _, err := appContext.AuthenticateUser("alice", testPassword); !errors.Is(err, models.ErrUserLocked) {
+		t.Fatalf("correct password on a locked user: %v, want locked", err)
+	}
+
+	// Unlocking by an administrator lets the user in again
+	if _, err := appContext.UpdateUser(user.ID, models.RoleOperator, "", false, ""); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if _, err := appContext.AuthenticateUser("alice", testPassword); err != nil {
+		t.Fatalf("login after unlock: %v", err)
+	}
+}
+
+// TestConcurrentFailedLogins checks that attempts racing past the first
+// lockout check neither count once the user is locked nor renew the lock
+func TestConcurrentFailedLogins(t *testing.T) {
+	const maxFailedLogins, attempts = 3, 8
+	appContext, user := newTestUser(t, maxFailedLogins)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[error]int)
+	)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := appContext.AuthenticateUser("alice", "wrong")
+			mu.Lock()
+			results[err]++
+			mu.Unlock()
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if results[models.ErrInvalidCredentials] != maxFailedLogins || results[models.ErrUserLocked] != attempts-maxFailedLogins {
+		t.Errorf("got %d invalid credentials and %d locked, want %d and %d",
+			results[models.ErrInvalidCredentials], results[models.ErrUserLocked], maxFailedLogins, attempts-maxFailedLogins)
+	}
+
+	current, _ := appContext.GetUser(user.ID)
+	if !current.IsLocked(time.Now()) || current.FailedLogins != 0 {
+		t.Errorf("user locked %t with %d failed logins, want locked with none counted", current.IsLocked(time.Now()), current.FailedLogins)
+	}
+}
@@ -0,0 +1,390 @@
+package context
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// DefaultWebhookDeliveryHistory is the number of deliveries kept per webhook
+const DefaultWebhookDeliveryHistory = 500
+
+// Webhook Functions
+
+// AddWebhook stores a new webhook and assigns its ID. A signing secret is
+// generated when none is given.
+func (c *Context) AddWebhook(webhook *models.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	now := time.Now()
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	c.webhooks[webhook.ID] = webhook
+	c.saveWebhookState()
+	return nil
+}
+
+// UpdateWebhook replaces an existing webhook. The secret is kept when the
+// update does not set one.
+func (c *Context) UpdateWebhook(webhook *models.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	existing, exists := c.webhooks[webhook.ID]
+	if !exists {
+		return models.ErrWebhookNotFound
+	}
+
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+	webhook.CreatedBy = existing.CreatedBy
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.UpdatedAt = time.Now()
+	c.webhooks[webhook.ID] = webhook
+	c.saveWebhookState()
+	return nil
+}
+
+// RemoveWebhook deletes a webhook together with its delivery history
+func (c *Context) RemoveWebhook(webhookID string) error {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	if _, exists := c.webhooks[webhookID]; !exists {
+		return models.ErrWebhookNotFound
+	}
+	delete(c.webhooks, webhookID)
+	for _, deliveryID := range c.deliveriesByWebhook[webhookID] {
+		delete(c.webhookDeliveries, deliveryID)
+	}
+	delete(c.deliveriesByWebhook, webhookID)
+	c.saveWebhookState()
+	return nil
+}
+
+// GetWebhook retrieves a webhook by ID. Stored webhooks are replaced, never
+// modified, so the result may be read without holding a lock.
+func (c *Context) GetWebhook(webhookID string) (*models.Webhook, bool) {
+	c.webhooksMutex.RLock()
+	defer c.webhooksMutex.RUnlock()
+	webhook, exists := c.webhooks[webhookID]
+	return webhook, exists
+}
+
+// GetWebhooks returns all webhooks, oldest first
+func (c *Context) GetWebhooks() []*models.Webhook {
+	c.webhooksMutex.RLock()
+	defer c.webhooksMutex.RUnlock()
+
+	webhooks := make([]*models.Webhook, 0, len(c.webhooks))
+	for _, webhook := range c.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return webhooks
+}
+
+// validateWebhook checks a webhook including its event type filters
+func validateWebhook(webhook *models.Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		return err
+	}
+	for _, pattern := range webhook.Events {
+		if !IsValidEventPattern(pattern) {
+			return models.ValidationErrors{Errors: []models.ValidationError{{Field: "events", Message: "unknown event type " + pattern}}}
+		}
+	}
+	return nil
+}
+
+// generateWebhookSecret returns a random 256 bit signing key
+func generateWebhookSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Webhook Delivery Functions
+
+// SetWebhookDeliveryHistory sets the number of deliveries kept per webhook.
+// Older deliveries are dropped once they are delivered or dead.
+func (c *Context) SetWebhookDeliveryHistory(size int) {
+	if size <= 0 {
+		size = DefaultWebhookDeliveryHistory
+	}
+
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+	c.webhookHistory = size
+}
+
+// AddWebhookDelivery queues a delivery for its first attempt
+func (c *Context) AddWebhookDelivery(delivery *models.WebhookDelivery) error {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	if _, exists := c.webhooks[delivery.WebhookID]; !exists {
+		return models.ErrWebhookNotFound
+	}
+	c.queueWebhookDelivery(delivery)
+	return nil
+}
+
+// RedeliverWebhookDelivery queues a new delivery of the same payload with a
+// fresh set of attempts
+func (c *Context) RedeliverWebhookDelivery(webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	original, exists := c.webhookDeliveries[deliveryID]
+	if !exists || original.WebhookID != webhookID {
+		return nil, models.ErrWebhookDeliveryNotFound
+	}
+	if _, exists := c.webhooks[webhookID]; !exists {
+		return nil, models.ErrWebhookNotFound
+	}
+
+	delivery := &models.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		EventType:     original.EventType,
+		EventSequence: original.EventSequence,
+		DeviceID:      original.DeviceID,
+		Payload:       original.Payload,
+		RedeliveryOf:  original.ID,
+	}
+	c.queueWebhookDelivery(delivery)
+	return cloneDelivery(delivery), nil
+}
+
+// GetWebhookDelivery retrieves a copy of a delivery of a webhook
+func (c *Context) GetWebhookDelivery(webhookID, deliveryID string) (*models.WebhookDelivery, bool) {
+	c.webhooksMutex.RLock()
+	defer c.webhooksMutex.RUnlock()
+
+	delivery, exists := c.webhookDeliveries[deliveryID]
+	if !exists || delivery.WebhookID != webhookID {
+		return nil, false
+	}
+	return cloneDelivery(delivery), true
+}
+
+// GetWebhookDeliveries returns copies of the deliveries of a webhook, newest
+// first, optionally only those with the given status
+func (c *Context) GetWebhookDeliveries(webhookID, status string) []*models.WebhookDelivery {
+	c.webhooksMutex.RLock()
+	defer c.webhooksMutex.RUnlock()
+
+	ids := c.deliveriesByWebhook[webhookID]
+	deliveries := make([]*models.WebhookDelivery, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		delivery := c.webhookDeliveries[ids[i]]
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+	return deliveries
+}
+
+// ClaimDueWebhookDeliveries returns copies of at most limit deliveries whose
+// next attempt is due. Claimed deliveries are not due again before lease has
+// passed, so a delivery is never attempted twice at the same time.
+func (c *Context) ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) []*models.WebhookDelivery {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	var due []*models.WebhookDelivery
+	for _, delivery := range c.webhookDeliveries {
+		if delivery.IsFinished() || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		due = append(due, delivery)
+	}
+
+	// Oldest first, so a backlog drains in event order
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	leaseEnd := now.Add(lease)
+	claimed := make([]*models.WebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		delivery.NextAttemptAt = &leaseEnd
+		claimed = append(claimed, cloneDelivery(delivery))
+	}
+	return claimed
+}
+
+// RecordWebhookDeliveryAttempt appends an attempt to a delivery and moves it
+// to status. next is the time of the following attempt while retrying.
+func (c *Context) RecordWebhookDeliveryAttempt(deliveryID string, attempt models.DeliveryAttempt, status string, next *time.Time) error {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	delivery, exists := c.webhookDeliveries[deliveryID]
+	if !exists {
+		return models.ErrWebhookDeliveryNotFound
+	}
+
+	attempt.Attempt = len(delivery.Attempts) + 1
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.Status = status
+	delivery.NextAttemptAt = next
+	delivery.UpdatedAt = time.Now()
+	return nil
+}
+
+// queueWebhookDelivery stores a delivery as pending and due now, trimming
+// the finished deliveries that exceed the history of its webhook.
+// Must be called with webhooksMutex held.
+func (c *Context) queueWebhookDelivery(delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.ID = uuid.New().String()
+	delivery.Status = models.DeliveryStatusPending
+	delivery.Attempts = []models.DeliveryAttempt{}
+	delivery.NextAttemptAt = &now
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	c.webhookDeliveries[delivery.ID] = delivery
+	ids := append(c.deliveriesByWebhook[delivery.WebhookID], delivery.ID)
+
+	// Deliveries still being attempted are kept beyond the history size,
+	// their number is bounded by the retry schedule
+	excess := len(ids) - c.webhookHistory
+	if excess > 0 {
+		kept := ids[:0]
+		for _, id := range ids {
+			if excess > 0 && c.webhookDeliveries[id].IsFinished() {
+				delete(c.webhookDeliveries, id)
+				excess--
+				continue
+			}
+			kept = append(kept, id)
+		}
+		ids = kept
+	}
+	c.deliveriesByWebhook[delivery.WebhookID] = ids
+}
+
+// cloneDelivery copies a delivery including its attempts
+func cloneDelivery(delivery *models.WebhookDelivery) *models.WebhookDelivery {
+	clone := *delivery
+	clone.Attempts = append([]models.DeliveryAttempt(nil), delivery.Attempts...)
+	if delivery.NextAttemptAt != nil {
+		next := *delivery.NextAttemptAt
+		clone.NextAttemptAt = &next
+	}
+	return &clone
+}
+
+// Webhook Persistence Functions
+
+// SetWebhookStateFile sets the file used to persist webhooks. An empty path
+// disables persistence.
+func (c *Context) SetWebhookStateFile(path string) {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+	c.webhookStateFile = path
+}
+
+// LoadWebhookState restores the webhooks saved by a previous run
+func (c *Context) LoadWebhookState() error {
+	c.webhooksMutex.Lock()
+	defer c.webhooksMutex.Unlock()
+
+	if c.webhookStateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.webhookStateFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var webhooks []*models.Webhook
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		c.webhooks[webhook.ID] = webhook
+	}
+
+	logger.ContextLog.Infof("Loaded %d webhooks from %s", len(webhooks), c.webhookStateFile)
+	return nil
+}
+
+// saveWebhookState writes all webhooks, including their secrets, to the
+// state file. Must be called with webhooksMutex held.
+func (c *Context) saveWebhookState() {
+	if c.webhookStateFile == "" {
+		return
+	}
+
+	webhooks := make([]*models.Webhook, 0, len(c.webhooks))
+	for _, webhook := range c.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+
+	data, err := json.Marshal(webhooks)
+	if err != nil {
+		logger.ContextLog.Errorf("Failed to encode webhook state: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.webhookStateFile), 0o755); err != nil {
+		logger.ContextLog.Errorf("Failed to create webhook state directory: %v", err)
+		return
+	}
+
+	// The file holds signing secrets, keep it private
+	tmp := c.webhookStateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		logger.ContextLog.Errorf("Failed to write webhook state: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.webhookStateFile); err != nil {
+		logger.ContextLog.Errorf("Failed to replace webhook state: %v", err)
+	}
+}
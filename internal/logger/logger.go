@@ -25,6 +25,9 @@ var (
 	SBILog      *logrus.Entry
 	WebLog      *logrus.Entry
 	GenieACSLog *logrus.Entry
+	FaultLog    *logrus.Entry
+	NotifyLog   *logrus.Entry
+	AuditLog    *logrus.Entry
 )
 
 func init() {
@@ -42,6 +45,9 @@ func init() {
 	SBILog = log.WithFields(logrus.Fields{"component": "SBI"})
 	WebLog = log.WithFields(logrus.Fields{"component": "WEB"})
 	GenieACSLog = log.WithFields(logrus.Fields{"component": "GENIEACS"})
+	FaultLog = log.WithFields(logrus.Fields{"component": "FAULT"})
+	NotifyLog = log.WithFields(logrus.Fields{"component": "NOTIFY"})
+	AuditLog = log.WithFields(logrus.Fields{"component": "AUDIT"})
 }
 
 type Config struct {
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Outcomes of an audited request
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeDenied  = "denied"
+	AuditOutcomeFailure = "failure"
+)
+
+// Interfaces an audited request came in on
+const (
+	AuditSourceNBI = "nbi"
+	AuditSourceUI  = "ui"
+)
+
+// AuditRecord is an entry of the audit log. Records are chained: Hash
+// covers the record and the hash of its predecessor, so changing or
+// removing a record breaks the chain from there on.
+type AuditRecord struct {
+	Seq       uint64    `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Actor      string `json:"actor"`
+	AuthMethod string `json:"authMethod,omitempty"`
+	ClientIP   string `json:"clientIp"`
+	RequestID  string `json:"requestId,omitempty"`
+	Source     string `json:"source"`
+
+	// Action is the method and route, such as
+	// "POST /api/v1/devices/:deviceId/reboot"
+	Action  string        `json:"action"`
+	Targets []string      `json:"targets,omitempty"`
+	Changes []AuditChange `json:"changes,omitempty"`
+
+	Status  int    `json:"status"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditChange is a value changed by an audited request. Before is empty
+// when the previous value is not known.
+type AuditChange struct {
+	Target string          `json:"target"`
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditFilter selects audit records. Empty fields match all records.
+type AuditFilter struct {
+	Actor   string
+	Action  string
+	Target  string
+	Outcome string
+	Source  string
+	From    time.Time
+	To      time.Time
+}
+
+// Matches reports whether a record passes the filter. Actions match by
+// substring, so "reboot" finds single and bulk reboots.
+func (f AuditFilter) Matches(record *AuditRecord) bool {
+	if f.Actor != "" && record.Actor != f.Actor {
+		return false
+	}
+	if f.Action != "" && !strings.Contains(record.Action, f.Action) {
+		return false
+	}
+	if f.Target != "" && !slices.Contains(record.Targets, f.Target) {
+		return false
+	}
+	if f.Outcome != "" && record.Outcome != f.Outcome {
+		return false
+	}
+	if f.Source != "" && record.Source != f.Source {
+		return false
+	}
+	if !f.From.IsZero() && record.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !record.Timestamp.Before(f.To) {
+		return false
+	}
+	return true
+}
+
+// AuditVerification is the result of checking the hash chain of the audit
+// log
+type AuditVerification struct {
+	Valid   bool   `json:"valid"`
+	Records uint64 `json:"records"`
+
+	// BrokenAt is the sequence number of the first record that does not
+	// match its hash or predecessor
+	BrokenAt uint64 `json:"brokenAt,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Built-in roles. Their permissions can be changed and further roles added
+// in the rbac section of the configuration.
+const (
+	RoleViewer        = "viewer"
+	RoleOperator      = "operator"
+	RoleFirmwareAdmin = "firmware-admin"
+	RoleAdmin         = "admin"
+)
+
+// Permissions guard route groups of the NBI and UI. Destructive device
+// actions have their own permissions, managing devices does not imply them.
+const (
+	PermView               = "view"
+	PermDeviceManage       = "device.manage"
+	PermDeviceReboot       = "device.reboot"
+	PermDeviceFactoryReset = "device.factory-reset"
+	PermBulkParameters     = "bulk.parameters"
+	PermFirmwareManage     = "firmware.manage"
+	PermTaskManage         = "task.manage"
+	PermFaultAcknowledge   = "fault.acknowledge"
+	PermFaultResolve       = "fault.resolve"
+	PermFaultDelete        = "fault.delete"
+	PermMaintenanceManage  = "maintenance.manage"
+	PermConfigManage       = "config.manage"
+	PermAPIKeyManage       = "apikey.manage"
+	PermUserManage         = "user.manage"
+	PermAuditView          = "audit.view"
+)
+
+// AllPermissions lists every permission
+var AllPermissions = []string{
+	PermView,
+	PermDeviceManage,
+	PermDeviceReboot,
+	PermDeviceFactoryReset,
+	PermBulkParameters,
+	PermFirmwareManage,
+	PermTaskManage,
+	PermFaultAcknowledge,
+	PermFaultResolve,
+	PermFaultDelete,
+	PermMaintenanceManage,
+	PermConfigManage,
+	PermAPIKeyManage,
+	PermUserManage,
+	PermAuditView,
+}
+
+// DefaultRolePermissions are the permissions of the built-in roles
+var DefaultRolePermissions = map[string][]string{
+	RoleViewer: {PermView},
+	RoleOperator: {
+		PermView, PermDeviceManage, PermDeviceReboot, PermTaskManage,
+		PermFaultAcknowledge, PermFaultResolve, PermMaintenanceManage,
+	},
+	RoleFirmwareAdmin: {
+		PermView, PermDeviceManage, PermDeviceReboot, PermTaskManage,
+		PermFirmwareManage, PermMaintenanceManage,
+	},
+	RoleAdmin: AllPermissions,
+}
+
+// IsValidPermission checks if a permission is known
+func IsValidPermission(permission string) bool {
+	return slices.Contains(AllPermissions, permission)
+}
+
+// Authentication methods of a principal. Anonymous principals stand for
+// callers of an interface without authentication. OIDC marks single sign-on
+// logins in the audit log, their sessions are session principals.
+const (
+	AuthMethodAPIKey    = "apikey"
+	AuthMethodJWT       = "jwt"
+	AuthMethodSession   = "session"
+	AuthMethodAnonymous = "anonymous"
+	AuthMethodOIDC      = "oidc"
+)
+
+// APIKeyPrefix starts every generated API key, telling keys and JWTs apart
+const APIKeyPrefix = "ntg_"
+
+// APIKey is a key for NBI clients. Only the SHA-256 hash of the key is kept,
+// the key itself is returned once when it is created.
+type APIKey struct {
+	ID   string `json:"id" bson:"_id"`
+	Name string `json:"name" bson:"name"`
+	Role string `json:"role" bson:"role"`
+
+	// Tenant scopes the key to the devices of a tenant, empty sees across
+	// tenants
+	Tenant string `json:"tenant,omitempty" bson:"tenant,omitempty"`
+
+	// Prefix is the start of the key, enough to recognise it. Hash is the
+	// hex SHA-256 of the key, it is only written to the state file.
+	Prefix string `json:"prefix" bson:"prefix"`
+	Hash   string `json:"hash,omitempty" bson:"hash"`
+
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+
+	CreatedBy string    `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Validate checks the key name and expiry. The role is checked by the
+// context, which knows the configured roles.
+func (k *APIKey) Validate() error {
+	if k.Name == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "name", Message: "name is required"}}}
+	}
+	if k.Role == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "role", Message: "role is required"}}}
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(time.Now()) {
+		return ValidationErrors{Errors: []ValidationError{{Field: "expiresAt", Message: "expiresAt must be in the future"}}}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the key without its hash
+func (k *APIKey) Redacted() *APIKey {
+	redacted := *k
+	redacted.Hash = ""
+	return &redacted
+}
+
+// IsExpired reports whether the key can no longer be used
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// Principal is the authenticated caller of an NBI or UI request
+type Principal struct {
+	// Name is the API key name or the name claim of the token, and is
+	// recorded as the actor of changes
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	KeyID  string `json:"keyId,omitempty"`
+
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+
+	// Tenant limits the caller to the devices of a tenant. Callers without
+	// a tenant, the super-admins, see across tenants.
+	Tenant string `json:"tenant,omitempty"`
+
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Can reports whether one of the roles of the principal grants the
+// permission
+func (p *Principal) Can(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// IsAnonymous reports whether the caller did not authenticate
+func (p *Principal) IsAnonymous() bool {
+	return p.Method == AuthMethodAnonymous
+}
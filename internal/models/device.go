@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -19,6 +20,87 @@ type Device struct {
 
 	ConnectionRequest ConnectionRequest `json:"connectionRequest" bson:"connectionRequest"`
 	Status            DeviceStatus      `json:"status" bson:"status"`
+
+	// Version is assigned by the device store and increases on every change
+	Version uint64 `json:"version" bson:"-"`
+}
+
+// Clone returns a deep copy of the device that can be modified without
+// affecting the original
+func (d *Device) Clone() *Device {
+	clone := *d
+
+	if d.Tags != nil {
+		clone.Tags = make(map[string]bool, len(d.Tags))
+		for tag, set := range d.Tags {
+			clone.Tags[tag] = set
+		}
+	}
+
+	if d.DeviceInfo != nil {
+		clone.DeviceInfo = cloneValue(d.DeviceInfo).(map[string]interface{})
+	}
+
+	if d.Parameters != nil {
+		clone.Parameters = make(map[string]Parameter, len(d.Parameters))
+		for path, param := range d.Parameters {
+			param.Value = cloneValue(param.Value)
+			if param.Attributes != nil {
+				param.Attributes = cloneValue(param.Attributes).(map[string]interface{})
+			}
+			clone.Parameters[path] = param
+		}
+	}
+
+	return &clone
+}
+
+// cloneValue deep copies the maps and slices of a decoded document
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneValue(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return value
+	}
+}
+
+// ParameterValue returns the value of a parameter path, looking at the
+// extracted parameters first and then walking the nested GenieACS document
+func (d *Device) ParameterValue(path string) (interface{}, bool) {
+	if param, ok := d.Parameters[path]; ok && param.Value != nil {
+		return param.Value, true
+	}
+	if d.DeviceInfo == nil {
+		return nil, false
+	}
+
+	var current interface{} = d.DeviceInfo
+	for _, part := range strings.Split(path, ".") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = node[part]; !ok {
+			return nil, false
+		}
+	}
+
+	if node, ok := current.(map[string]interface{}); ok {
+		value, ok := node["_value"]
+		return value, ok && value != nil
+	}
+	return current, current != nil
 }
 
 // DeviceID contains identifying information for the device
@@ -50,8 +132,19 @@ type DeviceStatus struct {
 	LastSeen         time.Time `json:"lastSeen" bson:"lastSeen"`
 	ConnectionStatus string    `json:"connectionStatus" bson:"connectionStatus"`
 	ErrorCount       int       `json:"errorCount" bson:"errorCount"`
+
+	// Heartbeat derived from the CPE's periodic inform interval
+	InformInterval int `json:"informInterval,omitempty" bson:"informInterval,omitempty"`
+	MissedInforms  int `json:"missedInforms,omitempty" bson:"missedInforms,omitempty"`
 }
 
+// ConnectionStatus constants
+const (
+	ConnectionStatusOnline  = "online"
+	ConnectionStatusLate    = "late"
+	ConnectionStatusOffline = "offline"
+)
+
 // Parameter represents a device parameter
 type Parameter struct {
 	Path       string                 `json:"path" bson:"path"`
@@ -83,6 +176,34 @@ type Fault struct {
 	ResolvedBy     string     `json:"resolvedBy,omitempty" bson:"resolvedBy,omitempty"`
 	ResolvedAt     *time.Time `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
 	Tags           []string   `json:"tags,omitempty" bson:"tags,omitempty"`
+	IncidentID     string     `json:"incidentId,omitempty" bson:"incidentId,omitempty"`
+
+	// Suppression by a maintenance window
+	Suppressed          bool   `json:"suppressed,omitempty" bson:"suppressed,omitempty"`
+	MaintenanceWindowID string `json:"maintenanceWindowId,omitempty" bson:"maintenanceWindowId,omitempty"`
+
+	// Ownership and operator notes
+	Assignee           string      `json:"assignee,omitempty" bson:"assignee,omitempty"`
+	AssignedBy         string      `json:"assignedBy,omitempty" bson:"assignedBy,omitempty"`
+	AssignedAt         *time.Time  `json:"assignedAt,omitempty" bson:"assignedAt,omitempty"`
+	Notes              []FaultNote `json:"notes,omitempty" bson:"notes,omitempty"`
+	ResolutionCategory string      `json:"resolutionCategory,omitempty" bson:"resolutionCategory,omitempty"`
+	Resolution         string      `json:"resolution,omitempty" bson:"resolution,omitempty"`
+
+	// Escalation state for unacknowledged faults
+	EscalationLevel    int        `json:"escalationLevel,omitempty" bson:"escalationLevel,omitempty"`
+	EscalationPolicyID string     `json:"escalationPolicyId,omitempty" bson:"escalationPolicyId,omitempty"`
+	EscalatedAt        *time.Time `json:"escalatedAt,omitempty" bson:"escalatedAt,omitempty"`
+}
+
+// FaultNote is a comment left on a fault by an operator. Notes form a thread
+// in the order they were added; ReplyTo points at the note being answered.
+type FaultNote struct {
+	ID        string    `json:"id" bson:"id"`
+	Author    string    `json:"author" bson:"author"`
+	Text      string    `json:"text" bson:"text"`
+	ReplyTo   string    `json:"replyTo,omitempty" bson:"replyTo,omitempty"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
 }
 
 // FaultSeverity constants
@@ -94,6 +215,16 @@ const (
 	SeverityInfo     = "info"
 )
 
+// IsValidSeverity checks if a fault severity is known
+func IsValidSeverity(severity string) bool {
+	switch severity {
+	case SeverityCritical, SeverityMajor, SeverityMinor, SeverityWarning, SeverityInfo:
+		return true
+	default:
+		return false
+	}
+}
+
 // FaultStatus constants
 const (
 	FaultStatusActive       = "active"
@@ -102,13 +233,48 @@ const (
 	FaultStatusExpired      = "expired"
 )
 
+// FaultChannelHeartbeat is the channel of faults raised when a device stops
+// informing
+const (
+	FaultChannelHeartbeat  = "gateway-heartbeat"
+	FaultCodeDeviceOffline = "GW-OFFLINE"
+)
+
+// Fault resolution category constants
+const (
+	ResolutionFixed         = "fixed"
+	ResolutionFalsePositive = "false_positive"
+	ResolutionDuplicate     = "duplicate"
+	ResolutionExpected      = "expected"
+	ResolutionWontFix       = "wont_fix"
+	ResolutionOther         = "other"
+
+	// ResolutionAutoCleared is set when the gateway clears a fault it raised
+	ResolutionAutoCleared = "auto_cleared"
+)
+
+// IsValidResolutionCategory checks if a resolution category is known. An
+// empty category is allowed.
+func IsValidResolutionCategory(category string) bool {
+	switch category {
+	case "", ResolutionFixed, ResolutionFalsePositive, ResolutionDuplicate,
+		ResolutionExpected, ResolutionWontFix, ResolutionOther, ResolutionAutoCleared:
+		return true
+	default:
+		return false
+	}
+}
+
 // FaultFilter represents filtering options for faults
 type FaultFilter struct {
-	DeviceID  string `json:"deviceId,omitempty"`
-	Severity  string `json:"severity,omitempty"`
-	Status    string `json:"status,omitempty"`
-	Channel   string `json:"channel,omitempty"`
-	TimeRange string `json:"timeRange,omitempty"`
+	DeviceID   string `json:"deviceId,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+	TimeRange  string `json:"timeRange,omitempty"`
+	IncidentID string `json:"incidentId,omitempty"`
+	Suppressed *bool  `json:"suppressed,omitempty"`
+	Assignee   string `json:"assignee,omitempty"`
 }
 
 // DeviceFilter represents filtering options for devices
@@ -120,16 +286,11 @@ type DeviceFilter struct {
 	ProductClass string             `json:"productClass,omitempty"`
 	Tags         []string           `json:"tags,omitempty"`
 	Online       *bool              `json:"online,omitempty"`
-	Search       string             `json:"search,omitempty"`
+	Tenant       string             `json:"tenant,omitempty"`
+	Search       string             `json:"search,omitempty"` // Ranked full-text query
 	Pagination   *PaginationOptions `json:"pagination,omitempty"`
 }
 
-// IPRange represents an IP address range for filtering
-type IPRange struct {
-	StartIP string `json:"startIp"`
-	EndIP   string `json:"endIp"`
-}
-
 // PaginationOptions represents pagination parameters
 type PaginationOptions struct {
 	Page     int    `json:"page"`
@@ -144,10 +305,11 @@ type DeviceStats struct {
 	OnlineDevices  int `json:"onlineDevices"`
 	OfflineDevices int `json:"offlineDevices"`
 
-	DevicesByVendor map[string]int `json:"devicesByVendor"`
-	DevicesByModel  map[string]int `json:"devicesByModel"`
-	ActiveFaults    int            `json:"activeFaults"`
-	CriticalFaults  int            `json:"criticalFaults"`
+	DevicesByVendor  map[string]int `json:"devicesByVendor"`
+	DevicesByModel   map[string]int `json:"devicesByModel"`
+	ActiveFaults     int            `json:"activeFaults"`
+	CriticalFaults   int            `json:"criticalFaults"`
+	SuppressedFaults int            `json:"suppressedFaults"`
 }
 
 // Task represents a task to be executed on a device
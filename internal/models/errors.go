@@ -10,6 +10,7 @@ var (
 	ErrInvalidDeviceID     = errors.New("invalid device ID")
 	ErrDeviceOffline       = errors.New("device is offline")
 	ErrDeviceNotResponding = errors.New("device is not responding")
+	ErrDeviceConflict      = errors.New("device was modified concurrently")
 
 	// Fault errors
 	ErrFaultNotFound            = errors.New("fault not found")
@@ -17,6 +18,40 @@ var (
 	ErrInvalidFaultID           = errors.New("invalid fault ID")
 	ErrFaultAlreadyAcknowledged = errors.New("fault already acknowledged")
 	ErrFaultAlreadyResolved     = errors.New("fault already resolved")
+	ErrFaultNoteNotFound        = errors.New("fault note not found")
+	ErrInvalidResolution        = errors.New("invalid resolution category")
+
+	// Incident errors
+	ErrIncidentNotFound = errors.New("incident not found")
+
+	// Maintenance errors
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+
+	// Escalation errors
+	ErrEscalationPolicyNotFound = errors.New("escalation policy not found")
+
+	// Alarm rule errors
+	ErrAlarmRuleNotFound = errors.New("alarm rule not found")
+
+	// Webhook errors
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	// API key errors
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrAPIKeyExpired  = errors.New("API key expired")
+
+	// User errors
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserExists         = errors.New("user already exists")
+	ErrUserLocked         = errors.New("user locked after failed logins")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrExternalUser       = errors.New("password of single sign-on users is managed by the identity provider")
+
+	// Event errors
+	ErrSlowConsumer  = errors.New("event subscriber fell behind")
+	ErrEventsExpired = errors.New("events after the requested sequence are no longer retained")
 
 	// Task errors
 	ErrTaskNotFound      = errors.New("task not found")
@@ -95,6 +130,14 @@ func (ve ValidationErrors) Error() string {
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrDeviceNotFound) ||
 		errors.Is(err, ErrFaultNotFound) ||
+		errors.Is(err, ErrIncidentNotFound) ||
+		errors.Is(err, ErrMaintenanceWindowNotFound) ||
+		errors.Is(err, ErrEscalationPolicyNotFound) ||
+		errors.Is(err, ErrAlarmRuleNotFound) ||
+		errors.Is(err, ErrWebhookNotFound) ||
+		errors.Is(err, ErrWebhookDeliveryNotFound) ||
+		errors.Is(err, ErrAPIKeyNotFound) ||
+		errors.Is(err, ErrUserNotFound) ||
 		errors.Is(err, ErrTaskNotFound) ||
 		errors.Is(err, ErrParameterNotFound) ||
 		errors.Is(err, ErrRecordNotFound)
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// EscalationPolicy escalates faults that stay unacknowledged. A policy
+// applies to faults whose severity and device tags match its selector.
+type EscalationPolicy struct {
+	ID      string `json:"id" bson:"_id"`
+	Name    string `json:"name" bson:"name"`
+	Enabled bool   `json:"enabled" bson:"enabled"`
+
+	// Selector. Empty lists match every severity or device.
+	Severities []string `json:"severities,omitempty" bson:"severities,omitempty"`
+	Tags       []string `json:"tags,omitempty" bson:"tags,omitempty"`
+
+	Levels []EscalationLevel `json:"levels" bson:"levels"`
+
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// EscalationLevel is a single step of an escalation policy
+type EscalationLevel struct {
+	Level        int      `json:"level" bson:"level"`
+	AfterMinutes int      `json:"afterMinutes" bson:"afterMinutes"`
+	Notify       []string `json:"notify,omitempty" bson:"notify,omitempty"`
+}
+
+// EscalationEvent records a fault moving to a higher escalation level
+type EscalationEvent struct {
+	FaultID    string    `json:"faultId"`
+	DeviceID   string    `json:"deviceId"`
+	Severity   string    `json:"severity"`
+	PolicyID   string    `json:"policyId"`
+	PolicyName string    `json:"policyName"`
+	Level      int       `json:"level"`
+	Notify     []string  `json:"notify,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Validate checks the policy and orders its levels
+func (p *EscalationPolicy) Validate() error {
+	if p.Name == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "name", Message: "name is required"}}}
+	}
+	if len(p.Levels) == 0 {
+		return ValidationErrors{Errors: []ValidationError{{Field: "levels", Message: "at least one level is required"}}}
+	}
+
+	sort.Slice(p.Levels, func(i, j int) bool {
+		return p.Levels[i].Level < p.Levels[j].Level
+	})
+
+	for i, level := range p.Levels {
+		if level.Level < 1 {
+			return ValidationErrors{Errors: []ValidationError{{Field: "levels", Message: "level numbers must start at 1"}}}
+		}
+		if level.AfterMinutes < 0 {
+			return ValidationErrors{Errors: []ValidationError{{Field: "levels", Message: "afterMinutes must not be negative"}}}
+		}
+		if i > 0 {
+			prev := p.Levels[i-1]
+			if level.Level == prev.Level {
+				return ValidationErrors{Errors: []ValidationError{{Field: "levels", Message: "level numbers must be unique"}}}
+			}
+			if level.AfterMinutes < prev.AfterMinutes {
+				return ValidationErrors{Errors: []ValidationError{{Field: "levels", Message: "higher levels must not fire before lower ones"}}}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the policy applies to a fault raised by a device
+// with the given tags
+func (p *EscalationPolicy) Matches(fault *Fault, tags map[string]bool) bool {
+	if !p.Enabled {
+		return false
+	}
+
+	if len(p.Severities) > 0 {
+		found := false
+		for _, severity := range p.Severities {
+			if severity == fault.Severity {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(p.Tags) > 0 {
+		found := false
+		for _, tag := range p.Tags {
+			if tags[tag] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// LevelFor returns the highest level reached after the fault has been
+// unacknowledged for the given duration, or nil if none is due yet
+func (p *EscalationPolicy) LevelFor(elapsed time.Duration) *EscalationLevel {
+	var reached *EscalationLevel
+	for i := range p.Levels {
+		if elapsed >= time.Duration(p.Levels[i].AfterMinutes)*time.Minute {
+			reached = &p.Levels[i]
+		}
+	}
+	return reached
+}
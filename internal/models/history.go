@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAvailabilityRanges are reported when no ranges are requested
+var DefaultAvailabilityRanges = []string{"24h", "7d", "30d"}
+
+// DeviceEvent type constants
+const (
+	DeviceEventOnline    = "online"
+	DeviceEventLate      = "late"
+	DeviceEventOffline   = "offline"
+	DeviceEventBoot      = "boot"
+	DeviceEventBootstrap = "bootstrap"
+	DeviceEventFirmware  = "firmware"
+)
+
+// DeviceEvent records a connectivity transition, boot, bootstrap or
+// firmware change of a device
+type DeviceEvent struct {
+	DeviceID  string    `json:"deviceId" bson:"deviceId"`
+	Type      string    `json:"type" bson:"type"`
+	From      string    `json:"from,omitempty" bson:"from,omitempty"`
+	To        string    `json:"to,omitempty" bson:"to,omitempty"`
+	Message   string    `json:"message" bson:"message"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+}
+
+// IsConnectivity reports whether the event changes the connection state
+func (e *DeviceEvent) IsConnectivity() bool {
+	switch e.Type {
+	case DeviceEventOnline, DeviceEventLate, DeviceEventOffline:
+		return true
+	default:
+		return false
+	}
+}
+
+// Availability is the share of a time range a device was reachable. Late
+// devices count as available. Time before the first recorded state is not
+// counted.
+type Availability struct {
+	Range          string    `json:"range"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OnlineSeconds  int64     `json:"onlineSeconds"`
+	OfflineSeconds int64     `json:"offlineSeconds"`
+	Percentage     float64   `json:"percentage"`
+	Transitions    int       `json:"transitions"`
+}
+
+// CalculateAvailability computes availability over [from, to] from a
+// device's events in chronological order
+func CalculateAvailability(events []*DeviceEvent, from, to time.Time) Availability {
+	availability := Availability{From: from, To: to}
+
+	state := ""
+	since := from
+	account := func(until time.Time) {
+		if state == "" || !until.After(since) {
+			return
+		}
+		seconds := int64(until.Sub(since) / time.Second)
+		if state == DeviceEventOffline {
+			availability.OfflineSeconds += seconds
+		} else {
+			availability.OnlineSeconds += seconds
+		}
+	}
+
+	for _, event := range events {
+		if !event.IsConnectivity() {
+			continue
+		}
+		if event.Timestamp.After(to) {
+			break
+		}
+		if event.Timestamp.After(from) {
+			account(event.Timestamp)
+			since = event.Timestamp
+			availability.Transitions++
+		}
+		state = event.Type
+	}
+	account(to)
+
+	if total := availability.OnlineSeconds + availability.OfflineSeconds; total > 0 {
+		availability.Percentage = float64(availability.OnlineSeconds) * 100 / float64(total)
+	}
+	return availability
+}
+
+// ParseHistoryRange parses a range such as "90m", "24h" or "7d"
+func ParseHistoryRange(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid range: %s", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid range: %s", value)
+	}
+	return d, nil
+}
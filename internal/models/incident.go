@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// Incident groups correlated faults that share a code, channel and device
+// model inside the correlation window into a single parent alarm
+type Incident struct {
+	ID          string `json:"id" bson:"_id"`
+	Key         string `json:"key" bson:"key"`
+	Code        string `json:"code" bson:"code"`
+	Channel     string `json:"channel" bson:"channel"`
+	DeviceModel string `json:"deviceModel" bson:"deviceModel"`
+	Message     string `json:"message" bson:"message"`
+	Severity    string `json:"severity" bson:"severity"`
+	Status      string `json:"status" bson:"status"`
+
+	FirstSeen   time.Time `json:"firstSeen" bson:"firstSeen"`
+	LastSeen    time.Time `json:"lastSeen" bson:"lastSeen"`
+	FaultIDs    []string  `json:"faultIds" bson:"faultIds"`
+	DeviceIDs   []string  `json:"deviceIds" bson:"deviceIds"`
+	FaultCount  int       `json:"faultCount" bson:"faultCount"`
+	DeviceCount int       `json:"deviceCount" bson:"deviceCount"`
+
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty" bson:"acknowledgedBy,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty" bson:"acknowledgedAt,omitempty"`
+	ResolvedBy     string     `json:"resolvedBy,omitempty" bson:"resolvedBy,omitempty"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+}
+
+// IncidentFilter represents filtering options for incidents
+type IncidentFilter struct {
+	Status      string `json:"status,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	DeviceModel string `json:"deviceModel,omitempty"`
+	MinFaults   int    `json:"minFaults,omitempty"`
+}
+
+// SeverityRank returns a numeric rank for a severity, higher is more severe
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 5
+	case SeverityMajor:
+		return 4
+	case SeverityMinor:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
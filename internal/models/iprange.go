@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPRange field constants select which device address a range applies to
+const (
+	IPFieldAny      = ""
+	IPFieldLAN      = "ip"
+	IPFieldExternal = "external"
+)
+
+// IPRange represents an IP address filter. An address matches when it lies
+// between StartIP and EndIP or inside any of the CIDR prefixes. IPv4 and
+// IPv6 are supported; a range only matches addresses of its own family.
+type IPRange struct {
+	StartIP string   `json:"startIp,omitempty"`
+	EndIP   string   `json:"endIp,omitempty"`
+	CIDRs   []string `json:"cidrs,omitempty"`
+
+	// Field selects IPAddress, ExternalIPAddress or either of them
+	Field string `json:"field,omitempty"`
+
+	// Negate keeps the devices that do not match
+	Negate bool `json:"negate,omitempty"`
+}
+
+// Validate checks the addresses and prefixes of the filter
+func (r *IPRange) Validate() error {
+	if r.StartIP == "" && r.EndIP == "" && len(r.CIDRs) == 0 {
+		return ValidationErrors{Errors: []ValidationError{{Field: "startIp", Message: "an address range or CIDR prefix is required"}}}
+	}
+
+	if r.StartIP != "" || r.EndIP != "" {
+		if _, _, err := r.bounds(); err != nil {
+			return ValidationErrors{Errors: []ValidationError{{Field: "startIp", Message: err.Error()}}}
+		}
+	}
+
+	for _, cidr := range r.CIDRs {
+		if _, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err != nil {
+			return ValidationErrors{Errors: []ValidationError{{Field: "cidrs", Message: fmt.Sprintf("invalid CIDR prefix %q", cidr)}}}
+		}
+	}
+
+	switch r.Field {
+	case IPFieldAny, IPFieldLAN, IPFieldExternal:
+	default:
+		return ValidationErrors{Errors: []ValidationError{{Field: "field", Message: "field must be ip, external or empty"}}}
+	}
+	return nil
+}
+
+// Contains reports whether an address is selected by the range, ignoring
+// Negate. Invalid or empty addresses never match.
+func (r *IPRange) Contains(ip string) bool {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	if r.StartIP != "" || r.EndIP != "" {
+		if start, end, err := r.bounds(); err == nil && addr.Is4() == start.Is4() {
+			if addr.Compare(start) >= 0 && addr.Compare(end) <= 0 {
+				return true
+			}
+		}
+	}
+
+	for _, cidr := range r.CIDRs {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr)); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesDevice applies the filter, including Negate, to a device
+func (r *IPRange) MatchesDevice(device *Device) bool {
+	var matched bool
+	switch r.Field {
+	case IPFieldLAN:
+		matched = r.Contains(device.DeviceID.IPAddress)
+	case IPFieldExternal:
+		matched = r.Contains(device.DeviceID.ExternalIPAddress)
+	default:
+		matched = r.Contains(device.DeviceID.IPAddress) || r.Contains(device.DeviceID.ExternalIPAddress)
+	}
+	return matched != r.Negate
+}
+
+// bounds parses the start and end of the range. A missing end makes a
+// single address range.
+func (r *IPRange) bounds() (netip.Addr, netip.Addr, error) {
+	startText, endText := strings.TrimSpace(r.StartIP), strings.TrimSpace(r.EndIP)
+	if startText == "" {
+		startText = endText
+	}
+	if endText == "" {
+		endText = startText
+	}
+
+	start, err := netip.ParseAddr(startText)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid start address %q", startText)
+	}
+	end, err := netip.ParseAddr(endText)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid end address %q", endText)
+	}
+
+	start, end = start.Unmap(), end.Unmap()
+	if start.Is4() != end.Is4() {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("start and end addresses must be the same IP version")
+	}
+	if start.Compare(end) > 0 {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("start address is after end address")
+	}
+	return start, end, nil
+}
+
+// ParseIPRange builds a filter from request parameters. cidrs is a comma
+// separated prefix list. It returns nil when no range is given.
+func ParseIPRange(startIP, endIP, cidrs, field string, negate bool) (*IPRange, error) {
+	r := &IPRange{
+		StartIP: strings.TrimSpace(startIP),
+		EndIP:   strings.TrimSpace(endIP),
+		Field:   field,
+		Negate:  negate,
+	}
+	for _, cidr := range strings.Split(cidrs, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			r.CIDRs = append(r.CIDRs, cidr)
+		}
+	}
+
+	if r.StartIP == "" && r.EndIP == "" && len(r.CIDRs) == 0 {
+		return nil, nil
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+)
+
+// MaintenanceWindow describes a planned period during which faults raised by
+// the selected devices are expected and therefore suppressed
+type MaintenanceWindow struct {
+	ID         string    `json:"id" bson:"_id"`
+	Name       string    `json:"name" bson:"name"`
+	Reason     string    `json:"reason" bson:"reason"`
+	StartTime  time.Time `json:"startTime" bson:"startTime"`
+	EndTime    time.Time `json:"endTime" bson:"endTime"`
+	Recurrence string    `json:"recurrence,omitempty" bson:"recurrence,omitempty"`
+
+	// Device selector. A device matches when it is listed by ID, carries one
+	// of the tags or is one of the models. An empty selector matches every
+	// device.
+	DeviceIDs []string `json:"deviceIds,omitempty" bson:"deviceIds,omitempty"`
+	Tags      []string `json:"tags,omitempty" bson:"tags,omitempty"`
+	Models    []string `json:"models,omitempty" bson:"models,omitempty"`
+
+	CreatedBy string    `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Maintenance window recurrence constants
+const (
+	RecurrenceNone   = ""
+	RecurrenceDaily  = "daily"
+	RecurrenceWeekly = "weekly"
+)
+
+// Validate checks that the window has a usable schedule
+func (w *MaintenanceWindow) Validate() error {
+	if w.Name == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "name", Message: "name is required"}}}
+	}
+	if w.StartTime.IsZero() || w.EndTime.IsZero() {
+		return ValidationErrors{Errors: []ValidationError{{Field: "startTime", Message: "startTime and endTime are required"}}}
+	}
+	if !w.EndTime.After(w.StartTime) {
+		return ValidationErrors{Errors: []ValidationError{{Field: "endTime", Message: "endTime must be after startTime"}}}
+	}
+
+	switch w.Recurrence {
+	case RecurrenceNone:
+	case RecurrenceDaily, RecurrenceWeekly:
+		if w.EndTime.Sub(w.StartTime) >= w.period() {
+			return ValidationErrors{Errors: []ValidationError{{Field: "endTime", Message: "window must be shorter than its recurrence period"}}}
+		}
+	default:
+		return ValidationErrors{Errors: []ValidationError{{Field: "recurrence", Message: "recurrence must be daily, weekly or empty"}}}
+	}
+
+	return nil
+}
+
+// IsActiveAt reports whether the window covers the given time
+func (w *MaintenanceWindow) IsActiveAt(t time.Time) bool {
+	if t.Before(w.StartTime) {
+		return false
+	}
+
+	duration := w.EndTime.Sub(w.StartTime)
+	period := w.period()
+	if period == 0 {
+		return t.Before(w.EndTime)
+	}
+	return t.Sub(w.StartTime)%period < duration
+}
+
+// IsExpired reports whether the window will never be active again
+func (w *MaintenanceWindow) IsExpired(t time.Time) bool {
+	return w.Recurrence == RecurrenceNone && !t.Before(w.EndTime)
+}
+
+// MatchesDevice reports whether the selector covers a device. Tags may be nil
+// when the device is not known to the gateway yet.
+func (w *MaintenanceWindow) MatchesDevice(deviceID, model string, tags map[string]bool) bool {
+	if len(w.DeviceIDs) == 0 && len(w.Tags) == 0 && len(w.Models) == 0 {
+		return true
+	}
+
+	for _, id := range w.DeviceIDs {
+		if id == deviceID {
+			return true
+		}
+	}
+	for _, m := range w.Models {
+		if m == model {
+			return true
+		}
+	}
+	for _, tag := range w.Tags {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// period returns the recurrence period, or zero for a one-off window
+func (w *MaintenanceWindow) period() time.Duration {
+	switch w.Recurrence {
+	case RecurrenceDaily:
+		return 24 * time.Hour
+	case RecurrenceWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
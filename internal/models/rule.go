@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// FaultChannelGatewayRule is the channel of faults raised by gateway alarm
+// rules rather than reported by GenieACS
+const FaultChannelGatewayRule = "gateway-rule"
+
+// AlarmRule raises a fault on a device while an expression over its
+// parameters and inform timestamps holds
+type AlarmRule struct {
+	ID          string `json:"id" bson:"_id"`
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	Enabled     bool   `json:"enabled" bson:"enabled"`
+
+	// Fault raised while the rule is active
+	Code     string `json:"code" bson:"code"`
+	Severity string `json:"severity" bson:"severity"`
+
+	// Condition raises the alarm. ClearCondition clears it and defaults to
+	// the negated condition; a separate clear threshold gives hysteresis.
+	Condition      string `json:"condition" bson:"condition"`
+	ClearCondition string `json:"clearCondition,omitempty" bson:"clearCondition,omitempty"`
+
+	// Hold-down timers. The condition must hold for RaiseAfterSeconds before
+	// the fault is raised and the clear condition for ClearAfterSeconds
+	// before it is cleared.
+	RaiseAfterSeconds int `json:"raiseAfterSeconds,omitempty" bson:"raiseAfterSeconds,omitempty"`
+	ClearAfterSeconds int `json:"clearAfterSeconds,omitempty" bson:"clearAfterSeconds,omitempty"`
+
+	// Device selector. Empty lists match every device.
+	Tags   []string `json:"tags,omitempty" bson:"tags,omitempty"`
+	Models []string `json:"models,omitempty" bson:"models,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate checks the fields of a rule that do not need the expression parser
+func (r *AlarmRule) Validate() error {
+	if r.Name == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "name", Message: "name is required"}}}
+	}
+	if r.Code == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "code", Message: "code is required"}}}
+	}
+	if SeverityRank(r.Severity) == 0 {
+		return ValidationErrors{Errors: []ValidationError{{Field: "severity", Message: "severity must be critical, major, minor, warning or info"}}}
+	}
+	if r.Condition == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "condition", Message: "condition is required"}}}
+	}
+	if r.RaiseAfterSeconds < 0 || r.ClearAfterSeconds < 0 {
+		return ValidationErrors{Errors: []ValidationError{{Field: "raiseAfterSeconds", Message: "hold-down timers must not be negative"}}}
+	}
+	return nil
+}
+
+// MatchesDevice reports whether the rule applies to a device
+func (r *AlarmRule) MatchesDevice(device *Device) bool {
+	if len(r.Models) > 0 {
+		found := false
+		for _, model := range r.Models {
+			if model == device.DeviceID.ModelName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.Tags) > 0 {
+		for _, tag := range r.Tags {
+			if device.Tags[tag] {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tenant is a customer network managed by the gateway. Users and API keys of
+// a tenant only see its devices, with their faults, tasks and statistics.
+// Callers without a tenant see across tenants.
+type Tenant struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Selector    TenantSelector `json:"selector"`
+}
+
+// TenantSelector selects the devices of a tenant. A device belongs to the
+// tenant when its OUI, one of its tags or its provisioning code is listed.
+type TenantSelector struct {
+	OUIs              []string `json:"ouis,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	ProvisioningCodes []string `json:"provisioningCodes,omitempty"`
+}
+
+// IsEmpty reports whether the selector selects no device at all
+func (s TenantSelector) IsEmpty() bool {
+	return len(s.OUIs) == 0 && len(s.Tags) == 0 && len(s.ProvisioningCodes) == 0
+}
+
+// Matches reports whether a device belongs to the tenant. OUIs compare
+// without case, tags and provisioning codes exactly.
+func (t *Tenant) Matches(device *Device) bool {
+	if oui := device.DeviceID.OUI; oui != "" {
+		for _, selected := range t.Selector.OUIs {
+			if strings.EqualFold(selected, oui) {
+				return true
+			}
+		}
+	}
+	for _, tag := range t.Selector.Tags {
+		if device.Tags[tag] {
+			return true
+		}
+	}
+	if code := device.DeviceID.ProvisioningCode; code != "" && slices.Contains(t.Selector.ProvisioningCodes, code) {
+		return true
+	}
+	return false
+}
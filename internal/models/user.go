@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// usernamePattern restricts usernames to characters that are safe in logs
+// and audit records
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]{0,63}$`)
+
+// MinPasswordLength is the shortest password accepted for local users
+const MinPasswordLength = 12
+
+// UserProviderOIDC marks users that log in via OpenID Connect
+const UserProviderOIDC = "oidc"
+
+// User is an account of the web UI. Local users have a password, of which
+// only the bcrypt hash is kept. Single sign-on users are created on their
+// first login and get their role from the identity provider on each one.
+type User struct {
+	ID           string `json:"id" bson:"_id"`
+	Username     string `json:"username" bson:"username"`
+	Role         string `json:"role" bson:"role"`
+	Tenant       string `json:"tenant,omitempty" bson:"tenant,omitempty"`
+	PasswordHash string `json:"passwordHash,omitempty" bson:"passwordHash"`
+	Disabled     bool   `json:"disabled" bson:"disabled"`
+
+	// Provider and Subject identify single sign-on users at their
+	// identity provider, both are empty for local users
+	Provider string `json:"provider,omitempty" bson:"provider,omitempty"`
+	Subject  string `json:"subject,omitempty" bson:"subject,omitempty"`
+
+	// FailedLogins counts failed logins since the last successful one,
+	// reaching the configured maximum locks the user until LockedUntil
+	FailedLogins int        `json:"failedLogins" bson:"failedLogins"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty" bson:"lockedUntil,omitempty"`
+	LastLoginAt  *time.Time `json:"lastLoginAt,omitempty" bson:"lastLoginAt,omitempty"`
+
+	CreatedBy string    `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate checks the username and role. The role is checked against the
+// configured roles by the context.
+func (u *User) Validate() error {
+	if !usernamePattern.MatchString(u.Username) {
+		return ValidationErrors{Errors: []ValidationError{{Field: "username", Message: "username must be 1-64 letters, digits or ._@- characters"}}}
+	}
+	if u.Role == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "role", Message: "role is required"}}}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the user without the password hash
+func (u *User) Redacted() *User {
+	redacted := *u
+	redacted.PasswordHash = ""
+	return &redacted
+}
+
+// IsExternal reports whether the user logs in via single sign-on
+func (u *User) IsExternal() bool {
+	return u.Provider != ""
+}
+
+// IsLocked reports whether failed logins currently lock the user
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// ValidatePassword checks a new password for a local user
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return ValidationErrors{Errors: []ValidationError{{Field: "password", Message: fmt.Sprintf("password must be at least %d characters", MinPasswordLength)}}}
+	}
+	if len(password) > 72 {
+		// bcrypt ignores everything after 72 bytes
+		return ValidationErrors{Errors: []ValidationError{{Field: "password", Message: "password must be at most 72 bytes"}}}
+	}
+	return nil
+}
+
+// Session is a logged in web UI user. Sessions end after IdleTimeout
+// without requests and at ExpiresAt, whichever comes first.
+type Session struct {
+	ID        string `json:"-"`
+	UserID    string `json:"userId"`
+	CSRFToken string `json:"-"`
+
+	ClientIP   string    `json:"clientIp"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
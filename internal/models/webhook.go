@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// Webhook is an outbound HTTP subscription to gateway events. Every matching
+// event is POSTed to the URL as JSON, signed with the secret.
+type Webhook struct {
+	ID      string `json:"id" bson:"_id"`
+	Name    string `json:"name" bson:"name"`
+	URL     string `json:"url" bson:"url"`
+	Enabled bool   `json:"enabled" bson:"enabled"`
+
+	// Secret is the HMAC-SHA256 signing key. It is only returned when the
+	// webhook is created or the secret is changed.
+	Secret string `json:"secret,omitempty" bson:"secret"`
+
+	// Event types or category patterns such as "fault.*". Empty matches
+	// every event.
+	Events []string `json:"events,omitempty" bson:"events,omitempty"`
+
+	// Device selector, as for maintenance windows. Events without a device,
+	// such as ACS connectivity changes, pass the selector.
+	DeviceIDs []string `json:"deviceIds,omitempty" bson:"deviceIds,omitempty"`
+	Tags      []string `json:"tags,omitempty" bson:"tags,omitempty"`
+	Models    []string `json:"models,omitempty" bson:"models,omitempty"`
+
+	CreatedBy string    `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Validate checks the webhook target. Event types are checked by the
+// context, which knows the event catalogue.
+func (w *Webhook) Validate() error {
+	if w.Name == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "name", Message: "name is required"}}}
+	}
+
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return ValidationErrors{Errors: []ValidationError{{Field: "url", Message: "url must be an absolute http or https URL"}}}
+	}
+	return nil
+}
+
+// MatchesDevice reports whether the selector covers a device. Tags may be nil
+// when the device is not known to the gateway.
+func (w *Webhook) MatchesDevice(deviceID, model string, tags map[string]bool) bool {
+	selector := MaintenanceWindow{DeviceIDs: w.DeviceIDs, Tags: w.Tags, Models: w.Models}
+	return selector.MatchesDevice(deviceID, model, tags)
+}
+
+// Redacted returns a copy of the webhook without its secret
+func (w *Webhook) Redacted() *Webhook {
+	redacted := *w
+	redacted.Secret = ""
+	return &redacted
+}
+
+// Webhook delivery status constants
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusRetrying  = "retrying"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+// WebhookDelivery is one event sent to one webhook, with every attempt made
+// to deliver it. A delivery that used up its attempts is dead and only
+// sent again on manual redelivery.
+type WebhookDelivery struct {
+	ID            string          `json:"id" bson:"_id"`
+	WebhookID     string          `json:"webhookId" bson:"webhookId"`
+	EventType     string          `json:"eventType" bson:"eventType"`
+	EventSequence uint64          `json:"eventSequence" bson:"eventSequence"`
+	DeviceID      string          `json:"deviceId,omitempty" bson:"deviceId,omitempty"`
+	Payload       json.RawMessage `json:"payload" bson:"payload"`
+
+	Status        string            `json:"status" bson:"status"`
+	Attempts      []DeliveryAttempt `json:"attempts" bson:"attempts"`
+	NextAttemptAt *time.Time        `json:"nextAttemptAt,omitempty" bson:"nextAttemptAt,omitempty"`
+
+	// RedeliveryOf is the delivery this one was manually resent from
+	RedeliveryOf string `json:"redeliveryOf,omitempty" bson:"redeliveryOf,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// DeliveryAttempt records a single HTTP request made for a delivery
+type DeliveryAttempt struct {
+	Attempt    int       `json:"attempt" bson:"attempt"`
+	Timestamp  time.Time `json:"timestamp" bson:"timestamp"`
+	StatusCode int       `json:"statusCode,omitempty" bson:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs int64     `json:"durationMs" bson:"durationMs"`
+}
+
+// IsFinished reports whether the delivery will not be attempted again
+func (d *WebhookDelivery) IsFinished() bool {
+	return d.Status == DeliveryStatusDelivered || d.Status == DeliveryStatusDead
+}
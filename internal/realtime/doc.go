@@ -0,0 +1,56 @@
+// Package realtime streams events of the application event bus to clients.
+// The NBI and the web UI serve the same WebSocket protocol on /ws, the NBI
+// also streams the events as Server-Sent Events on /api/v1/events for
+// clients behind proxies without WebSocket support, see SSEHandler.
+//
+// # Topics
+//
+//	stats              statistics snapshot, sent on subscribe and at most
+//	                   once per second while devices or faults change
+//	devices            every device.* event
+//	device:<id>        device, fault and task events of one device
+//	faults             every fault.* event
+//	faults:<severity>  fault events of one severity, e.g. faults:critical
+//	tasks              every task.* event
+//	system             system events such as ACS connectivity changes
+//
+// # Client messages
+//
+//	{"action": "subscribe", "id": "1", "topics": ["stats", "faults:critical"]}
+//	{"action": "subscribe", "topics": ["devices"], "resumeFrom": 1520}
+//	{"action": "unsubscribe", "id": "2", "topics": ["stats"]}
+//	{"action": "ping"}
+//
+// The optional id is echoed in the reply. resumeFrom replays the retained
+// events after that sequence that match the subscribed topics. Topics and
+// the resume point can also be given when connecting, as in
+// /ws?topics=stats,faults:critical&resumeFrom=1520.
+//
+// Browsers may only connect from pages of the gateway itself and from the
+// allowedOrigins of the NBI or UI configuration.
+//
+// # Server messages
+//
+//	{"type": "welcome", "sequence": 1520, "heartbeatInterval": 30}
+//	{"type": "subscribed", "id": "1", "topics": ["stats", "faults:critical"]}
+//	{"type": "unsubscribed", "id": "2", "topics": ["stats"]}
+//	{"type": "event", "topics": ["faults:critical"], "sequence": 1521, "event": {...}}
+//	{"type": "stats", "data": {"devices": {...}, "faults": {...}, "system": {...}}}
+//	{"type": "heartbeat", "sequence": 1521}
+//	{"type": "resync", "reason": "...", "sequence": 1600}
+//	{"type": "error", "id": "3", "error": "unknown topic \"foo\""}
+//	{"type": "pong"}
+//
+// Every message carries a timestamp. Clients remember the sequence of the
+// last event they handled and pass it as resumeFrom after reconnecting.
+//
+// # Backpressure
+//
+// Each connection buffers a bounded number of events. A connection that
+// falls behind is resumed server side from the last event it was sent.
+// When those events are no longer retained the server sends resync with
+// the current sequence: the client should reload its state, the stream
+// continues with live events. A write that does not complete within the
+// write timeout closes the connection, as does a client that answers
+// neither heartbeats nor WebSocket pings.
+package realtime
@@ -0,0 +1,160 @@
+package realtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Topic names
+const (
+	TopicStats   = "stats"
+	TopicDevices = "devices"
+	TopicDevice  = "device"
+	TopicFaults  = "faults"
+	TopicTasks   = "tasks"
+	TopicSystem  = "system"
+)
+
+// Client actions
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+	ActionPing        = "ping"
+)
+
+// Server message types
+const (
+	MessageWelcome      = "welcome"
+	MessageSubscribed   = "subscribed"
+	MessageUnsubscribed = "unsubscribed"
+	MessageEvent        = "event"
+	MessageStats        = "stats"
+	MessageHeartbeat    = "heartbeat"
+	MessageResync       = "resync"
+	MessageError        = "error"
+	MessagePong         = "pong"
+)
+
+// ClientMessage is a request sent by a client
+type ClientMessage struct {
+	Action     string   `json:"action"`
+	ID         string   `json:"id,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+	ResumeFrom uint64   `json:"resumeFrom,omitempty"`
+}
+
+// ServerMessage is a message pushed to a client
+type ServerMessage struct {
+	Type      string         `json:"type"`
+	ID        string         `json:"id,omitempty"`
+	Topics    []string       `json:"topics,omitempty"`
+	Sequence  uint64         `json:"sequence,omitempty"`
+	Event     *context.Event `json:"event,omitempty"`
+	Data      interface{}    `json:"data,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+
+	// Seconds between heartbeats, sent with the welcome message
+	HeartbeatInterval int `json:"heartbeatInterval,omitempty"`
+}
+
+// Topic is a parsed subscription topic such as "faults:critical"
+type Topic struct {
+	Name string
+	Kind string
+	Arg  string
+}
+
+// ParseTopic parses and validates a topic name
+func ParseTopic(name string) (Topic, error) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(name), ":")
+	topic := Topic{Name: name, Kind: kind, Arg: arg}
+
+	switch kind {
+	case TopicStats, TopicDevices, TopicTasks, TopicSystem:
+		if arg != "" {
+			return topic, fmt.Errorf("topic %q takes no argument", kind)
+		}
+	case TopicDevice:
+		if arg == "" {
+			return topic, fmt.Errorf("topic %q needs a device ID", kind)
+		}
+	case TopicFaults:
+		if arg != "" && !models.IsValidSeverity(arg) {
+			return topic, fmt.Errorf("unknown fault severity %q", arg)
+		}
+	default:
+		return topic, fmt.Errorf("unknown topic %q", name)
+	}
+	return topic, nil
+}
+
+// Matches checks if an event belongs to the topic. The stats topic carries
+// snapshots, not events.
+func (t Topic) Matches(event *context.Event) bool {
+	switch t.Kind {
+	case TopicDevices:
+		return strings.HasPrefix(event.Type, "device.")
+	case TopicDevice:
+		return event.DeviceID == t.Arg
+	case TopicFaults:
+		return strings.HasPrefix(event.Type, "fault.") && (t.Arg == "" || event.Severity == t.Arg)
+	case TopicTasks:
+		return strings.HasPrefix(event.Type, "task.")
+	case TopicSystem:
+		return strings.HasPrefix(event.Type, "system.")
+	}
+	return false
+}
+
+// affectsStats checks if an event can change the statistics snapshot
+func affectsStats(event *context.Event) bool {
+	return strings.HasPrefix(event.Type, "device.") ||
+		strings.HasPrefix(event.Type, "fault.") ||
+		strings.HasPrefix(event.Type, "system.")
+}
+
+// visibleToTenant checks if an event may be pushed to a client of the
+// tenant. Events without a device, such as ACS connectivity changes, are
+// visible to every tenant. The device carried by the event is used when
+// present so that removed devices are still matched.
+func visibleToTenant(appContext *context.Context, tenantID string, event *context.Event) bool {
+	if tenantID == "" || event.DeviceID == "" {
+		return true
+	}
+	if event.Device != nil {
+		tenant, exists := appContext.GetTenant(tenantID)
+		return exists && tenant.Matches(event.Device)
+	}
+	return appContext.DeviceInTenant(tenantID, event.DeviceID)
+}
+
+// StatsSnapshot returns the statistics pushed on the stats topic, counted
+// over the devices of the tenant when one is given
+func StatsSnapshot(appContext *context.Context, tenantID string) map[string]interface{} {
+	stats := appContext.GetTenantDeviceStats(tenantID)
+	genieStatus := appContext.GetGenieACSStatus()
+
+	return map[string]interface{}{
+		"devices": map[string]interface{}{
+			"total":   stats.TotalDevices,
+			"online":  stats.OnlineDevices,
+			"offline": stats.OfflineDevices,
+		},
+		"faults": map[string]interface{}{
+			"active":     stats.ActiveFaults,
+			"critical":   stats.CriticalFaults,
+			"suppressed": stats.SuppressedFaults,
+		},
+		"system": map[string]interface{}{
+			"cwmpConnected": genieStatus.CWMPConnected,
+			"nbiConnected":  genieStatus.NBIConnected,
+			"fsConnected":   genieStatus.FSConnected,
+		},
+	}
+}
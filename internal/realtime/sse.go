@@ -0,0 +1,307 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// KeepAliveInterval is the time between keep-alive comments on an event
+// stream, short enough for proxies that close idle connections
+const KeepAliveInterval = 15 * time.Second
+
+// sseRetry is the reconnection delay suggested to EventSource clients
+const sseRetry = 5 * time.Second
+
+// SSEHandler streams events as Server-Sent Events. Each event is sent with
+// its sequence as id and its type as event name, so a reconnecting client
+// resumes through the Last-Event-ID header. Query parameters:
+//
+//	type      comma separated event types or patterns such as fault.*
+//	deviceId  events of one device
+//	severity  comma separated fault severities
+//	topics    comma separated topics of the WebSocket protocol
+//
+// A stream that cannot be resumed starts with a resync event, one that
+// includes the stats topic receives stats events without an id.
+func SSEHandler(appContext *context.Context, log *logrus.Entry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, topics, err := parseStreamQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		var resumeFrom uint64
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("lastEventId")
+		}
+		if lastEventID != "" {
+			if resumeFrom, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid Last-Event-ID",
+				})
+				return
+			}
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		s := &stream{
+			c:          c,
+			rc:         http.NewResponseController(c.Writer),
+			appContext: appContext,
+			filter:     filter,
+			topics:     topics,
+			tenant:     auth.Tenant(c),
+		}
+		defer s.closeSubscription()
+
+		if err := s.run(resumeFrom); err != nil {
+			log.Debugf("Event stream closed: %v", err)
+		}
+	}
+}
+
+// parseStreamQuery builds the event filter and topics of a stream request
+func parseStreamQuery(c *gin.Context) (context.EventFilter, []Topic, error) {
+	var filter context.EventFilter
+
+	for _, pattern := range splitQuery(c.Query("type")) {
+		if !context.IsValidEventPattern(pattern) {
+			return filter, nil, fmt.Errorf("unknown event type %q", pattern)
+		}
+		filter.Types = append(filter.Types, pattern)
+	}
+
+	for _, severity := range splitQuery(c.Query("severity")) {
+		if !models.IsValidSeverity(severity) {
+			return filter, nil, fmt.Errorf("unknown fault severity %q", severity)
+		}
+		filter.Severities = append(filter.Severities, severity)
+	}
+
+	filter.DeviceID = c.Query("deviceId")
+
+	var topics []Topic
+	for _, name := range splitQuery(c.Query("topics")) {
+		topic, err := ParseTopic(name)
+		if err != nil {
+			return filter, nil, err
+		}
+		topics = append(topics, topic)
+	}
+
+	return filter, topics, nil
+}
+
+// splitQuery splits a comma separated query value, skipping empty items
+func splitQuery(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// stream is one Server-Sent Events connection
+type stream struct {
+	c          *gin.Context
+	rc         *http.ResponseController
+	appContext *context.Context
+	filter     context.EventFilter
+	topics     []Topic
+	tenant     string
+
+	sub          *context.Subscription
+	lastSequence uint64
+	statsDirty   bool
+}
+
+// run writes events until the client disconnects or a write fails
+func (s *stream) run(resumeFrom uint64) error {
+	s.lastSequence = s.appContext.EventSequence()
+	if resumeFrom == 0 {
+		resumeFrom = s.lastSequence
+	}
+
+	if err := s.write(fmt.Sprintf("retry: %d\n\n", sseRetry.Milliseconds())); err != nil {
+		return err
+	}
+	if err := s.subscribe(resumeFrom); err != nil {
+		return err
+	}
+	if s.wantsStats() {
+		if err := s.sendStats(); err != nil {
+			return err
+		}
+	}
+
+	keepAlive := time.NewTicker(KeepAliveInterval)
+	defer keepAlive.Stop()
+	statsTicker := time.NewTicker(statsInterval)
+	defer statsTicker.Stop()
+
+	for {
+		var events <-chan context.Event
+		if s.sub != nil {
+			events = s.sub.C
+		}
+
+		var err error
+		select {
+		case <-s.c.Request.Context().Done():
+			return nil
+
+		case event, ok := <-events:
+			if !ok {
+				err = s.recover()
+			} else {
+				err = s.deliver(&event)
+			}
+
+		case <-keepAlive.C:
+			err = s.write(": keep-alive\n\n")
+
+		case <-statsTicker.C:
+			if s.statsDirty {
+				err = s.sendStats()
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// deliver writes an event when it passes the filter and matches the topics,
+// if any were given. Filtered events still refresh the stats.
+func (s *stream) deliver(event *context.Event) error {
+	s.lastSequence = event.Sequence
+	if affectsStats(event) {
+		s.statsDirty = true
+	}
+
+	if !s.filter.Matches(event) || !visibleToTenant(s.appContext, s.tenant, event) {
+		return nil
+	}
+	if len(s.topics) > 0 {
+		matched := false
+		for _, topic := range s.topics {
+			if topic.Matches(event) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data))
+}
+
+// subscribe subscribes to the event bus from resumeFrom. When those events
+// are no longer retained the client is told to resync and receives live
+// events only.
+func (s *stream) subscribe(resumeFrom uint64) error {
+	sub, err := s.appContext.SubscribeEvents(context.EventFilter{}, eventBuffer, resumeFrom)
+	if errors.Is(err, models.ErrEventsExpired) {
+		sub, err = s.appContext.SubscribeEvents(context.EventFilter{}, eventBuffer, 0)
+		if err == nil {
+			s.lastSequence = s.appContext.EventSequence()
+			s.statsDirty = true
+			err = s.writeJSON(fmt.Sprintf("id: %d\nevent: %s\n", s.lastSequence, MessageResync), gin.H{
+				"reason":   "events after the requested sequence are no longer available",
+				"sequence": s.lastSequence,
+			})
+		}
+	}
+	if sub != nil {
+		s.sub = sub
+	}
+	return err
+}
+
+// recover resumes after the event bus dropped the stream for falling behind
+func (s *stream) recover() error {
+	reason := s.sub.Err()
+	s.sub = nil
+	if !errors.Is(reason, models.ErrSlowConsumer) {
+		return reason
+	}
+	return s.subscribe(s.lastSequence)
+}
+
+// wantsStats checks if the stats topic was requested
+func (s *stream) wantsStats() bool {
+	for _, topic := range s.topics {
+		if topic.Kind == TopicStats {
+			return true
+		}
+	}
+	return false
+}
+
+// sendStats writes a statistics snapshot
+func (s *stream) sendStats() error {
+	s.statsDirty = false
+	if !s.wantsStats() {
+		return nil
+	}
+	return s.writeJSON(fmt.Sprintf("event: %s\n", MessageStats), StatsSnapshot(s.appContext, s.tenant))
+}
+
+// writeJSON writes an event with the given header lines and JSON data
+func (s *stream) writeJSON(header string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.write(fmt.Sprintf("%sdata: %s\n\n", header, data))
+}
+
+// write sends raw stream data and flushes it. The deadline is extended on
+// every write so the server write timeout does not end healthy streams.
+func (s *stream) write(data string) error {
+	// Not every writer supports deadlines, the server timeout applies then
+	s.rc.SetWriteDeadline(time.Now().Add(writeWait))
+
+	if _, err := s.c.Writer.WriteString(data); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// closeSubscription ends the event bus subscription
+func (s *stream) closeSubscription() {
+	if s.sub != nil {
+		s.sub.Close()
+		s.sub = nil
+	}
+}
@@ -0,0 +1,402 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Connection timing
+const (
+	// HeartbeatInterval is the time between heartbeats and pings
+	HeartbeatInterval = 30 * time.Second
+	// pongWait is how long a silent client is kept, it must answer pings
+	pongWait = 2 * HeartbeatInterval
+	// writeWait bounds a single write to a slow client
+	writeWait = 10 * time.Second
+	// statsInterval is the minimum time between two stats pushes
+	statsInterval = time.Second
+	// maxMessageSize limits client messages
+	maxMessageSize = 64 * 1024
+	// eventBuffer is the number of events a connection may lag behind
+	eventBuffer = 256
+)
+
+// originChecker accepts requests without an Origin header, which do not come
+// from browsers, pages served by the requested host and the allowed origins.
+// Other pages must not open connections with the cookies of the user.
+func originChecker(allowedOrigins []string, log *logrus.Entry) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		if allowed[strings.ToLower(origin)] {
+			return true
+		}
+		if parsed, err := url.Parse(origin); err == nil && strings.EqualFold(parsed.Host, r.Host) {
+			return true
+		}
+
+		log.Warnf("Refused WebSocket connection from origin %s", origin)
+		return false
+	}
+}
+
+// WebSocketHandler serves the real-time protocol described in the package
+// documentation. Browsers may connect from pages of the gateway itself and
+// from allowedOrigins, given as scheme://host[:port].
+func WebSocketHandler(appContext *context.Context, allowedOrigins []string, log *logrus.Entry) gin.HandlerFunc {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: originChecker(allowedOrigins, log),
+	}
+
+	return func(c *gin.Context) {
+		var resumeFrom uint64
+		if value := c.Query("resumeFrom"); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid resumeFrom",
+				})
+				return
+			}
+			resumeFrom = parsed
+		}
+
+		topics := make(map[string]Topic)
+		if value := c.Query("topics"); value != "" {
+			for _, name := range strings.Split(value, ",") {
+				topic, err := ParseTopic(name)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{
+						"error": err.Error(),
+					})
+					return
+				}
+				topics[topic.Name] = topic
+			}
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			log.Errorf("WebSocket upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		s := &session{
+			conn:       conn,
+			appContext: appContext,
+			log:        log,
+			topics:     topics,
+			tenant:     auth.Tenant(c),
+		}
+		defer s.closeSubscription()
+
+		if err := s.run(resumeFrom); err != nil {
+			log.Debugf("WebSocket connection closed: %v", err)
+		}
+	}
+}
+
+// session is one client connection. Only the goroutine running run writes
+// to the connection.
+type session struct {
+	conn       *websocket.Conn
+	appContext *context.Context
+	log        *logrus.Entry
+
+	topics       map[string]Topic
+	tenant       string
+	sub          *context.Subscription
+	lastSequence uint64
+	statsDirty   bool
+}
+
+// run serves the connection until it fails or the client goes away
+func (s *session) run(resumeFrom uint64) error {
+	s.lastSequence = s.appContext.EventSequence()
+	if err := s.send(ServerMessage{
+		Type:              MessageWelcome,
+		Sequence:          s.lastSequence,
+		HeartbeatInterval: int(HeartbeatInterval / time.Second),
+	}); err != nil {
+		return err
+	}
+
+	// Without a resume point, replay what was published since the welcome
+	if resumeFrom == 0 {
+		resumeFrom = s.lastSequence
+	}
+	if err := s.subscribe(resumeFrom); err != nil {
+		return err
+	}
+	if _, ok := s.topics[TopicStats]; ok {
+		if err := s.sendStats(""); err != nil {
+			return err
+		}
+	}
+
+	requests := make(chan clientRequest)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go s.read(requests, readErr, done)
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+	statsTicker := time.NewTicker(statsInterval)
+	defer statsTicker.Stop()
+
+	for {
+		var events <-chan context.Event
+		if s.sub != nil {
+			events = s.sub.C
+		}
+
+		var err error
+		select {
+		case event, ok := <-events:
+			if !ok {
+				err = s.recover()
+			} else {
+				err = s.deliver(&event)
+			}
+
+		case request := <-requests:
+			if request.err != nil {
+				err = s.send(ServerMessage{Type: MessageError, Error: "invalid message: " + request.err.Error()})
+			} else {
+				err = s.handle(request.message)
+			}
+
+		case err = <-readErr:
+
+		case <-heartbeat.C:
+			err = s.send(ServerMessage{Type: MessageHeartbeat, Sequence: s.lastSequence})
+			if err == nil {
+				err = s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait))
+			}
+
+		case <-statsTicker.C:
+			if s.statsDirty {
+				err = s.sendStats("")
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// clientRequest is a decoded client message or the reason it could not be
+// decoded
+type clientRequest struct {
+	message ClientMessage
+	err     error
+}
+
+// read decodes client messages until the connection fails or the session
+// ends. Any message or pong keeps the connection alive.
+func (s *session) read(requests chan<- clientRequest, readErr chan<- error, done <-chan struct{}) {
+	s.conn.SetReadLimit(maxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				s.log.Errorf("WebSocket read error: %v", err)
+			}
+			readErr <- err
+			return
+		}
+		s.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var request clientRequest
+		request.err = json.Unmarshal(data, &request.message)
+
+		select {
+		case requests <- request:
+		case <-done:
+			return
+		}
+	}
+}
+
+// handle executes a client request
+func (s *session) handle(message ClientMessage) error {
+	switch message.Action {
+	case ActionPing:
+		return s.send(ServerMessage{Type: MessagePong, ID: message.ID})
+
+	case ActionSubscribe, ActionUnsubscribe:
+		topics := make([]Topic, 0, len(message.Topics))
+		for _, name := range message.Topics {
+			topic, err := ParseTopic(name)
+			if err != nil {
+				return s.send(ServerMessage{Type: MessageError, ID: message.ID, Error: err.Error()})
+			}
+			topics = append(topics, topic)
+		}
+
+		if message.Action == ActionUnsubscribe {
+			for _, topic := range topics {
+				delete(s.topics, topic.Name)
+			}
+			return s.send(ServerMessage{Type: MessageUnsubscribed, ID: message.ID, Topics: message.Topics})
+		}
+
+		_, hadStats := s.topics[TopicStats]
+		for _, topic := range topics {
+			s.topics[topic.Name] = topic
+		}
+		if err := s.send(ServerMessage{Type: MessageSubscribed, ID: message.ID, Topics: s.topicNames()}); err != nil {
+			return err
+		}
+
+		// New stats subscribers start from a full snapshot
+		if !hadStats {
+			if err := s.sendStats(message.ID); err != nil {
+				return err
+			}
+		}
+
+		if message.ResumeFrom > 0 {
+			s.closeSubscription()
+			return s.subscribe(message.ResumeFrom)
+		}
+		return nil
+
+	default:
+		return s.send(ServerMessage{Type: MessageError, ID: message.ID, Error: fmt.Sprintf("unknown action %q", message.Action)})
+	}
+}
+
+// deliver pushes an event to the client when it matches a subscribed topic
+func (s *session) deliver(event *context.Event) error {
+	s.lastSequence = event.Sequence
+	if affectsStats(event) {
+		s.statsDirty = true
+	}
+	if !visibleToTenant(s.appContext, s.tenant, event) {
+		return nil
+	}
+
+	var matched []string
+	for name, topic := range s.topics {
+		if topic.Matches(event) {
+			matched = append(matched, name)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	sort.Strings(matched)
+	return s.send(ServerMessage{
+		Type:     MessageEvent,
+		Topics:   matched,
+		Sequence: event.Sequence,
+		Event:    event,
+	})
+}
+
+// subscribe subscribes to the event bus, replaying events after resumeFrom
+// when it is set. When they are no longer retained the client is told to
+// resync and receives live events only.
+func (s *session) subscribe(resumeFrom uint64) error {
+	sub, err := s.appContext.SubscribeEvents(context.EventFilter{}, eventBuffer, resumeFrom)
+	if errors.Is(err, models.ErrEventsExpired) {
+		sub, err = s.appContext.SubscribeEvents(context.EventFilter{}, eventBuffer, 0)
+		if err == nil {
+			s.lastSequence = s.appContext.EventSequence()
+			s.statsDirty = true
+			err = s.send(ServerMessage{
+				Type:     MessageResync,
+				Reason:   "events after the requested sequence are no longer available",
+				Sequence: s.lastSequence,
+			})
+		}
+	}
+	if sub != nil {
+		s.sub = sub
+	}
+	return err
+}
+
+// recover resumes after the event bus dropped the connection for falling
+// behind
+func (s *session) recover() error {
+	reason := s.sub.Err()
+	s.sub = nil
+	if !errors.Is(reason, models.ErrSlowConsumer) {
+		return reason
+	}
+
+	s.log.Warnf("WebSocket client fell behind at sequence %d, resuming", s.lastSequence)
+	return s.subscribe(s.lastSequence)
+}
+
+// sendStats pushes a statistics snapshot
+func (s *session) sendStats(id string) error {
+	if _, ok := s.topics[TopicStats]; !ok {
+		return nil
+	}
+	s.statsDirty = false
+	return s.send(ServerMessage{
+		Type:   MessageStats,
+		ID:     id,
+		Topics: []string{TopicStats},
+		Data:   StatsSnapshot(s.appContext, s.tenant),
+	})
+}
+
+// send writes a message, giving up on clients that do not read
+func (s *session) send(message ServerMessage) error {
+	message.Timestamp = time.Now().UTC()
+	s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return s.conn.WriteJSON(message)
+}
+
+// topicNames returns the subscribed topics in a stable order
+func (s *session) topicNames() []string {
+	names := make([]string, 0, len(s.topics))
+	for name := range s.topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// closeSubscription ends the event bus subscription
+func (s *session) closeSubscription() {
+	if s.sub != nil {
+		s.sub.Close()
+		s.sub = nil
+	}
+}
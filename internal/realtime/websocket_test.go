@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nextranet/gateway/c-plane/internal/context"
+)
+
+func TestWebSocketOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	router := gin.New()
+	router.GET("/ws", WebSocketHandler(context.NewContext(), []string{"https://NOC.example.com/"}, logrus.NewEntry(log)))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	address := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	self := server.URL
+
+	tests := []struct {
+		name   string
+		origin string
+		allow  bool
+	}{
+		{"no origin", "", true},
+		{"same host", self, true},
+		{"allowed origin", "https://noc.example.com", true},
+		{"other origin", "https://evil.example.net", false},
+		{"allowed host other port", "https://noc.example.com:8443", false},
+		{"same host prefix", self + ".evil.example.net", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+
+			conn, resp, err := websocket.DefaultDialer.Dial(address, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if tt.allow && err != nil {
+				t.Fatalf("connection from %q refused: %v", tt.origin, err)
+			}
+			if !tt.allow {
+				if err == nil {
+					t.Fatalf("connection from %q accepted", tt.origin)
+				}
+				if resp == nil || resp.StatusCode != http.StatusForbidden {
+					t.Errorf("connection from %q refused with %v, want 403", tt.origin, resp)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package rules
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Transition is a change of alarm state produced by the engine
+type Transition struct {
+	Rule     *models.AlarmRule
+	DeviceID string
+	FaultID  string
+	Raised   bool
+	Detail   string
+}
+
+// Engine evaluates alarm rules against devices and keeps the per device
+// alarm state needed for hold-down timers and prev()
+type Engine struct {
+	mu       sync.Mutex
+	compiled map[string]*compiledRule
+	states   map[string]*alarmState
+	samples  map[string]map[string]interface{}
+}
+
+type compiledRule struct {
+	updatedAt time.Time
+	raise     *Expression
+	clear     *Expression
+}
+
+type alarmState struct {
+	ruleID        string
+	ruleName      string
+	deviceID      string
+	active        bool
+	faultID       string
+	pendingSince  time.Time
+	clearingSince time.Time
+}
+
+// NewEngine creates a new rule engine
+func NewEngine() *Engine {
+	return &Engine{
+		compiled: make(map[string]*compiledRule),
+		states:   make(map[string]*alarmState),
+		samples:  make(map[string]map[string]interface{}),
+	}
+}
+
+// Validate compiles the expressions of a rule
+func Validate(rule *models.AlarmRule) error {
+	if _, err := Compile(rule.Condition); err != nil {
+		return fmt.Errorf("invalid condition: %w", err)
+	}
+	if rule.ClearCondition != "" {
+		if _, err := Compile(rule.ClearCondition); err != nil {
+			return fmt.Errorf("invalid clear condition: %w", err)
+		}
+	}
+	return nil
+}
+
+// Restore marks an alarm as active, used to adopt faults raised before a
+// restart
+func (e *Engine) Restore(ruleID, deviceID, faultID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.states[stateKey(ruleID, deviceID)] = &alarmState{
+		ruleID:   ruleID,
+		deviceID: deviceID,
+		active:   true,
+		faultID:  faultID,
+	}
+}
+
+// Evaluate runs the rules that apply to a device and returns the alarms
+// that were raised or cleared
+func (e *Engine) Evaluate(rules []*models.AlarmRule, device *models.Device, now time.Time) ([]Transition, []error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	env := &deviceEnv{device: device, now: now, prev: e.samples[device.ID]}
+	transitions := make([]Transition, 0)
+	errs := make([]error, 0)
+	refs := make(map[string]bool)
+
+	for _, rule := range rules {
+		if !rule.Enabled || !rule.MatchesDevice(device) {
+			continue
+		}
+
+		compiled, err := e.compile(rule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+			continue
+		}
+		for _, ref := range compiled.raise.PreviousRefs() {
+			refs[ref] = true
+		}
+		if compiled.clear != nil {
+			for _, ref := range compiled.clear.PreviousRefs() {
+				refs[ref] = true
+			}
+		}
+
+		key := stateKey(rule.ID, device.ID)
+		state, exists := e.states[key]
+		if !exists {
+			state = &alarmState{ruleID: rule.ID, deviceID: device.ID}
+			e.states[key] = state
+		}
+		state.ruleName = rule.Name
+
+		if transition, err := e.step(rule, compiled, state, env, now); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s on %s: %w", rule.Name, device.ID, err))
+		} else if transition != nil {
+			transitions = append(transitions, *transition)
+		}
+	}
+
+	// Remember the values prev() will need next time
+	if len(refs) > 0 {
+		sample := make(map[string]interface{}, len(refs))
+		for ref := range refs {
+			if value, ok := env.Lookup(ref); ok {
+				sample[ref] = value
+			}
+		}
+		e.samples[device.ID] = sample
+	} else {
+		delete(e.samples, device.ID)
+	}
+
+	return transitions, errs
+}
+
+// Prune clears the alarms of rules that were removed or disabled, of devices
+// that are no longer in the inventory and of devices the selector of their
+// rule no longer matches, and forgets their state and prev() samples
+func (e *Engine) Prune(rules []*models.AlarmRule, devices []*models.Device) []Transition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	enabled := make(map[string]*models.AlarmRule, len(rules))
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled[rule.ID] = rule
+		}
+	}
+	known := make(map[string]*models.Device, len(devices))
+	for _, device := range devices {
+		known[device.ID] = device
+	}
+
+	transitions := make([]Transition, 0)
+	for key, state := range e.states {
+		rule, ruleEnabled := enabled[state.ruleID]
+		device, deviceKnown := known[state.deviceID]
+		if ruleEnabled && deviceKnown && rule.MatchesDevice(device) {
+			continue
+		}
+		if state.active {
+			detail := "Device removed"
+			if deviceKnown {
+				detail = "Device no longer matches the rule"
+			}
+			if !ruleEnabled {
+				name := state.ruleName
+				if name == "" {
+					name = state.ruleID
+				}
+				rule = &models.AlarmRule{ID: state.ruleID, Name: name}
+				detail = "Rule removed or disabled"
+			}
+			transitions = append(transitions, Transition{
+				Rule:     rule,
+				DeviceID: state.deviceID,
+				FaultID:  state.faultID,
+				Detail:   detail,
+			})
+		}
+		delete(e.states, key)
+	}
+	for id := range e.compiled {
+		if _, ok := enabled[id]; !ok {
+			delete(e.compiled, id)
+		}
+	}
+	for deviceID := range e.samples {
+		if _, ok := known[deviceID]; !ok || len(enabled) == 0 {
+			delete(e.samples, deviceID)
+		}
+	}
+
+	return transitions
+}
+
+// step advances the alarm state machine of one rule on one device.
+// Must be called with mu held.
+func (e *Engine) step(rule *models.AlarmRule, compiled *compiledRule, state *alarmState, env *deviceEnv, now time.Time) (*Transition, error) {
+	if !state.active {
+		raise, err := compiled.raise.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if !raise {
+			state.pendingSince = time.Time{}
+			return nil, nil
+		}
+		if state.pendingSince.IsZero() {
+			state.pendingSince = now
+		}
+		if now.Sub(state.pendingSince) < time.Duration(rule.RaiseAfterSeconds)*time.Second {
+			return nil, nil
+		}
+
+		state.active = true
+		state.faultID = models.FaultChannelGatewayRule + "-" + uuid.New().String()
+		state.pendingSince = time.Time{}
+		state.clearingSince = time.Time{}
+
+		return &Transition{
+			Rule:     rule,
+			DeviceID: state.deviceID,
+			FaultID:  state.faultID,
+			Raised:   true,
+			Detail:   env.describe(compiled.raise),
+		}, nil
+	}
+
+	var clear bool
+	if compiled.clear != nil {
+		var err error
+		if clear, err = compiled.clear.Eval(env); err != nil {
+			return nil, err
+		}
+	} else {
+		raise, err := compiled.raise.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+		clear = !raise
+	}
+
+	if !clear {
+		state.clearingSince = time.Time{}
+		return nil, nil
+	}
+	if state.clearingSince.IsZero() {
+		state.clearingSince = now
+	}
+	if now.Sub(state.clearingSince) < time.Duration(rule.ClearAfterSeconds)*time.Second {
+		return nil, nil
+	}
+
+	transition := &Transition{
+		Rule:     rule,
+		DeviceID: state.deviceID,
+		FaultID:  state.faultID,
+		Detail:   "Condition cleared",
+	}
+	state.active = false
+	state.faultID = ""
+	state.clearingSince = time.Time{}
+	return transition, nil
+}
+
+// compile returns the cached expressions of a rule, recompiling after edits.
+// Must be called with mu held.
+func (e *Engine) compile(rule *models.AlarmRule) (*compiledRule, error) {
+	if cached, ok := e.compiled[rule.ID]; ok && cached.updatedAt.Equal(rule.UpdatedAt) {
+		return cached, nil
+	}
+
+	raise, err := Compile(rule.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := &compiledRule{updatedAt: rule.UpdatedAt, raise: raise}
+	if rule.ClearCondition != "" {
+		if compiled.clear, err = Compile(rule.ClearCondition); err != nil {
+			return nil, err
+		}
+	}
+
+	e.compiled[rule.ID] = compiled
+	return compiled, nil
+}
+
+func stateKey(ruleID, deviceID string) string {
+	return ruleID + "|" + deviceID
+}
+
+// deviceEnv exposes a device to rule expressions.
+//
+// Besides parameter paths the following variables are available:
+// lastInform and lastBoot (seconds since), online, uptime (seconds since
+// last boot), manufacturer, model and softwareVersion.
+type deviceEnv struct {
+	device *models.Device
+	now    time.Time
+	prev   map[string]interface{}
+}
+
+// Lookup implements Env
+func (env *deviceEnv) Lookup(name string) (interface{}, bool) {
+	device := env.device
+
+	switch name {
+	case "lastInform":
+		return env.secondsSince(device.LastInform)
+	case "lastBoot", "uptime":
+		return env.secondsSince(device.LastBoot)
+	case "online":
+		return device.Status.Online, true
+	case "manufacturer":
+		return device.DeviceID.Manufacturer, true
+	case "model":
+		return device.DeviceID.ModelName, true
+	case "softwareVersion":
+		return device.DeviceID.SoftwareVersion, true
+	}
+
+	return device.ParameterValue(name)
+}
+
+// Previous implements Env
+func (env *deviceEnv) Previous(name string) (interface{}, bool) {
+	value, ok := env.prev[name]
+	return value, ok
+}
+
+func (env *deviceEnv) secondsSince(t time.Time) (interface{}, bool) {
+	if t.IsZero() {
+		return nil, false
+	}
+	return env.now.Sub(t).Seconds(), true
+}
+
+// describe renders the fault detail for an expression
+func (env *deviceEnv) describe(expr *Expression) string {
+	return "Condition: " + expr.Source()
+}
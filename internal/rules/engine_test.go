@@ -0,0 +1,172 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+func TestPruneRemovedDevices(t *testing.T) {
+	engine := NewEngine()
+	now := time.Now()
+	rule := &models.AlarmRule{ID: "reboot-loop", Name: "Reboot loop", Enabled: true, Condition: "uptime < 300 && prev(uptime) > uptime"}
+	rules := []*models.AlarmRule{rule}
+
+	kept := &models.Device{ID: "cpe-kept", LastBoot: now.Add(-time.Hour)}
+	removed := &models.Device{ID: "cpe-removed", LastBoot: now.Add(-time.Hour)}
+	for _, device := range []*models.Device{kept, removed} {
+		engine.Evaluate(rules, device, now)
+	}
+
+	// cpe-removed rebooted and raised the alarm before it left the inventory
+	removed.LastBoot = now.Add(time.Minute - time.Second)
+	transitions, errs := engine.Evaluate(rules, removed, now.Add(time.Minute))
+	if len(errs) > 0 || len(transitions) != 1 || !transitions[0].Raised {
+		t.Fatalf("reboot raised %+v, errors %v", transitions, errs)
+	}
+	faultID := transitions[0].FaultID
+
+	transitions = engine.Prune(rules, []*models.Device{kept})
+	if len(transitions) != 1 || transitions[0].Raised || transitions[0].FaultID != faultID || transitions[0].Rule != rule {
+		t.Errorf("pruning the removed device gave %+v, want its alarm cleared", transitions)
+	}
+	if len(engine.states) != 1 || engine.states[stateKey(rule.ID, kept.ID)] == nil {
+		t.Errorf("%d alarm states kept, want the one of cpe-kept", len(engine.states))
+	}
+	if len(engine.samples) != 1 || engine.samples[kept.ID] == nil {
+		t.Errorf("%d prev() samples kept, want the one of cpe-kept", len(engine.samples))
+	}
+
+	// Without rules nothing is sampled
+	engine.Prune(nil, []*models.Device{kept})
+	if len(engine.states) != 0 || len(engine.samples) != 0 {
+		t.Errorf("%d states and %d samples kept without rules", len(engine.states), len(engine.samples))
+	}
+}
+
+func TestHoldDown(t *testing.T) {
+	// step is a temperature reported after seconds and the transition
+	// expected from it: raise, clear or none
+	type step struct {
+		after       int
+		temperature float64
+		want        string
+	}
+	tests := []struct {
+		name  string
+		rule  *models.AlarmRule
+		steps []step
+	}{
+		{
+			name: "immediate",
+			rule: &models.AlarmRule{Condition: "Device.Temperature > 80"},
+			steps: []step{
+				{0, 85, "raise"},
+				{10, 85, ""},
+				{20, 75, "clear"},
+				{30, 75, ""},
+			},
+		},
+		{
+			name: "hold-down with hysteresis",
+			rule: &models.AlarmRule{
+				Condition:         "Device.Temperature > 80",
+				ClearCondition:    "Device.Temperature < 70",
+				RaiseAfterSeconds: 60,
+				ClearAfterSeconds: 120,
+			},
+			steps: []step{
+				{0, 85, ""},
+				{30, 85, ""},
+				// Dropping below the condition restarts the raise timer
+				{45, 75, ""},
+				{60, 85, ""},
+				{119, 85, ""},
+				{120, 85, "raise"},
+				// Between the thresholds the alarm stays
+				{150, 75, ""},
+				{180, 65, ""},
+				// Leaving the clear condition restarts the clear timer
+				{240, 75, ""},
+				{250, 65, ""},
+				{369, 65, ""},
+				{370, 65, "clear"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine()
+			tt.rule.ID, tt.rule.Name, tt.rule.Enabled = "overheat", "Overheat", true
+			rules := []*models.AlarmRule{tt.rule}
+			start := time.Now()
+
+			var faultID string
+			for _, step := range tt.steps {
+				device := &models.Device{ID: "cpe-1", Parameters: map[string]models.Parameter{
+					"Device.Temperature": {Path: "Device.Temperature", Value: step.temperature},
+				}}
+				transitions, errs := engine.Evaluate(rules, device, start.Add(time.Duration(step.after)*time.Second))
+				if len(errs) > 0 {
+					t.Fatalf("after %ds: %v", step.after, errs)
+				}
+
+				got := ""
+				switch {
+				case len(transitions) > 1:
+					t.Fatalf("after %ds: %d transitions", step.after, len(transitions))
+				case len(transitions) == 1 && transitions[0].Raised:
+					got, faultID = "raise", transitions[0].FaultID
+				case len(transitions) == 1:
+					got = "clear"
+					if transitions[0].FaultID != faultID {
+						t.Errorf("after %ds: cleared fault %s, raised %s", step.after, transitions[0].FaultID, faultID)
+					}
+				}
+				if got != step.want {
+					t.Errorf("after %ds at %v: got %q, want %q", step.after, step.temperature, got, step.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPruneUnmatchedDevices(t *testing.T) {
+	engine := NewEngine()
+	now := time.Now()
+	rule := &models.AlarmRule{ID: "offline", Name: "Offline", Enabled: true, Condition: "!online", Tags: []string{"vip"}}
+	rules := []*models.AlarmRule{rule}
+
+	device := &models.Device{ID: "cpe-1", Tags: map[string]bool{"vip": true}}
+	transitions, errs := engine.Evaluate(rules, device, now)
+	if len(errs) > 0 || len(transitions) != 1 || !transitions[0].Raised {
+		t.Fatalf("offline device raised %+v, errors %v", transitions, errs)
+	}
+	faultID := transitions[0].FaultID
+
+	// While the device matches the alarm stays
+	if transitions := engine.Prune(rules, []*models.Device{device}); len(transitions) != 0 {
+		t.Fatalf("pruning a matching device gave %+v", transitions)
+	}
+
+	// Evaluate skips the device once the tag is gone, Prune clears the alarm
+	device.Tags = nil
+	transitions = engine.Prune(rules, []*models.Device{device})
+	if len(transitions) != 1 || transitions[0].Raised || transitions[0].FaultID != faultID || transitions[0].Rule != rule {
+		t.Fatalf("pruning the unmatched device gave %+v, want its alarm cleared", transitions)
+	}
+	if transitions[0].Detail != "Device no longer matches the rule" {
+		t.Errorf("alarm cleared with %q", transitions[0].Detail)
+	}
+	if len(engine.states) != 0 {
+		t.Errorf("%d alarm states kept", len(engine.states))
+	}
+
+	// Matching again raises a new alarm
+	device.Tags = map[string]bool{"vip": true}
+	if transitions, _ := engine.Evaluate(rules, device, now.Add(time.Minute)); len(transitions) != 1 || transitions[0].FaultID == faultID {
+		t.Errorf("matching device again raised %+v, want a new alarm", transitions)
+	}
+}
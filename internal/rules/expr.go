@@ -0,0 +1,684 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Env resolves identifiers while an expression is evaluated
+type Env interface {
+	// Lookup returns the current value of a parameter path or variable
+	Lookup(name string) (interface{}, bool)
+	// Previous returns the value seen at the previous evaluation
+	Previous(name string) (interface{}, bool)
+}
+
+// Expression is a compiled rule expression
+type Expression struct {
+	source string
+	root   node
+	prev   []string
+}
+
+// Source returns the expression text
+func (e *Expression) Source() string {
+	return e.source
+}
+
+// PreviousRefs returns the identifiers referenced through prev()
+func (e *Expression) PreviousRefs() []string {
+	return e.prev
+}
+
+// Eval evaluates the expression to a boolean. Missing values make
+// comparisons false rather than failing, so a rule never fires on a device
+// that does not report the parameter.
+func (e *Expression) Eval(env Env) (bool, error) {
+	value, err := e.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(value), nil
+}
+
+// Compile parses an expression.
+//
+// The grammar supports parameter paths (InternetGatewayDevice.DeviceInfo.UpTime),
+// numbers, quoted strings, true/false, the operators || && ! == != < <= > >=
+// + - * / and parentheses, and the functions prev(path), exists(path),
+// num(x) and contains(s, sub).
+func Compile(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+
+	return &Expression{source: source, root: root, prev: p.prev}, nil
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(source string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+
+		default:
+			start := i
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "&&", "||", "==", "!=", "<=", ">=":
+				tokens = append(tokens, token{kind: tokenOperator, text: two, pos: start})
+				i += 2
+				continue
+			}
+			switch r {
+			case '<', '>', '!', '+', '-', '*', '/':
+				tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: start})
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, start)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// Parser
+
+type parser struct {
+	tokens []token
+	pos    int
+	prev   []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) acceptOperator(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseEquality()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseEquality()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) parseEquality() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("==", "!=")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &compareNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("<", "<=", ">", ">=")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &compareNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOperator("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.acceptOperator("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return &literalNode{value: value}, nil
+
+	case tokenString:
+		return &literalNode{value: t.text}, nil
+
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", t.pos)
+		}
+		return inner, nil
+
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(t)
+		}
+		return &identNode{name: t.text}, nil
+
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	p.next() // (
+
+	args := make([]node, 0)
+	if p.peek().kind != tokenRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.next().kind != tokenRParen {
+		return nil, fmt.Errorf("missing closing parenthesis for %s at position %d", name.text, name.pos)
+	}
+
+	switch name.text {
+	case "prev", "exists":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s expects one parameter path", name.text)
+		}
+		ident, ok := args[0].(*identNode)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a parameter path", name.text)
+		}
+		if name.text == "prev" {
+			p.prev = append(p.prev, ident.name)
+			return &prevNode{name: ident.name}, nil
+		}
+		return &existsNode{name: ident.name}, nil
+	case "num":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("num expects one argument")
+		}
+		return &numNode{operand: args[0]}, nil
+	case "contains":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("contains expects two arguments")
+		}
+		return &containsNode{haystack: args[0], needle: args[1]}, nil
+	default:
+		return nil, fmt.Errorf("unknown function %s at position %d", name.text, name.pos)
+	}
+}
+
+// Evaluation
+
+type node interface {
+	eval(env Env) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(Env) (interface{}, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(env Env) (interface{}, error) {
+	value, _ := env.Lookup(n.name)
+	return value, nil
+}
+
+type prevNode struct {
+	name string
+}
+
+func (n *prevNode) eval(env Env) (interface{}, error) {
+	value, _ := env.Previous(n.name)
+	return value, nil
+}
+
+type existsNode struct {
+	name string
+}
+
+func (n *existsNode) eval(env Env) (interface{}, error) {
+	value, ok := env.Lookup(n.name)
+	return ok && value != nil, nil
+}
+
+type numNode struct {
+	operand node
+}
+
+func (n *numNode) eval(env Env) (interface{}, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := toNumber(value); ok {
+		return f, nil
+	}
+	return nil, nil
+}
+
+type containsNode struct {
+	haystack node
+	needle   node
+}
+
+func (n *containsNode) eval(env Env) (interface{}, error) {
+	haystack, err := n.haystack.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	needle, err := n.needle.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if haystack == nil || needle == nil {
+		return false, nil
+	}
+	return strings.Contains(toString(haystack), toString(needle)), nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(env Env) (interface{}, error) {
+	value, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(value), nil
+	}
+	f, ok := toNumber(value)
+	if !ok {
+		return nil, nil
+	}
+	return -f, nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(env Env) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" && !truthy(left) {
+		return false, nil
+	}
+	if n.op == "||" && truthy(left) {
+		return true, nil
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(right), nil
+}
+
+type arithNode struct {
+	op          string
+	left, right node
+}
+
+func (n *arithNode) eval(env Env) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	l, lok := toNumber(left)
+	r, rok := toNumber(right)
+	if !lok || !rok {
+		if n.op == "+" && left != nil && right != nil {
+			return toString(left) + toString(right), nil
+		}
+		return nil, nil
+	}
+
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	default:
+		if r == 0 {
+			return nil, nil
+		}
+		return l / r, nil
+	}
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(env Env) (interface{}, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil || right == nil {
+		// Unknown values never satisfy a comparison, except != against a
+		// known value
+		return n.op == "!=" && (left != nil || right != nil), nil
+	}
+
+	var cmp int
+	if l, ok := toNumber(left); ok {
+		if r, ok := toNumber(right); ok {
+			switch {
+			case l < r:
+				cmp = -1
+			case l > r:
+				cmp = 1
+			}
+			return compareResult(n.op, cmp), nil
+		}
+	}
+
+	if l, ok := left.(bool); ok {
+		if r, ok := right.(bool); ok {
+			if n.op != "==" && n.op != "!=" {
+				return nil, fmt.Errorf("operator %s is not defined for booleans", n.op)
+			}
+			return (l == r) == (n.op == "=="), nil
+		}
+	}
+
+	cmp = strings.Compare(toString(left), toString(right))
+	return compareResult(n.op, cmp), nil
+}
+
+func compareResult(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// Value helpers
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		b, err := strconv.ParseBool(v)
+		return err == nil && b
+	default:
+		return true
+	}
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case bool:
+		return 0, false
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
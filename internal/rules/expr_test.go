@@ -0,0 +1,187 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+// testEnv resolves identifiers from fixed current and previous values
+type testEnv struct {
+	values map[string]interface{}
+	prev   map[string]interface{}
+}
+
+func (env *testEnv) Lookup(name string) (interface{}, bool) {
+	value, ok := env.values[name]
+	return value, ok
+}
+
+func (env *testEnv) Previous(name string) (interface{}, bool) {
+	value, ok := env.prev[name]
+	return value, ok
+}
+
+func newTestEnv() *testEnv {
+	return &testEnv{
+		values: map[string]interface{}{
+			"uptime":                   120.0,
+			"online":                   true,
+			"model":                    "HG8245",
+			"Device.WiFi.SSID.1.SSID":  "guest-net",
+			"Device.Optical.RxPower":   "-27.5",
+			"Device.DeviceInfo.Uptime": int64(86400),
+		},
+		prev: map[string]interface{}{
+			"uptime": 90000.0,
+		},
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		// Operator precedence
+		{"multiplication before addition", "1 + 2 * 3 == 7", true},
+		{"parentheses", "(1 + 2) * 3 == 9", true},
+		{"subtraction is left associative", "10 - 4 - 3 == 3", true},
+		{"division is left associative", "8 / 4 / 2 == 1", true},
+		{"and before or", "true || false && false", true},
+		{"parenthesised or", "(true || false) && false", false},
+		{"not before and", "!false && false", false},
+		{"not of a group", "!(false && false)", true},
+		{"comparison before equality", "1 < 2 == 3 < 4", true},
+		{"arithmetic before comparison", "uptime + 60 > 170", true},
+		{"unary minus", "-2 * 3 == -6", true},
+		{"double negation", "!!online", true},
+		{"reboot", "uptime < 300 && prev(uptime) > uptime", true},
+
+		// Types
+		{"numeric string", "Device.Optical.RxPower < -25", true},
+		{"integer parameter", "Device.DeviceInfo.Uptime == 86400", true},
+		{"string equality", "model == 'HG8245'", true},
+		{"strings compare lexically", "model < \"HG9\"", true},
+		{"string concatenation", "\"v\" + 1 == \"v1\"", true},
+		{"arithmetic on a string", "model * 2 == 0", false},
+		{"division by zero", "1 / 0 == 0", false},
+		{"num of a string", "num(model) == 0", false},
+		{"num of a numeric string", "num(Device.Optical.RxPower) < 0", true},
+		{"contains", "contains(Device.WiFi.SSID.1.SSID, 'guest')", true},
+		{"contains a number", "contains(model, 8245)", true},
+		{"boolean equality", "online == true", true},
+		{"truthy string", "'true'", true},
+		{"truthy number", "uptime", true},
+		{"escaped quote", `'it\'s' == "it's"`, true},
+
+		// Missing parameters
+		{"missing greater", "Device.Missing > 0", false},
+		{"missing less", "Device.Missing < 0", false},
+		{"missing equal", "Device.Missing == 0", false},
+		{"missing unequal to a value", "Device.Missing != 0", true},
+		{"missing unequal to missing", "Device.Missing != Device.Other", false},
+		{"missing negated", "-Device.Missing < 0", false},
+		{"missing in arithmetic", "Device.Missing + 1 > 0", false},
+		{"missing is false", "Device.Missing", false},
+		{"not missing", "!Device.Missing", true},
+		{"exists", "exists(uptime) && !exists(Device.Missing)", true},
+		{"missing previous", "prev(Device.Missing) == 0", false},
+		{"contains missing", "contains(Device.Missing, 'x')", false},
+	}
+
+	env := newTestEnv()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Compile(tt.expr)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.expr, err)
+			}
+			got, err := expr.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalTypeErrors(t *testing.T) {
+	tests := []string{
+		"true < false",
+		"online >= false",
+		"uptime > 0 && online <= true",
+	}
+
+	env := newTestEnv()
+	for _, source := range tests {
+		expr, err := Compile(source)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", source, err)
+		}
+		if _, err := expr.Eval(env); err == nil || !strings.Contains(err.Error(), "not defined for booleans") {
+			t.Errorf("Eval(%q) = %v, want an ordering error", source, err)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"", "unexpected end"},
+		{"(", "unexpected end"},
+		{")", "unexpected \")\""},
+		{"uptime <", "unexpected end"},
+		{"uptime < < 3", "unexpected \"<\""},
+		{"(uptime > 3", "missing closing parenthesis"},
+		{"uptime > 3)", "unexpected \")\""},
+		{"uptime 3", "unexpected \"3\""},
+		{"a, b", "unexpected \",\""},
+		{"'open", "unterminated string"},
+		{"uptime # 3", "unexpected character"},
+		{"1.2.3 > 0", "invalid number"},
+		{"prev(1)", "expects a parameter path"},
+		{"prev()", "expects one parameter path"},
+		{"exists(a, b)", "expects one parameter path"},
+		{"num()", "expects one argument"},
+		{"contains(a)", "expects two arguments"},
+		{"lower(model)", "unknown function"},
+		{"prev(uptime", "missing closing parenthesis"},
+		{"!", "unexpected end"},
+		{"&&", "unexpected \"&&\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Compile(%q) = %v, want an error with %q", tt.expr, err, tt.err)
+			}
+		})
+	}
+}
+
+// TestCompileTruncated compiles and evaluates every prefix of valid
+// expressions, malformed input must fail without panicking
+func TestCompileTruncated(t *testing.T) {
+	sources := []string{
+		"uptime < 300 && prev(uptime) > uptime",
+		"!(contains(Device.WiFi.SSID.1.SSID, 'gu\\'est') || num(model) >= -1.5 * (2 + 3))",
+		"exists(Device.Optical.RxPower) && Device.Optical.RxPower != \"\" / 0",
+	}
+
+	env := newTestEnv()
+	for _, source := range sources {
+		for i := 0; i <= len(source); i++ {
+			expr, err := Compile(source[:i])
+			if err != nil {
+				continue
+			}
+			expr.Eval(env)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package sbi
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Authenticator verifies the API keys and JWTs presented by NBI clients
+type Authenticator struct {
+	config     *config.NBIAuth
+	appContext *context.Context
+
+	// staticKeys are the configured keys by hash
+	staticKeys map[string]*models.APIKey
+
+	parser *jwt.Parser
+	jwks   *jwksFile
+}
+
+// NewAuthenticator creates an authenticator for the NBI. It fails when the
+// JWKS file cannot be loaded.
+func NewAuthenticator(cfg *config.NBIAuth, appContext *context.Context) (*Authenticator, error) {
+	a := &Authenticator{
+		config:     cfg,
+		appContext: appContext,
+		staticKeys: make(map[string]*models.APIKey),
+	}
+	if !cfg.Enabled {
+		return a, nil
+	}
+
+	for _, key := range cfg.APIKeys {
+		a.staticKeys[strings.ToLower(key.Hash)] = &models.APIKey{
+			Name:   key.Name,
+			Role:   key.Role,
+			Tenant: key.Tenant,
+		}
+	}
+
+	if jwtConfig := cfg.JWT; jwtConfig != nil && jwtConfig.Enabled {
+		options := []jwt.ParserOption{
+			jwt.WithValidMethods(jwtConfig.Algorithms),
+			jwt.WithLeeway(jwtConfig.Leeway),
+			jwt.WithExpirationRequired(),
+		}
+		if jwtConfig.Issuer != "" {
+			options = append(options, jwt.WithIssuer(jwtConfig.Issuer))
+		}
+		if jwtConfig.Audience != "" {
+			options = append(options, jwt.WithAudience(jwtConfig.Audience))
+		}
+		a.parser = jwt.NewParser(options...)
+
+		if jwtConfig.JWKSFile != "" {
+			a.jwks = &jwksFile{path: jwtConfig.JWKSFile}
+			if err := a.jwks.load(); err != nil {
+				return nil, fmt.Errorf("failed to load JWKS %s: %w", jwtConfig.JWKSFile, err)
+			}
+		}
+	}
+
+	return a, nil
+}
+
+// Enabled reports whether requests must authenticate
+func (a *Authenticator) Enabled() bool {
+	return a.config.Enabled
+}
+
+// Anonymous returns the principal of requests while authentication is
+// disabled, it may do everything
+func (a *Authenticator) Anonymous() *models.Principal {
+	return a.appContext.NewPrincipal("", models.AuthMethodAnonymous, []string{models.RoleAdmin})
+}
+
+// Authenticate returns the principal for an API key or JWT
+func (a *Authenticator) Authenticate(token string) (*models.Principal, error) {
+	if strings.HasPrefix(token, models.APIKeyPrefix) {
+		return a.authenticateAPIKey(token)
+	}
+	if a.parser != nil && strings.Count(token, ".") == 2 {
+		return a.authenticateJWT(token)
+	}
+	return nil, models.ErrAuthenticationFailed
+}
+
+// authenticateAPIKey checks the configured keys, then the managed ones
+func (a *Authenticator) authenticateAPIKey(token string) (*models.Principal, error) {
+	if key, exists := a.staticKeys[context.HashAPIKey(token)]; exists {
+		principal := a.appContext.NewPrincipal(key.Name, models.AuthMethodAPIKey, []string{key.Role})
+		principal.Tenant = key.Tenant
+		return principal, nil
+	}
+
+	key, err := a.appContext.AuthenticateAPIKey(token)
+	if err != nil {
+		return nil, err
+	}
+	principal := a.appContext.NewPrincipal(key.Name, models.AuthMethodAPIKey, []string{key.Role})
+	principal.KeyID = key.ID
+	principal.Tenant = key.Tenant
+	principal.ExpiresAt = key.ExpiresAt
+	return principal, nil
+}
+
+// authenticateJWT validates the token and maps its claims to a principal
+func (a *Authenticator) authenticateJWT(raw string) (*models.Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(raw, claims, a.verificationKey); err != nil {
+		return nil, err
+	}
+
+	name, _ := claims[a.config.JWT.NameClaim].(string)
+	if name == "" {
+		return nil, fmt.Errorf("token has no %s claim", a.config.JWT.NameClaim)
+	}
+
+	roles := a.jwtRoles(claims[a.config.JWT.RolesClaim])
+	if len(roles) == 0 {
+		roles = []string{a.config.JWT.DefaultRole}
+	}
+
+	principal := a.appContext.NewPrincipal(name, models.AuthMethodJWT, roles)
+	if a.config.JWT.TenantClaim != "" {
+		tenant, _ := claims[a.config.JWT.TenantClaim].(string)
+		if tenant != "" && !a.appContext.IsValidTenant(tenant) {
+			return nil, fmt.Errorf("token names unknown tenant %s", tenant)
+		}
+		principal.Tenant = tenant
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		principal.ExpiresAt = &exp.Time
+	}
+	return principal, nil
+}
+
+// verificationKey returns the key for the algorithm of the token, the
+// parser has already checked the algorithm is allowed
+func (a *Authenticator) verificationKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return []byte(a.config.JWT.Secret), nil
+	case jwt.SigningMethodRS256.Alg():
+		if a.jwks == nil {
+			return nil, errors.New("no JWKS configured")
+		}
+		kid, _ := token.Header["kid"].(string)
+		return a.jwks.key(kid)
+	}
+	return nil, fmt.Errorf("unsupported signing method %s", token.Method.Alg())
+}
+
+// jwtRoles reads the known roles from a string or list claim. Roles the
+// gateway does not know, such as those of other applications, are ignored.
+func (a *Authenticator) jwtRoles(claim interface{}) []string {
+	var values []string
+	switch claim := claim.(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []interface{}:
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var roles []string
+	for _, value := range values {
+		if a.appContext.IsValidRole(value) {
+			roles = append(roles, value)
+		}
+	}
+	return roles
+}
+
+// jwksFile holds the RSA keys of a JSON Web Key Set file. The file is read
+// again when a token names an unknown key and the file has changed, so keys
+// can be rotated without a restart.
+type jwksFile struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	keys    map[string]*rsa.PublicKey
+}
+
+// jsonWebKey is the part of a JWK needed for RSA signature keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// key returns the key with the ID, or the only key when the token names none
+func (j *jwksFile) key(kid string) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if key := j.lookup(kid); key != nil {
+		return key, nil
+	}
+
+	info, err := os.Stat(j.path)
+	if err == nil && !info.ModTime().Equal(j.modTime) {
+		if err := j.loadLocked(); err != nil {
+			logger.SBILog.Warnf("Failed to reload JWKS %s: %v", j.path, err)
+		}
+		if key := j.lookup(kid); key != nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key ID %q", kid)
+}
+
+// lookup finds a key in the loaded set. Must be called with mu held.
+func (j *jwksFile) lookup(kid string) *rsa.PublicKey {
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key
+		}
+	}
+	return j.keys[kid]
+}
+
+// load reads the key set
+func (j *jwksFile) load() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.loadLocked()
+}
+
+// loadLocked reads the key set. Must be called with mu held.
+func (j *jwksFile) loadLocked() error {
+	info, err := os.Stat(j.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return fmt.Errorf("key %s: invalid modulus", jwk.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return fmt.Errorf("key %s: invalid exponent", jwk.Kid)
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return errors.New("no RSA signature keys")
+	}
+
+	j.keys = keys
+	j.modTime = info.ModTime()
+	logger.SBILog.Infof("Loaded %d keys from JWKS %s", len(keys), j.path)
+	return nil
+}
+
+// AuthMiddleware authenticates requests by API key or JWT, given as bearer
+// token or in the X-API-Key header. Streaming clients that cannot set
+// headers, such as browsers opening a WebSocket or EventSource, may pass
+// the token as access_token query parameter. Routes check the permissions
+// of the principal with auth.RequirePermission.
+func AuthMiddleware(authenticator *Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authenticator.Enabled() {
+			auth.SetPrincipal(c, authenticator.Anonymous())
+			c.Next()
+			return
+		}
+
+		token := requestToken(c)
+		if token == "" {
+			abortUnauthorized(c, "Missing credentials")
+			return
+		}
+
+		principal, err := authenticator.Authenticate(token)
+		if err != nil {
+			logger.SBILog.Warnf("Rejected credentials from %s for %s %s: %v", c.ClientIP(), c.Request.Method, c.Request.URL.Path, err)
+			if errors.Is(err, models.ErrAPIKeyExpired) {
+				abortUnauthorized(c, "API key expired")
+			} else {
+				abortUnauthorized(c, "Invalid credentials")
+			}
+			return
+		}
+
+		auth.SetPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// requestToken extracts the credentials of a request
+func requestToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") {
+			return ""
+		}
+		return strings.TrimSpace(token)
+	}
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if c.IsWebsocket() || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		return c.Query("access_token")
+	}
+	return ""
+}
+
+// abortUnauthorized rejects a request without valid credentials
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="nextranet-gateway"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"error": message,
+	})
+}
@@ -0,0 +1,90 @@
+package producer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+)
+
+// GetAuditRecords returns audit records, newest first. With format=csv all
+// matching records are returned as a CSV download instead of a page.
+func GetAuditRecords(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := audit.ParseFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		records := appContext.GetAuditRecords(filter)
+
+		if c.Query("format") == "csv" {
+			// Set headers for CSV download
+			c.Header("Content-Type", "text/csv")
+			c.Header("Content-Disposition", "attachment; filename=audit.csv")
+
+			if err := audit.WriteCSV(c.Writer, records); err != nil {
+				logger.ProducerLog.Errorf("Failed to export audit records: %v", err)
+			}
+			return
+		}
+
+		// Apply pagination
+		page := 1
+		pageSize := 20
+
+		if p := c.Query("page"); p != "" {
+			if val, err := strconv.Atoi(p); err == nil && val > 0 {
+				page = val
+			}
+		}
+
+		if ps := c.Query("pageSize"); ps != "" {
+			if val, err := strconv.Atoi(ps); err == nil && val > 0 && val <= 100 {
+				pageSize = val
+			}
+		}
+
+		start := (page - 1) * pageSize
+		end := start + pageSize
+
+		if start > len(records) {
+			start = len(records)
+		}
+		if end > len(records) {
+			end = len(records)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"records":  records[start:end],
+			"total":    len(records),
+			"page":     page,
+			"pageSize": pageSize,
+		})
+	}
+}
+
+// VerifyAuditLog checks the hash chain of the audit log
+func VerifyAuditLog(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verification, err := appContext.VerifyAuditLog()
+		if err != nil {
+			logger.ProducerLog.Errorf("Failed to verify audit log: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to read audit log",
+			})
+			return
+		}
+
+		if !verification.Valid {
+			logger.ProducerLog.Warnf("Audit log is broken at record %d: %s", verification.BrokenAt, verification.Error)
+		}
+		c.JSON(http.StatusOK, verification)
+	}
+}
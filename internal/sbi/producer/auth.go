@@ -0,0 +1,166 @@
+package producer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// apiKeyRequest is the request body for creating an API key
+type apiKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Role      string     `json:"role" binding:"required"`
+	Tenant    string     `json:"tenant,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// GetCurrentPrincipal returns the caller with its roles and permissions
+func GetCurrentPrincipal(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := auth.GetPrincipal(c)
+
+		c.JSON(http.StatusOK, gin.H{
+			"principal": principal,
+		})
+	}
+}
+
+// GetRoles returns the configured roles with their permissions
+func GetRoles(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles := make(map[string][]string)
+		for _, role := range appContext.GetRoles() {
+			roles[role] = appContext.RolePermissions([]string{role})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"roles": roles,
+		})
+	}
+}
+
+// GetAPIKeys returns the managed API keys visible to the caller without
+// their hashes. Keys from the configuration file are not listed.
+func GetAPIKeys(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keys := appContext.GetAPIKeys()
+
+		redacted := make([]*models.APIKey, 0, len(keys))
+		for _, key := range keys {
+			if apiKeyVisible(c, key) {
+				redacted = append(redacted, key.Redacted())
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"keys":  redacted,
+			"total": len(redacted),
+		})
+	}
+}
+
+// GetAPIKey returns a single API key without its hash
+func GetAPIKey(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, exists := appContext.GetAPIKey(c.Param("keyId"))
+		if !exists || !apiKeyVisible(c, key) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "API key not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"key": key.Redacted(),
+		})
+	}
+}
+
+// CreateAPIKey creates an API key. The response is the only one that
+// includes the key.
+func CreateAPIKey(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req apiKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		// Keys created by a tenant admin belong to the same tenant
+		if req.Tenant == "" {
+			req.Tenant = auth.Tenant(c)
+		}
+		if !auth.CanAssignTenant(c, req.Tenant) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Cannot create API keys of another tenant",
+			})
+			return
+		}
+
+		key := &models.APIKey{
+			Name:      req.Name,
+			Role:      req.Role,
+			Tenant:    req.Tenant,
+			ExpiresAt: req.ExpiresAt,
+			CreatedBy: auth.ActorName(c, ""),
+		}
+		token, err := appContext.AddAPIKey(key)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		audit.AddTargets(c, key.ID)
+		logger.ProducerLog.Infof("Created API key %s (%s) with role %s", key.ID, key.Name, key.Role)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "API key created successfully, store it now as it cannot be shown again",
+			"key":     key.Redacted(),
+			"apiKey":  token,
+		})
+	}
+}
+
+// DeleteAPIKey revokes an API key. Requests already authenticated with it
+// complete.
+func DeleteAPIKey(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID := c.Param("keyId")
+
+		if key, exists := appContext.GetAPIKey(keyID); !exists || !apiKeyVisible(c, key) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "API key not found",
+			})
+			return
+		}
+		if err := appContext.RemoveAPIKey(keyID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "API key not found",
+			})
+			return
+		}
+
+		logger.ProducerLog.Infof("Revoked API key %s", keyID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "API key revoked successfully",
+		})
+	}
+}
+
+// apiKeyVisible reports whether the caller may see and revoke a key. Callers
+// limited to a tenant only see the keys of that tenant.
+func apiKeyVisible(c *gin.Context, key *models.APIKey) bool {
+	tenant := auth.Tenant(c)
+	return tenant == "" || key.Tenant == tenant
+}
@@ -4,8 +4,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
 	"github.com/nextranet/gateway/c-plane/internal/models"
@@ -67,14 +70,39 @@ func GetDevices(appContext *context.Context) gin.HandlerFunc {
 			filter.Search = search
 		}
 
-		// Parse IP range
-		if startIP := c.Query("startIP"); startIP != "" {
-			if endIP := c.Query("endIP"); endIP != "" {
-				filter.IPRange = &models.IPRange{
-					StartIP: startIP,
-					EndIP:   endIP,
-				}
+		filter.Tenant = auth.RequestTenant(c)
+
+		// Parse IP range, CIDR prefixes and negation
+		negate, _ := strconv.ParseBool(c.Query("ipNegate"))
+		ipRange, err := models.ParseIPRange(c.Query("startIP"), c.Query("endIP"), c.Query("cidr"), c.Query("ipField"), negate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		filter.IPRange = ipRange
+
+		// Searches run against the inventory index, ranked by relevance
+		if filter.Search != "" {
+			matches := appContext.GetFilteredDevices(filter)
+
+			start := (filter.Pagination.Page - 1) * filter.Pagination.PageSize
+			end := start + filter.Pagination.PageSize
+			if start > len(matches) {
+				start = len(matches)
 			}
+			if end > len(matches) {
+				end = len(matches)
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"devices":  matches[start:end],
+				"total":    len(matches),
+				"page":     filter.Pagination.Page,
+				"pageSize": filter.Pagination.PageSize,
+			})
+			return
 		}
 
 		// Get devices from GenieACS
@@ -242,6 +270,8 @@ func SetDeviceParameters(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		audit.RecordParameterChanges(c, appContext, deviceID, req.Parameters)
+
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
@@ -291,6 +321,14 @@ func GetDeviceTasks(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
+// taskPermissions are the permissions GenieACS tasks need beyond managing
+// the device
+var taskPermissions = map[string]string{
+	"reboot":       models.PermDeviceReboot,
+	"factoryReset": models.PermDeviceFactoryReset,
+	"download":     models.PermFirmwareManage,
+}
+
 // CreateDeviceTask creates a new task for a device
 func CreateDeviceTask(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -310,6 +348,17 @@ func CreateDeviceTask(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		// Generic tasks must not bypass the permissions of dedicated routes
+		name, _ := task["name"].(string)
+		if permission, ok := taskPermissions[name]; ok && !auth.Can(c, permission) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Permission denied, " + permission + " required",
+			})
+			return
+		}
+
+		audit.RecordChange(c, deviceID, "task", nil, name)
+
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
@@ -364,6 +413,77 @@ func GetDeviceFaults(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
+// GetDeviceHistory returns the status history of a device with its
+// availability over the requested ranges
+func GetDeviceHistory(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deviceID := c.Param("deviceId")
+		if deviceID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Device ID is required",
+			})
+			return
+		}
+
+		if _, exists := appContext.GetDevice(deviceID); !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Device not found",
+			})
+			return
+		}
+
+		ranges := models.DefaultAvailabilityRanges
+		if r := c.Query("ranges"); r != "" {
+			ranges = strings.Split(r, ",")
+		}
+
+		now := time.Now()
+		availability := make([]models.Availability, 0, len(ranges))
+		for _, r := range ranges {
+			d, err := models.ParseHistoryRange(strings.TrimSpace(r))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+			a := appContext.GetDeviceAvailability(deviceID, now.Add(-d), now)
+			a.Range = strings.TrimSpace(r)
+			availability = append(availability, a)
+		}
+
+		var since time.Time
+		if s := c.Query("since"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid since timestamp, expected RFC 3339",
+				})
+				return
+			}
+			since = t
+		}
+
+		events := appContext.GetDeviceHistory(deviceID, since)
+		if eventType := c.Query("type"); eventType != "" {
+			filtered := make([]*models.DeviceEvent, 0, len(events))
+			for _, event := range events {
+				if event.Type == eventType {
+					filtered = append(filtered, event)
+				}
+			}
+			events = filtered
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"deviceId":     deviceID,
+			"events":       events,
+			"total":        len(events),
+			"availability": availability,
+		})
+	}
+}
+
 // RebootDevice reboots a device
 func RebootDevice(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,151 @@
+package producer
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// escalationPolicyRequest is the request body for creating or updating an
+// escalation policy
+type escalationPolicyRequest struct {
+	Name       string                   `json:"name" binding:"required"`
+	Enabled    *bool                    `json:"enabled,omitempty"`
+	Severities []string                 `json:"severities,omitempty"`
+	Tags       []string                 `json:"tags,omitempty"`
+	Levels     []models.EscalationLevel `json:"levels" binding:"required"`
+}
+
+// toPolicy converts the request into an escalation policy
+func (r *escalationPolicyRequest) toPolicy(id string) *models.EscalationPolicy {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &models.EscalationPolicy{
+		ID:         id,
+		Name:       r.Name,
+		Enabled:    enabled,
+		Severities: r.Severities,
+		Tags:       r.Tags,
+		Levels:     r.Levels,
+	}
+}
+
+// GetEscalationPolicies returns all escalation policies
+func GetEscalationPolicies(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies := appContext.GetEscalationPolicies()
+
+		c.JSON(http.StatusOK, gin.H{
+			"policies": policies,
+			"total":    len(policies),
+		})
+	}
+}
+
+// GetEscalationPolicy returns a single escalation policy
+func GetEscalationPolicy(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policyID := c.Param("policyId")
+
+		policy, exists := appContext.GetEscalationPolicy(policyID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Escalation policy not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// CreateEscalationPolicy creates a new escalation policy
+func CreateEscalationPolicy(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req escalationPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		policy := req.toPolicy("")
+		if err := appContext.AddEscalationPolicy(policy); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		audit.AddTargets(c, policy.ID)
+		logger.ProducerLog.Infof("Created escalation policy %s (%s)", policy.ID, policy.Name)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Escalation policy created successfully",
+			"policy":  policy,
+		})
+	}
+}
+
+// UpdateEscalationPolicy replaces an existing escalation policy
+func UpdateEscalationPolicy(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policyID := c.Param("policyId")
+
+		var req escalationPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		policy := req.toPolicy(policyID)
+		if err := appContext.UpdateEscalationPolicy(policy); err != nil {
+			if err == models.ErrEscalationPolicyNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Escalation policy not found",
+				})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Escalation policy updated successfully",
+			"policy":  policy,
+		})
+	}
+}
+
+// DeleteEscalationPolicy removes an escalation policy. Faults keep the
+// escalation level they already reached.
+func DeleteEscalationPolicy(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policyID := c.Param("policyId")
+
+		if err := appContext.RemoveEscalationPolicy(policyID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Escalation policy not found",
+			})
+			return
+		}
+
+		logger.ProducerLog.Infof("Deleted escalation policy %s", policyID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Escalation policy deleted successfully",
+		})
+	}
+}
@@ -3,9 +3,11 @@ package producer
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
 	"github.com/nextranet/gateway/c-plane/internal/models"
@@ -21,23 +23,28 @@ func GetFaults(appContext *context.Context) gin.HandlerFunc {
 		status := c.Query("status")
 		severity := c.Query("severity")
 		channel := c.Query("channel")
+		incidentID := c.Query("incidentId")
+		suppressed := c.Query("suppressed")
+		assignee := c.Query("assignee")
 
 		// Get all faults from GenieACS
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
-		faults, err := genieService.GetFaults("")
+		genieFaults, err := genieService.GetFaults("")
 		if err != nil {
 			logger.ProducerLog.Errorf("Failed to get faults from GenieACS: %v", err)
-			// Fall back to context data
-			faults = appContext.GetActiveFaults()
 		}
 
 		// Add faults to context
-		for _, fault := range faults {
+		for _, fault := range genieFaults {
 			appContext.AddFault(fault)
 		}
 
+		// Serve from context, which also holds faults raised by gateway
+		// alarm rules and falls back to known faults if GenieACS is down
+		faults := appContext.FilterTenantFaults(auth.RequestTenant(c), appContext.GetAllFaults())
+
 		// Apply filters
 		filteredFaults := make([]*models.Fault, 0)
 
@@ -62,6 +69,21 @@ func GetFaults(appContext *context.Context) gin.HandlerFunc {
 				continue
 			}
 
+			// Filter by incident
+			if incidentID != "" && fault.IncidentID != incidentID {
+				continue
+			}
+
+			// Filter by maintenance suppression
+			if suppressed != "" && fault.Suppressed != (suppressed == "true") {
+				continue
+			}
+
+			// Filter by assignee
+			if assignee != "" && !strings.EqualFold(fault.Assignee, assignee) {
+				continue
+			}
+
 			filteredFaults = append(filteredFaults, fault)
 		}
 
@@ -182,7 +204,7 @@ func AcknowledgeFault(appContext *context.Context) gin.HandlerFunc {
 		}
 
 		var req struct {
-			AcknowledgedBy string `json:"acknowledgedBy" binding:"required"`
+			AcknowledgedBy string `json:"acknowledgedBy,omitempty"`
 			Notes          string `json:"notes,omitempty"`
 		}
 
@@ -193,6 +215,15 @@ func AcknowledgeFault(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		// The authenticated caller takes precedence over the name in the body
+		req.AcknowledgedBy = auth.ActorName(c, req.AcknowledgedBy)
+		if req.AcknowledgedBy == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "acknowledgedBy is required",
+			})
+			return
+		}
+
 		// Check if fault exists
 		fault, exists := appContext.GetFault(faultID)
 		if !exists {
@@ -227,6 +258,12 @@ func AcknowledgeFault(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		if req.Notes != "" {
+			if _, err := appContext.AddFaultNote(faultID, req.AcknowledgedBy, req.Notes, ""); err != nil {
+				logger.ProducerLog.Warnf("Failed to add acknowledgement note to fault %s: %v", faultID, err)
+			}
+		}
+
 		// Get updated fault
 		fault, _ = appContext.GetFault(faultID)
 
@@ -249,7 +286,8 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 		}
 
 		var req struct {
-			ResolvedBy string `json:"resolvedBy" binding:"required"`
+			ResolvedBy string `json:"resolvedBy,omitempty"`
+			Category   string `json:"category,omitempty"`
 			Resolution string `json:"resolution,omitempty"`
 			Notes      string `json:"notes,omitempty"`
 		}
@@ -261,6 +299,22 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		// The authenticated caller takes precedence over the name in the body
+		req.ResolvedBy = auth.ActorName(c, req.ResolvedBy)
+		if req.ResolvedBy == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "resolvedBy is required",
+			})
+			return
+		}
+
+		if !models.IsValidResolutionCategory(req.Category) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid resolution category",
+			})
+			return
+		}
+
 		// Check if fault exists
 		fault, exists := appContext.GetFault(faultID)
 		if !exists {
@@ -279,7 +333,7 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 		}
 
 		// Resolve the fault
-		err := appContext.ResolveFault(faultID, req.ResolvedBy)
+		err := appContext.ResolveFault(faultID, req.ResolvedBy, req.Category, req.Resolution)
 		if err != nil {
 			logger.ProducerLog.Errorf("Failed to resolve fault: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -288,6 +342,12 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		if req.Notes != "" {
+			if _, err := appContext.AddFaultNote(faultID, req.ResolvedBy, req.Notes, ""); err != nil {
+				logger.ProducerLog.Warnf("Failed to add resolution note to fault %s: %v", faultID, err)
+			}
+		}
+
 		// Delete fault from GenieACS
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
@@ -307,6 +367,152 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
+// AssignFault sets or clears the owner of a fault
+func AssignFault(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		faultID := c.Param("faultId")
+		if faultID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Fault ID is required",
+			})
+			return
+		}
+
+		var req struct {
+			Assignee   string `json:"assignee"`
+			AssignedBy string `json:"assignedBy,omitempty"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		if err := appContext.AssignFault(faultID, req.Assignee, auth.ActorName(c, req.AssignedBy)); err != nil {
+			if err == models.ErrFaultNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Fault not found",
+				})
+				return
+			}
+			logger.ProducerLog.Errorf("Failed to assign fault: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to assign fault",
+			})
+			return
+		}
+
+		fault, _ := appContext.GetFault(faultID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Fault assigned successfully",
+			"fault":   fault,
+		})
+	}
+}
+
+// GetFaultNotes returns the note thread of a fault
+func GetFaultNotes(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		faultID := c.Param("faultId")
+
+		notes, err := appContext.GetFaultNotes(faultID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Fault not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"notes": notes,
+			"total": len(notes),
+		})
+	}
+}
+
+// AddFaultNote adds a note to the thread of a fault
+func AddFaultNote(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		faultID := c.Param("faultId")
+		if faultID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Fault ID is required",
+			})
+			return
+		}
+
+		var req struct {
+			Author  string `json:"author,omitempty"`
+			Text    string `json:"text" binding:"required"`
+			ReplyTo string `json:"replyTo,omitempty"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		// The authenticated caller takes precedence over the name in the body
+		req.Author = auth.ActorName(c, req.Author)
+		if req.Author == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "author is required",
+			})
+			return
+		}
+
+		note, err := appContext.AddFaultNote(faultID, req.Author, req.Text, req.ReplyTo)
+		if err != nil {
+			switch err {
+			case models.ErrFaultNotFound:
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Fault not found",
+				})
+			case models.ErrFaultNoteNotFound:
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Note to reply to not found",
+				})
+			default:
+				logger.ProducerLog.Errorf("Failed to add fault note: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to add note",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Note added successfully",
+			"note":    note,
+		})
+	}
+}
+
+// GetMyFaults returns the open faults assigned to the caller
+func GetMyFaults(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		assignee := auth.ActorName(c, c.Query("assignee"))
+		if assignee == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Assignee is required",
+			})
+			return
+		}
+
+		faults := appContext.FilterTenantFaults(auth.Tenant(c), appContext.GetAssignedFaults(assignee))
+
+		c.JSON(http.StatusOK, gin.H{
+			"faults": faults,
+			"total":  len(faults),
+		})
+	}
+}
+
 // DeleteFault deletes a fault
 func DeleteFault(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -366,7 +572,7 @@ func DeleteFault(appContext *context.Context) gin.HandlerFunc {
 // GetFaultStats returns fault statistics
 func GetFaultStats(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		faults := appContext.GetActiveFaults()
+		faults := appContext.FilterTenantFaults(auth.RequestTenant(c), appContext.GetActiveFaults())
 
 		// Calculate statistics
 		stats := struct {
@@ -0,0 +1,258 @@
+package producer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/pkg/factory"
+	"github.com/nextranet/gateway/c-plane/pkg/service"
+)
+
+// GetIncidents returns a list of correlated fault incidents
+func GetIncidents(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := &models.IncidentFilter{
+			Status:      c.Query("status"),
+			Severity:    c.Query("severity"),
+			Code:        c.Query("code"),
+			Channel:     c.Query("channel"),
+			DeviceModel: c.Query("deviceModel"),
+			MinFaults:   appContext.GetCorrelationConfig().MinFaults,
+		}
+
+		if minFaults := c.Query("minFaults"); minFaults != "" {
+			if val, err := strconv.Atoi(minFaults); err == nil && val >= 0 {
+				filter.MinFaults = val
+			}
+		}
+
+		incidents := appContext.FilterTenantIncidents(auth.RequestTenant(c), appContext.GetIncidents(filter))
+
+		// Apply pagination
+		page := 1
+		pageSize := 20
+
+		if p := c.Query("page"); p != "" {
+			if val, err := strconv.Atoi(p); err == nil && val > 0 {
+				page = val
+			}
+		}
+
+		if ps := c.Query("pageSize"); ps != "" {
+			if val, err := strconv.Atoi(ps); err == nil && val > 0 && val <= 100 {
+				pageSize = val
+			}
+		}
+
+		start := (page - 1) * pageSize
+		end := start + pageSize
+
+		if start > len(incidents) {
+			start = len(incidents)
+		}
+		if end > len(incidents) {
+			end = len(incidents)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"incidents": incidents[start:end],
+			"total":     len(incidents),
+			"page":      page,
+			"pageSize":  pageSize,
+		})
+	}
+}
+
+// GetIncident returns a single incident with its member faults
+func GetIncident(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		incidentID := c.Param("incidentId")
+		if incidentID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Incident ID is required",
+			})
+			return
+		}
+
+		tenant := auth.Tenant(c)
+		incident, exists := tenantIncident(appContext, tenant, incidentID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Incident not found",
+			})
+			return
+		}
+
+		faults, _ := appContext.GetIncidentFaults(incidentID)
+		faults = appContext.FilterTenantFaults(tenant, faults)
+
+		c.JSON(http.StatusOK, gin.H{
+			"incident": incident,
+			"faults":   faults,
+		})
+	}
+}
+
+// AcknowledgeIncident acknowledges an incident and all of its member faults
+func AcknowledgeIncident(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		incidentID := c.Param("incidentId")
+		if incidentID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Incident ID is required",
+			})
+			return
+		}
+
+		var req struct {
+			AcknowledgedBy string `json:"acknowledgedBy,omitempty"`
+			Notes          string `json:"notes,omitempty"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		// The authenticated caller takes precedence over the name in the body
+		req.AcknowledgedBy = auth.ActorName(c, req.AcknowledgedBy)
+		if req.AcknowledgedBy == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "acknowledgedBy is required",
+			})
+			return
+		}
+
+		err := appContext.AcknowledgeIncident(auth.Tenant(c), incidentID, req.AcknowledgedBy)
+		if err != nil {
+			switch err {
+			case models.ErrIncidentNotFound:
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Incident not found",
+				})
+			case models.ErrFaultAlreadyResolved:
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Incident is already resolved",
+				})
+			default:
+				logger.ProducerLog.Errorf("Failed to acknowledge incident: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to acknowledge incident",
+				})
+			}
+			return
+		}
+
+		incident, _ := tenantIncident(appContext, auth.Tenant(c), incidentID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Incident acknowledged successfully",
+			"incident": incident,
+		})
+	}
+}
+
+// ResolveIncident resolves an incident and all of its member faults
+func ResolveIncident(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		incidentID := c.Param("incidentId")
+		if incidentID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Incident ID is required",
+			})
+			return
+		}
+
+		var req struct {
+			ResolvedBy string `json:"resolvedBy,omitempty"`
+			Category   string `json:"category,omitempty"`
+			Resolution string `json:"resolution,omitempty"`
+			Notes      string `json:"notes,omitempty"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		// The authenticated caller takes precedence over the name in the body
+		req.ResolvedBy = auth.ActorName(c, req.ResolvedBy)
+		if req.ResolvedBy == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "resolvedBy is required",
+			})
+			return
+		}
+
+		resolved, err := appContext.ResolveIncident(auth.Tenant(c), incidentID, req.ResolvedBy, req.Category, req.Resolution)
+		if err != nil {
+			switch err {
+			case models.ErrIncidentNotFound:
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Incident not found",
+				})
+			case models.ErrInvalidResolution:
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid resolution category",
+				})
+			case models.ErrFaultAlreadyResolved:
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Incident is already resolved",
+				})
+			default:
+				logger.ProducerLog.Errorf("Failed to resolve incident: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to resolve incident",
+				})
+			}
+			return
+		}
+
+		// Delete member faults from GenieACS
+		cfg := factory.GetConfig()
+		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
+
+		for _, faultID := range resolved {
+			if req.Notes != "" {
+				if _, err := appContext.AddFaultNote(faultID, req.ResolvedBy, req.Notes, ""); err != nil {
+					logger.ProducerLog.Warnf("Failed to add resolution note to fault %s: %v", faultID, err)
+				}
+			}
+			if err := genieService.DeleteFault(faultID); err != nil {
+				logger.ProducerLog.Warnf("Failed to delete fault %s from GenieACS: %v", faultID, err)
+				// Continue anyway as fault is marked as resolved
+			}
+		}
+
+		incident, _ := tenantIncident(appContext, auth.Tenant(c), incidentID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":        "Incident resolved successfully",
+			"incident":       incident,
+			"resolvedFaults": len(resolved),
+		})
+	}
+}
+
+// tenantIncident returns an incident as the tenant sees it, limited to its
+// devices and faults
+func tenantIncident(appContext *context.Context, tenant, incidentID string) (*models.Incident, bool) {
+	incident, exists := appContext.GetIncident(incidentID)
+	if !exists {
+		return nil, false
+	}
+	scoped := appContext.FilterTenantIncidents(tenant, []*models.Incident{incident})
+	if len(scoped) == 0 {
+		return nil, false
+	}
+	return scoped[0], true
+}
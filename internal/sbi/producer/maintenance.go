@@ -0,0 +1,165 @@
+package producer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// maintenanceWindowRequest is the request body for creating or updating a
+// maintenance window
+type maintenanceWindowRequest struct {
+	Name       string    `json:"name" binding:"required"`
+	Reason     string    `json:"reason"`
+	StartTime  time.Time `json:"startTime" binding:"required"`
+	EndTime    time.Time `json:"endTime" binding:"required"`
+	Recurrence string    `json:"recurrence,omitempty"`
+	DeviceIDs  []string  `json:"deviceIds,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Models     []string  `json:"models,omitempty"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+}
+
+// toWindow converts the request into a maintenance window
+func (r *maintenanceWindowRequest) toWindow(id string) *models.MaintenanceWindow {
+	return &models.MaintenanceWindow{
+		ID:         id,
+		Name:       r.Name,
+		Reason:     r.Reason,
+		StartTime:  r.StartTime,
+		EndTime:    r.EndTime,
+		Recurrence: r.Recurrence,
+		DeviceIDs:  r.DeviceIDs,
+		Tags:       r.Tags,
+		Models:     r.Models,
+		CreatedBy:  r.CreatedBy,
+	}
+}
+
+// GetMaintenanceWindows returns all maintenance windows
+func GetMaintenanceWindows(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var windows []*models.MaintenanceWindow
+		if c.Query("active") == "true" {
+			windows = appContext.GetActiveMaintenanceWindows(time.Now())
+		} else {
+			windows = appContext.GetMaintenanceWindows()
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"windows": windows,
+			"total":   len(windows),
+		})
+	}
+}
+
+// GetMaintenanceWindow returns a single maintenance window
+func GetMaintenanceWindow(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		windowID := c.Param("windowId")
+
+		window, exists := appContext.GetMaintenanceWindow(windowID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Maintenance window not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"window": window,
+			"active": window.IsActiveAt(time.Now()),
+		})
+	}
+}
+
+// CreateMaintenanceWindow creates a new maintenance window
+func CreateMaintenanceWindow(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req maintenanceWindowRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		window := req.toWindow("")
+		window.CreatedBy = auth.ActorName(c, window.CreatedBy)
+		if err := appContext.AddMaintenanceWindow(window); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		audit.AddTargets(c, window.ID)
+		logger.ProducerLog.Infof("Created maintenance window %s (%s)", window.ID, window.Name)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Maintenance window created successfully",
+			"window":  window,
+		})
+	}
+}
+
+// UpdateMaintenanceWindow replaces an existing maintenance window
+func UpdateMaintenanceWindow(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		windowID := c.Param("windowId")
+
+		var req maintenanceWindowRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		window := req.toWindow(windowID)
+		if err := appContext.UpdateMaintenanceWindow(window); err != nil {
+			if err == models.ErrMaintenanceWindowNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Maintenance window not found",
+				})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Maintenance window updated successfully",
+			"window":  window,
+		})
+	}
+}
+
+// DeleteMaintenanceWindow removes a maintenance window. Faults that were
+// already suppressed by it stay suppressed.
+func DeleteMaintenanceWindow(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		windowID := c.Param("windowId")
+
+		if err := appContext.RemoveMaintenanceWindow(windowID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Maintenance window not found",
+			})
+			return
+		}
+
+		logger.ProducerLog.Infof("Deleted maintenance window %s", windowID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Maintenance window deleted successfully",
+		})
+	}
+}
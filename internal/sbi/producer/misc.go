@@ -4,30 +4,26 @@ import (
 	"encoding/csv"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
 	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/realtime"
 	"github.com/nextranet/gateway/c-plane/pkg/factory"
 	"github.com/nextranet/gateway/c-plane/pkg/service"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow all origins in development, restrict in production
-		return true
-	},
-}
-
 // GetSystemStatus returns system status information
 func GetSystemStatus(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		genieStatus := appContext.GetGenieACSStatus()
-		deviceStats := appContext.GetDeviceStats()
+		deviceStats := appContext.GetTenantDeviceStats(auth.Tenant(c))
 
 		status := gin.H{
 			"status": "operational",
@@ -112,12 +108,14 @@ func UpdateSystemConfig(appContext *context.Context) gin.HandlerFunc {
 
 		// Apply updates (limited to safe fields)
 		if logLevel, ok := updates["logLevel"].(string); ok {
+			audit.RecordChange(c, "system", "logLevel", cfg.Logger.Level, logLevel)
 			cfg.Logger.Level = logLevel
 			logger.SetLogLevel(logLevel)
 		}
 
 		if theme, ok := updates["theme"].(string); ok {
 			if cfg.UI != nil {
+				audit.RecordChange(c, "system", "theme", cfg.UI.Theme, theme)
 				cfg.UI.Theme = theme
 			}
 		}
@@ -155,6 +153,7 @@ func GetTasks(appContext *context.Context) gin.HandlerFunc {
 			})
 			return
 		}
+		tasks = appContext.FilterTenantTasks(auth.RequestTenant(c), tasks)
 
 		// Filter by status if provided
 		if status != "" {
@@ -207,6 +206,31 @@ func DeleteTask(appContext *context.Context) gin.HandlerFunc {
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
+		// Callers limited to a tenant may only delete tasks of its devices
+		if tenant := auth.Tenant(c); tenant != "" {
+			tasks, err := genieService.GetTasks("")
+			if err != nil {
+				logger.ProducerLog.Errorf("Failed to get tasks: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "Failed to delete task",
+				})
+				return
+			}
+			found := false
+			for _, task := range appContext.FilterTenantTasks(tenant, tasks) {
+				if task.ID == taskID {
+					found = true
+					break
+				}
+			}
+			if !found {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Task not found",
+				})
+				return
+			}
+		}
+
 		err := genieService.DeleteTask(taskID)
 		if err != nil {
 			logger.ProducerLog.Errorf("Failed to delete task: %v", err)
@@ -258,6 +282,11 @@ func BulkRefreshDevices(appContext *context.Context) gin.HandlerFunc {
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
+		if !requireTenantDevices(c, appContext, req.DeviceIDs) {
+			return
+		}
+		audit.AddTargets(c, req.DeviceIDs...)
+
 		successful := 0
 		failed := 0
 		errors := make([]string, 0)
@@ -317,6 +346,10 @@ func BulkRebootDevices(appContext *context.Context) gin.HandlerFunc {
 		task := map[string]interface{}{
 			"name": "reboot",
 		}
+		if !requireTenantDevices(c, appContext, req.DeviceIDs) {
+			return
+		}
+		audit.AddTargets(c, req.DeviceIDs...)
 
 		for _, deviceID := range req.DeviceIDs {
 			err := genieService.CreateTask(deviceID, task)
@@ -371,6 +404,14 @@ func BulkSetParameters(appContext *context.Context) gin.HandlerFunc {
 		failed := 0
 		errors := make([]string, 0)
 
+		if !requireTenantDevices(c, appContext, req.DeviceIDs) {
+			return
+		}
+		audit.AddTargets(c, req.DeviceIDs...)
+		for _, deviceID := range req.DeviceIDs {
+			audit.RecordParameterChanges(c, appContext, deviceID, req.Parameters)
+		}
+
 		for _, deviceID := range req.DeviceIDs {
 			err := genieService.SetDeviceParameters(deviceID, req.Parameters)
 			if err != nil {
@@ -426,35 +467,50 @@ func BulkUpdateTags(appContext *context.Context) gin.HandlerFunc {
 		failed := 0
 		errors := make([]string, 0)
 
-		for _, deviceID := range req.DeviceIDs {
-			device, exists := appContext.GetDevice(deviceID)
-			if !exists {
-				failed++
-				errors = append(errors, fmt.Sprintf("%s: device not found", deviceID))
-				continue
-			}
+		switch req.Operation {
+		case "add", "remove", "replace":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid operation",
+			})
+			return
+		}
 
-			switch req.Operation {
-			case "add":
-				for _, tag := range req.Tags {
-					device.Tags[tag] = true
-				}
-			case "remove":
-				for _, tag := range req.Tags {
-					delete(device.Tags, tag)
-				}
-			case "replace":
-				device.Tags = make(map[string]bool)
-				for _, tag := range req.Tags {
-					device.Tags[tag] = true
+		if !requireTenantDevices(c, appContext, req.DeviceIDs) {
+			return
+		}
+		audit.AddTargets(c, req.DeviceIDs...)
+
+		for _, deviceID := range req.DeviceIDs {
+			var before []string
+			updated, err := appContext.UpdateDevice(deviceID, func(device *models.Device) error {
+				before = tagList(device.Tags)
+				switch req.Operation {
+				case "add":
+					if device.Tags == nil {
+						device.Tags = make(map[string]bool)
+					}
+					for _, tag := range req.Tags {
+						device.Tags[tag] = true
+					}
+				case "remove":
+					for _, tag := range req.Tags {
+						delete(device.Tags, tag)
+					}
+				case "replace":
+					device.Tags = make(map[string]bool)
+					for _, tag := range req.Tags {
+						device.Tags[tag] = true
+					}
 				}
-			default:
+				return nil
+			})
+			if err != nil {
 				failed++
-				errors = append(errors, fmt.Sprintf("%s: invalid operation", deviceID))
+				errors = append(errors, fmt.Sprintf("%s: %v", deviceID, err))
 				continue
 			}
-
-			appContext.AddDevice(device)
+			audit.RecordChange(c, deviceID, "tags", before, tagList(updated.Tags))
 			successful++
 		}
 
@@ -480,10 +536,22 @@ func BulkUpdateTags(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
+// tagList returns the set tags of a device, sorted
+func tagList(tags map[string]bool) []string {
+	list := make([]string, 0, len(tags))
+	for tag, set := range tags {
+		if set {
+			list = append(list, tag)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
 // ExportDevices exports devices to CSV
 func ExportDevices(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		devices := appContext.GetAllDevices()
+		devices := appContext.GetFilteredDevices(&models.DeviceFilter{Tenant: auth.RequestTenant(c)})
 
 		// Set headers for CSV download
 		c.Header("Content-Type", "text/csv")
@@ -535,7 +603,7 @@ func ExportDevices(appContext *context.Context) gin.HandlerFunc {
 // ExportFaults exports faults to CSV
 func ExportFaults(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		faults := appContext.GetActiveFaults()
+		faults := appContext.FilterTenantFaults(auth.RequestTenant(c), appContext.GetActiveFaults())
 
 		// Set headers for CSV download
 		c.Header("Content-Type", "text/csv")
@@ -580,69 +648,31 @@ func ExportFaults(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
+// StreamEvents streams events as Server-Sent Events
+func StreamEvents(appContext *context.Context) gin.HandlerFunc {
+	return realtime.SSEHandler(appContext, logger.ProducerLog)
+}
+
 // WebSocketHandler handles WebSocket connections for real-time updates
 func WebSocketHandler(appContext *context.Context) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-		if err != nil {
-			logger.ProducerLog.Errorf("WebSocket upgrade failed: %v", err)
-			return
-		}
-		defer conn.Close()
-
-		// Send initial connection message
-		conn.WriteJSON(gin.H{
-			"type":    "connected",
-			"message": "WebSocket connection established",
-		})
-
-		// Create a ticker for periodic updates
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-
-		// Channel for client messages
-		clientMsg := make(chan []byte, 10)
-		go func() {
-			for {
-				_, msg, err := conn.ReadMessage()
-				if err != nil {
-					if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-						logger.ProducerLog.Errorf("WebSocket read error: %v", err)
-					}
-					close(clientMsg)
-					return
-				}
-				clientMsg <- msg
-			}
-		}()
-
-		for {
-			select {
-			case <-ticker.C:
-				// Send periodic updates
-				stats := appContext.GetDeviceStats()
-				update := gin.H{
-					"type": "stats_update",
-					"data": gin.H{
-						"totalDevices":  stats.TotalDevices,
-						"onlineDevices": stats.OnlineDevices,
-						"activeFaults":  stats.ActiveFaults,
-						"timestamp":     time.Now().UTC().Format(time.RFC3339),
-					},
-				}
-
-				if err := conn.WriteJSON(update); err != nil {
-					logger.ProducerLog.Errorf("WebSocket write error: %v", err)
-					return
-				}
+	var allowedOrigins []string
+	if cfg := factory.GetConfig(); cfg != nil && cfg.NBI != nil {
+		allowedOrigins = cfg.NBI.AllowedOrigins
+	}
+	return realtime.WebSocketHandler(appContext, allowedOrigins, logger.ProducerLog)
+}
 
-			case msg, ok := <-clientMsg:
-				if !ok {
-					return
-				}
-				// Handle client messages (e.g., subscription requests)
-				logger.ProducerLog.Debugf("Received WebSocket message: %s", string(msg))
-			}
+// requireTenantDevices rejects a bulk request naming a device outside the
+// tenant of the caller, as if the device did not exist
+func requireTenantDevices(c *gin.Context, appContext *context.Context, deviceIDs []string) bool {
+	tenant := auth.Tenant(c)
+	for _, deviceID := range deviceIDs {
+		if !appContext.DeviceInTenant(tenant, deviceID) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Device not found: " + deviceID,
+			})
+			return false
 		}
 	}
+	return true
 }
@@ -0,0 +1,162 @@
+package producer
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// alarmRuleRequest is the request body for creating or updating an alarm rule
+type alarmRuleRequest struct {
+	Name              string   `json:"name" binding:"required"`
+	Description       string   `json:"description,omitempty"`
+	Enabled           *bool    `json:"enabled,omitempty"`
+	Code              string   `json:"code" binding:"required"`
+	Severity          string   `json:"severity" binding:"required"`
+	Condition         string   `json:"condition" binding:"required"`
+	ClearCondition    string   `json:"clearCondition,omitempty"`
+	RaiseAfterSeconds int      `json:"raiseAfterSeconds,omitempty"`
+	ClearAfterSeconds int      `json:"clearAfterSeconds,omitempty"`
+	Tags              []string `json:"tags,omitempty"`
+	Models            []string `json:"models,omitempty"`
+}
+
+// toRule converts the request into an alarm rule
+func (r *alarmRuleRequest) toRule(id string) *models.AlarmRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &models.AlarmRule{
+		ID:                id,
+		Name:              r.Name,
+		Description:       r.Description,
+		Enabled:           enabled,
+		Code:              r.Code,
+		Severity:          r.Severity,
+		Condition:         r.Condition,
+		ClearCondition:    r.ClearCondition,
+		RaiseAfterSeconds: r.RaiseAfterSeconds,
+		ClearAfterSeconds: r.ClearAfterSeconds,
+		Tags:              r.Tags,
+		Models:            r.Models,
+	}
+}
+
+// GetAlarmRules returns all gateway alarm rules
+func GetAlarmRules(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alarmRules := appContext.GetAlarmRules()
+
+		c.JSON(http.StatusOK, gin.H{
+			"rules": alarmRules,
+			"total": len(alarmRules),
+		})
+	}
+}
+
+// GetAlarmRule returns a single alarm rule
+func GetAlarmRule(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ruleID := c.Param("ruleId")
+
+		rule, exists := appContext.GetAlarmRule(ruleID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Alarm rule not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, rule)
+	}
+}
+
+// CreateAlarmRule creates a new alarm rule
+func CreateAlarmRule(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req alarmRuleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		rule := req.toRule("")
+		if err := appContext.AddAlarmRule(rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		audit.AddTargets(c, rule.ID)
+		logger.ProducerLog.Infof("Created alarm rule %s (%s)", rule.ID, rule.Name)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Alarm rule created successfully",
+			"rule":    rule,
+		})
+	}
+}
+
+// UpdateAlarmRule replaces an existing alarm rule
+func UpdateAlarmRule(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ruleID := c.Param("ruleId")
+
+		var req alarmRuleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		rule := req.toRule(ruleID)
+		if err := appContext.UpdateAlarmRule(rule); err != nil {
+			if err == models.ErrAlarmRuleNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Alarm rule not found",
+				})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Alarm rule updated successfully",
+			"rule":    rule,
+		})
+	}
+}
+
+// DeleteAlarmRule removes an alarm rule. Its open faults are cleared on the
+// next inventory sync.
+func DeleteAlarmRule(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ruleID := c.Param("ruleId")
+
+		if err := appContext.RemoveAlarmRule(ruleID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Alarm rule not found",
+			})
+			return
+		}
+
+		logger.ProducerLog.Infof("Deleted alarm rule %s", ruleID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Alarm rule deleted successfully",
+		})
+	}
+}
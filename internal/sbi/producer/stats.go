@@ -5,18 +5,22 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/models"
 )
 
-// GetOverviewStats returns overall system statistics
+// GetOverviewStats returns overall system statistics, of one tenant for
+// callers limited to it or asking for it
 func GetOverviewStats(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		tenant := auth.RequestTenant(c)
+
 		// Get device statistics
-		deviceStats := appContext.GetDeviceStats()
+		deviceStats := appContext.GetTenantDeviceStats(tenant)
 
 		// Get fault statistics
-		faults := appContext.GetActiveFaults()
+		faults := appContext.FilterTenantFaults(tenant, appContext.GetActiveFaults())
 		faultStats := calculateFaultStats(faults)
 
 		// Get GenieACS status
@@ -33,12 +37,13 @@ func GetOverviewStats(appContext *context.Context) gin.HandlerFunc {
 				"byModel":  deviceStats.DevicesByModel,
 			},
 			"faults": gin.H{
-				"total":    faultStats.Total,
-				"active":   faultStats.Active,
-				"critical": faultStats.Critical,
-				"major":    faultStats.Major,
-				"minor":    faultStats.Minor,
-				"warning":  faultStats.Warning,
+				"total":      faultStats.Total,
+				"active":     faultStats.Active,
+				"critical":   faultStats.Critical,
+				"major":      faultStats.Major,
+				"minor":      faultStats.Minor,
+				"warning":    faultStats.Warning,
+				"suppressed": faultStats.Suppressed,
 			},
 
 			"system": gin.H{
@@ -53,11 +58,13 @@ func GetOverviewStats(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
-// GetDeviceStats returns detailed device statistics
+// GetDeviceStats returns detailed device statistics, of one tenant for
+// callers limited to it or asking for it
 func GetDeviceStats(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		stats := appContext.GetDeviceStats()
-		devices := appContext.GetAllDevices()
+		tenant := auth.RequestTenant(c)
+		stats := appContext.GetTenantDeviceStats(tenant)
+		devices := appContext.GetFilteredDevices(&models.DeviceFilter{Tenant: tenant})
 
 		// Calculate additional statistics
 		vendorModels := make(map[string]map[string]int)
@@ -143,6 +150,8 @@ type faultStatsResult struct {
 	Minor    int
 	Warning  int
 	Info     int
+
+	Suppressed int
 }
 
 func calculateFaultStats(faults []*models.Fault) faultStatsResult {
@@ -155,6 +164,13 @@ func calculateFaultStats(faults []*models.Fault) faultStatsResult {
 			result.Active++
 		}
 
+		// Suppressed faults are expected during maintenance and are
+		// kept out of the severity counts
+		if fault.Suppressed {
+			result.Suppressed++
+			continue
+		}
+
 		switch fault.Severity {
 		case models.SeverityCritical:
 			result.Critical++
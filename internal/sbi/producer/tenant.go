@@ -0,0 +1,53 @@
+package producer
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+)
+
+// GetTenants returns the tenants visible to the caller with their device
+// statistics. Callers limited to a tenant only see their own.
+func GetTenants(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		own := auth.Tenant(c)
+
+		tenants := make([]gin.H, 0)
+		for _, tenant := range appContext.GetTenants() {
+			if own != "" && tenant.ID != own {
+				continue
+			}
+			tenants = append(tenants, gin.H{
+				"tenant": tenant,
+				"stats":  appContext.GetTenantDeviceStats(tenant.ID),
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"tenants": tenants,
+			"total":   len(tenants),
+		})
+	}
+}
+
+// GetTenant returns a single tenant with its device statistics
+func GetTenant(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.Param("tenantId")
+
+		tenant, exists := appContext.GetTenant(tenantID)
+		if own := auth.Tenant(c); !exists || (own != "" && own != tenantID) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Tenant not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"tenant": tenant,
+			"stats":  appContext.GetTenantDeviceStats(tenant.ID),
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package producer
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// webhookRequest is the request body for creating or updating a webhook
+type webhookRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	URL       string   `json:"url" binding:"required"`
+	Enabled   *bool    `json:"enabled,omitempty"`
+	Secret    string   `json:"secret,omitempty"`
+	Events    []string `json:"events,omitempty"`
+	DeviceIDs []string `json:"deviceIds,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Models    []string `json:"models,omitempty"`
+	CreatedBy string   `json:"createdBy,omitempty"`
+}
+
+// toWebhook converts the request into a webhook, enabled unless stated
+// otherwise
+func (r *webhookRequest) toWebhook(id string) *models.Webhook {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &models.Webhook{
+		ID:        id,
+		Name:      r.Name,
+		URL:       r.URL,
+		Enabled:   enabled,
+		Secret:    r.Secret,
+		Events:    r.Events,
+		DeviceIDs: r.DeviceIDs,
+		Tags:      r.Tags,
+		Models:    r.Models,
+		CreatedBy: r.CreatedBy,
+	}
+}
+
+// GetWebhooks returns all webhooks without their secrets
+func GetWebhooks(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhooks := appContext.GetWebhooks()
+
+		redacted := make([]*models.Webhook, 0, len(webhooks))
+		for _, webhook := range webhooks {
+			redacted = append(redacted, webhook.Redacted())
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"webhooks": redacted,
+			"total":    len(redacted),
+		})
+	}
+}
+
+// GetWebhook returns a single webhook with its delivery counts
+func GetWebhook(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID := c.Param("webhookId")
+
+		webhook, exists := appContext.GetWebhook(webhookID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+
+		counts := map[string]int{
+			models.DeliveryStatusPending:   0,
+			models.DeliveryStatusRetrying:  0,
+			models.DeliveryStatusDelivered: 0,
+			models.DeliveryStatusDead:      0,
+		}
+		for _, delivery := range appContext.GetWebhookDeliveries(webhookID, "") {
+			counts[delivery.Status]++
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"webhook":    webhook.Redacted(),
+			"deliveries": counts,
+		})
+	}
+}
+
+// CreateWebhook creates a new webhook. The response is the only one that
+// includes a generated secret.
+func CreateWebhook(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req webhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		webhook := req.toWebhook("")
+		webhook.CreatedBy = auth.ActorName(c, webhook.CreatedBy)
+		if err := appContext.AddWebhook(webhook); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		audit.AddTargets(c, webhook.ID)
+		logger.ProducerLog.Infof("Created webhook %s (%s) for %s", webhook.ID, webhook.Name, webhook.URL)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Webhook created successfully",
+			"webhook": webhook,
+		})
+	}
+}
+
+// UpdateWebhook replaces an existing webhook. The secret and the enabled
+// state are kept when the request leaves them out.
+func UpdateWebhook(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID := c.Param("webhookId")
+
+		var req webhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		existing, exists := appContext.GetWebhook(webhookID)
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+
+		webhook := req.toWebhook(webhookID)
+		if req.Enabled == nil {
+			webhook.Enabled = existing.Enabled
+		}
+		if err := appContext.UpdateWebhook(webhook); err != nil {
+			if err == models.ErrWebhookNotFound {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Webhook not found",
+				})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		// Echo the secret only when the caller changed it
+		response := webhook.Redacted()
+		if req.Secret != "" {
+			response = webhook
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Webhook updated successfully",
+			"webhook": response,
+		})
+	}
+}
+
+// DeleteWebhook removes a webhook. Deliveries in flight are abandoned.
+func DeleteWebhook(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID := c.Param("webhookId")
+
+		if err := appContext.RemoveWebhook(webhookID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+
+		logger.ProducerLog.Infof("Deleted webhook %s", webhookID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Webhook deleted successfully",
+		})
+	}
+}
+
+// GetWebhookDeliveries returns the delivery history of a webhook, newest
+// first, with every attempt and its status code
+func GetWebhookDeliveries(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID := c.Param("webhookId")
+
+		if _, exists := appContext.GetWebhook(webhookID); !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+
+		status := c.Query("status")
+		switch status {
+		case "", models.DeliveryStatusPending, models.DeliveryStatusRetrying,
+			models.DeliveryStatusDelivered, models.DeliveryStatusDead:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid delivery status",
+			})
+			return
+		}
+
+		limit := 50
+		if l := c.Query("limit"); l != "" {
+			if val, err := strconv.Atoi(l); err == nil && val > 0 && val <= 500 {
+				limit = val
+			}
+		}
+
+		deliveries := appContext.GetWebhookDeliveries(webhookID, status)
+		total := len(deliveries)
+		if len(deliveries) > limit {
+			deliveries = deliveries[:limit]
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"deliveries": deliveries,
+			"total":      total,
+		})
+	}
+}
+
+// GetWebhookDelivery returns a single delivery with its payload
+func GetWebhookDelivery(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		delivery, exists := appContext.GetWebhookDelivery(c.Param("webhookId"), c.Param("deliveryId"))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook delivery not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"delivery": delivery,
+		})
+	}
+}
+
+// RedeliverWebhookDelivery sends the payload of a delivery again as a new
+// delivery, typically one that is dead
+func RedeliverWebhookDelivery(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhookID := c.Param("webhookId")
+		deliveryID := c.Param("deliveryId")
+
+		delivery, err := appContext.RedeliverWebhookDelivery(webhookID, deliveryID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		logger.ProducerLog.Infof("Queued redelivery %s of webhook delivery %s", delivery.ID, deliveryID)
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":  "Redelivery queued",
+			"delivery": delivery,
+		})
+	}
+}
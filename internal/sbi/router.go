@@ -4,60 +4,162 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
 	"github.com/nextranet/gateway/c-plane/internal/sbi/producer"
 )
 
-// InitRouter initializes the SBI router with all routes
-func InitRouter(router *gin.Engine, appContext *context.Context) {
-	// API v1 routes
-	v1 := router.Group("/api/v1")
+// InitRouter initializes the SBI router with all routes. Everything but the
+// health check requires authentication, and each route group a permission.
+// Callers limited to a tenant only reach its devices and their faults, and
+// are refused the resources that span tenants.
+// State-changing requests are recorded in the audit log.
+func InitRouter(router *gin.Engine, appContext *context.Context, authenticator *Authenticator) {
+	view := auth.RequirePermission(models.PermView)
+
+	// API v1 routes, audited before authentication so denied requests are
+	// recorded too
+	v1 := router.Group("/api/v1", audit.Middleware(appContext, models.AuditSourceNBI))
 	{
 		// Health check
 		v1.GET("/health", healthCheck(appContext))
 
-		// Device routes
-		devices := v1.Group("/devices")
+		v1.Use(AuthMiddleware(authenticator))
+
+		// Caller identity, roles and API key management
+		v1.GET("/auth/whoami", producer.GetCurrentPrincipal(appContext))
+		v1.GET("/auth/roles", view, producer.GetRoles(appContext))
+		apiKeys := v1.Group("/auth/keys", auth.RequirePermission(models.PermAPIKeyManage))
+		{
+			apiKeys.GET("", producer.GetAPIKeys(appContext))
+			apiKeys.POST("", producer.CreateAPIKey(appContext))
+			apiKeys.GET("/:keyId", producer.GetAPIKey(appContext))
+			apiKeys.DELETE("/:keyId", producer.DeleteAPIKey(appContext))
+		}
+
+		// Tenants and their device statistics
+		tenants := v1.Group("/tenants", view)
+		{
+			tenants.GET("", producer.GetTenants(appContext))
+			tenants.GET("/:tenantId", producer.GetTenant(appContext))
+		}
+
+		// Device routes. Task types that reboot, reset or flash a device are
+		// checked against their own permissions by CreateDeviceTask.
+		devices := v1.Group("/devices", view, auth.RequireDeviceAccess(appContext))
 		{
+			manage := auth.RequirePermission(models.PermDeviceManage)
+
 			devices.GET("", producer.GetDevices(appContext))
 			devices.GET("/:deviceId", producer.GetDevice(appContext))
-			devices.POST("/:deviceId/refresh", producer.RefreshDevice(appContext))
+			devices.POST("/:deviceId/refresh", manage, producer.RefreshDevice(appContext))
 			devices.GET("/:deviceId/parameters", producer.GetDeviceParameters(appContext))
-			devices.PUT("/:deviceId/parameters", producer.SetDeviceParameters(appContext))
+			devices.PUT("/:deviceId/parameters", manage, producer.SetDeviceParameters(appContext))
 			devices.GET("/:deviceId/tasks", producer.GetDeviceTasks(appContext))
-			devices.POST("/:deviceId/tasks", producer.CreateDeviceTask(appContext))
+			devices.POST("/:deviceId/tasks", manage, producer.CreateDeviceTask(appContext))
 			devices.GET("/:deviceId/faults", producer.GetDeviceFaults(appContext))
-			devices.POST("/:deviceId/reboot", producer.RebootDevice(appContext))
-			devices.POST("/:deviceId/factory-reset", producer.FactoryResetDevice(appContext))
-			devices.PUT("/:deviceId/tags", producer.UpdateDeviceTags(appContext))
+			devices.GET("/:deviceId/history", producer.GetDeviceHistory(appContext))
+			devices.POST("/:deviceId/reboot", auth.RequirePermission(models.PermDeviceReboot), producer.RebootDevice(appContext))
+			devices.POST("/:deviceId/factory-reset", auth.RequirePermission(models.PermDeviceFactoryReset), producer.FactoryResetDevice(appContext))
+			devices.PUT("/:deviceId/tags", manage, producer.UpdateDeviceTags(appContext))
 		}
 
 		// Fault routes
-		faults := v1.Group("/faults")
+		faults := v1.Group("/faults", view, auth.RequireFaultAccess(appContext))
 		{
+			acknowledge := auth.RequirePermission(models.PermFaultAcknowledge)
+
 			faults.GET("", producer.GetFaults(appContext))
+			faults.GET("/mine", producer.GetMyFaults(appContext))
 			faults.GET("/:faultId", producer.GetFault(appContext))
-			faults.PUT("/:faultId/acknowledge", producer.AcknowledgeFault(appContext))
-			faults.PUT("/:faultId/resolve", producer.ResolveFault(appContext))
-			faults.DELETE("/:faultId", producer.DeleteFault(appContext))
+			faults.PUT("/:faultId/acknowledge", acknowledge, producer.AcknowledgeFault(appContext))
+			faults.PUT("/:faultId/resolve", auth.RequirePermission(models.PermFaultResolve), producer.ResolveFault(appContext))
+			faults.PUT("/:faultId/assign", acknowledge, producer.AssignFault(appContext))
+			faults.GET("/:faultId/notes", producer.GetFaultNotes(appContext))
+			faults.POST("/:faultId/notes", acknowledge, producer.AddFaultNote(appContext))
+			faults.DELETE("/:faultId", auth.RequirePermission(models.PermFaultDelete), producer.DeleteFault(appContext))
+		}
+
+		// Incident routes (correlated fault groups)
+		incidents := v1.Group("/incidents", view, auth.RequireIncidentAccess(appContext))
+		{
+			incidents.GET("", producer.GetIncidents(appContext))
+			incidents.GET("/:incidentId", producer.GetIncident(appContext))
+			incidents.PUT("/:incidentId/acknowledge", auth.RequirePermission(models.PermFaultAcknowledge), producer.AcknowledgeIncident(appContext))
+			incidents.PUT("/:incidentId/resolve", auth.RequirePermission(models.PermFaultResolve), producer.ResolveIncident(appContext))
+		}
+
+		// Resources without a tenant, such as maintenance windows that
+		// suppress faults of any device, are refused to tenant callers
+		allTenants := auth.RequireAllTenants()
+
+		// Maintenance window routes
+		maintenance := v1.Group("/maintenance", view, allTenants)
+		{
+			manage := auth.RequirePermission(models.PermMaintenanceManage)
+
+			maintenance.GET("", producer.GetMaintenanceWindows(appContext))
+			maintenance.POST("", manage, producer.CreateMaintenanceWindow(appContext))
+			maintenance.GET("/:windowId", producer.GetMaintenanceWindow(appContext))
+			maintenance.PUT("/:windowId", manage, producer.UpdateMaintenanceWindow(appContext))
+			maintenance.DELETE("/:windowId", manage, producer.DeleteMaintenanceWindow(appContext))
+		}
+
+		configure := auth.RequirePermission(models.PermConfigManage)
+
+		// Escalation policy routes
+		escalation := v1.Group("/escalation/policies", view, allTenants)
+		{
+			escalation.GET("", producer.GetEscalationPolicies(appContext))
+			escalation.POST("", configure, producer.CreateEscalationPolicy(appContext))
+			escalation.GET("/:policyId", producer.GetEscalationPolicy(appContext))
+			escalation.PUT("/:policyId", configure, producer.UpdateEscalationPolicy(appContext))
+			escalation.DELETE("/:policyId", configure, producer.DeleteEscalationPolicy(appContext))
+		}
+
+		// Gateway alarm rule routes
+		alarmRules := v1.Group("/rules", view, allTenants)
+		{
+			alarmRules.GET("", producer.GetAlarmRules(appContext))
+			alarmRules.POST("", configure, producer.CreateAlarmRule(appContext))
+			alarmRules.GET("/:ruleId", producer.GetAlarmRule(appContext))
+			alarmRules.PUT("/:ruleId", configure, producer.UpdateAlarmRule(appContext))
+			alarmRules.DELETE("/:ruleId", configure, producer.DeleteAlarmRule(appContext))
+		}
+
+		// Outbound webhook routes. Webhooks carry secrets and delivery
+		// payloads, so even reading them needs the config permission.
+		webhooks := v1.Group("/webhooks", configure, allTenants)
+		{
+			webhooks.GET("", producer.GetWebhooks(appContext))
+			webhooks.POST("", producer.CreateWebhook(appContext))
+			webhooks.GET("/:webhookId", producer.GetWebhook(appContext))
+			webhooks.PUT("/:webhookId", producer.UpdateWebhook(appContext))
+			webhooks.DELETE("/:webhookId", producer.DeleteWebhook(appContext))
+			webhooks.GET("/:webhookId/deliveries", producer.GetWebhookDeliveries(appContext))
+			webhooks.GET("/:webhookId/deliveries/:deliveryId", producer.GetWebhookDelivery(appContext))
+			webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", producer.RedeliverWebhookDelivery(appContext))
 		}
 
 		// Task routes
-		tasks := v1.Group("/tasks")
+		tasks := v1.Group("/tasks", view)
 		{
+			manage := auth.RequirePermission(models.PermTaskManage)
+
 			tasks.GET("", producer.GetTasks(appContext))
 			tasks.GET("/:taskId", producer.GetTask(appContext))
-			tasks.DELETE("/:taskId", producer.DeleteTask(appContext))
-			tasks.POST("/:taskId/retry", producer.RetryTask(appContext))
+			tasks.DELETE("/:taskId", manage, producer.DeleteTask(appContext))
+			tasks.POST("/:taskId/retry", manage, producer.RetryTask(appContext))
 		}
 
 		// Statistics routes
-		stats := v1.Group("/stats")
+		stats := v1.Group("/stats", view)
 		{
 			stats.GET("/overview", producer.GetOverviewStats(appContext))
 			stats.GET("/devices", producer.GetDeviceStats(appContext))
@@ -66,32 +168,42 @@ func InitRouter(router *gin.Engine, appContext *context.Context) {
 		}
 
 		// System routes
-		system := v1.Group("/system")
+		system := v1.Group("/system", view)
 		{
 			system.GET("/status", producer.GetSystemStatus(appContext))
 			system.GET("/config", producer.GetSystemConfig(appContext))
-			system.PUT("/config", producer.UpdateSystemConfig(appContext))
+			system.PUT("/config", configure, allTenants, producer.UpdateSystemConfig(appContext))
 		}
 
 		// Bulk operations
 		bulk := v1.Group("/bulk")
 		{
-			bulk.POST("/devices/refresh", producer.BulkRefreshDevices(appContext))
-			bulk.POST("/devices/reboot", producer.BulkRebootDevices(appContext))
-			bulk.PUT("/devices/parameters", producer.BulkSetParameters(appContext))
-			bulk.PUT("/devices/tags", producer.BulkUpdateTags(appContext))
+			bulk.POST("/devices/refresh", auth.RequirePermission(models.PermDeviceManage), producer.BulkRefreshDevices(appContext))
+			bulk.POST("/devices/reboot", auth.RequirePermission(models.PermDeviceReboot), producer.BulkRebootDevices(appContext))
+			bulk.PUT("/devices/parameters", auth.RequirePermission(models.PermBulkParameters), producer.BulkSetParameters(appContext))
+			bulk.PUT("/devices/tags", auth.RequirePermission(models.PermDeviceManage), producer.BulkUpdateTags(appContext))
+		}
+
+		// Audit log routes
+		auditLog := v1.Group("/audit", auth.RequirePermission(models.PermAuditView), allTenants)
+		{
+			auditLog.GET("", producer.GetAuditRecords(appContext))
+			auditLog.GET("/verify", producer.VerifyAuditLog(appContext))
 		}
 
 		// Export routes
-		export := v1.Group("/export")
+		export := v1.Group("/export", view)
 		{
 			export.GET("/devices", producer.ExportDevices(appContext))
 			export.GET("/faults", producer.ExportFaults(appContext))
 		}
+
+		// Event stream for clients that cannot use WebSockets
+		v1.GET("/events", view, producer.StreamEvents(appContext))
 	}
 
 	// WebSocket endpoint for real-time updates
-	router.GET("/ws", producer.WebSocketHandler(appContext))
+	router.GET("/ws", AuthMiddleware(authenticator), view, producer.WebSocketHandler(appContext))
 }
 
 // LoggerMiddleware creates a logger middleware for Gin
@@ -147,42 +259,6 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
-// AuthMiddleware creates an authentication middleware
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Check for API key or Bearer token
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Missing authorization header",
-			})
-			c.Abort()
-			return
-		}
-
-		// Check Bearer token
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			// TODO: Validate token
-			if token == "" {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Invalid token",
-				})
-				c.Abort()
-				return
-			}
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid authorization format",
-			})
-			c.Abort()
-			return
-		}
-
-		c.Next()
-	}
-}
-
 // RateLimitMiddleware creates a rate limiting middleware
 func RateLimitMiddleware(requestsPerMinute int) gin.HandlerFunc {
 	// Simple in-memory rate limiter
@@ -0,0 +1,93 @@
+package sbi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// newTestRouter serves the NBI with an admin key limited to a tenant and
+// one that sees across tenants
+func newTestRouter(t *testing.T) (router *gin.Engine, tenantKey, globalKey string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	appContext := context.NewContext()
+	appContext.SetTenants([]*models.Tenant{
+		{ID: "north", Selector: models.TenantSelector{OUIs: []string{"00A0B1"}}},
+	})
+
+	var err error
+	if tenantKey, err = appContext.AddAPIKey(&models.APIKey{Name: "north-admin", Role: models.RoleAdmin, Tenant: "north"}); err != nil {
+		t.Fatalf("AddAPIKey: %v", err)
+	}
+	if globalKey, err = appContext.AddAPIKey(&models.APIKey{Name: "admin", Role: models.RoleAdmin}); err != nil {
+		t.Fatalf("AddAPIKey: %v", err)
+	}
+
+	authenticator, err := NewAuthenticator(&config.NBIAuth{Enabled: true}, appContext)
+	if err != nil {
+		t.Fatalf("NewAuthenticator: %v", err)
+	}
+	router = gin.New()
+	InitRouter(router, appContext, authenticator)
+	return router, tenantKey, globalKey
+}
+
+// serve sends a request with an API key and returns the status
+func serve(router *gin.Engine, key, method, path, body string) int {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-API-Key", key)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestTenantCallerRefused(t *testing.T) {
+	router, tenantKey, globalKey := newTestRouter(t)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/api/v1/audit"},
+		{http.MethodGet, "/api/v1/audit/verify"},
+		{http.MethodGet, "/api/v1/maintenance"},
+		{http.MethodPost, "/api/v1/maintenance"},
+		{http.MethodGet, "/api/v1/maintenance/window-1"},
+		{http.MethodPut, "/api/v1/maintenance/window-1"},
+		{http.MethodDelete, "/api/v1/maintenance/window-1"},
+		{http.MethodGet, "/api/v1/escalation/policies"},
+		{http.MethodPost, "/api/v1/escalation/policies"},
+		{http.MethodPut, "/api/v1/escalation/policies/policy-1"},
+		{http.MethodDelete, "/api/v1/escalation/policies/policy-1"},
+		{http.MethodGet, "/api/v1/rules"},
+		{http.MethodPost, "/api/v1/rules"},
+		{http.MethodPut, "/api/v1/rules/rule-1"},
+		{http.MethodDelete, "/api/v1/rules/rule-1"},
+		{http.MethodGet, "/api/v1/webhooks"},
+		{http.MethodPost, "/api/v1/webhooks"},
+		{http.MethodPut, "/api/v1/webhooks/hook-1"},
+		{http.MethodDelete, "/api/v1/webhooks/hook-1"},
+		{http.MethodGet, "/api/v1/webhooks/hook-1/deliveries"},
+		{http.MethodPost, "/api/v1/webhooks/hook-1/deliveries/delivery-1/redeliver"},
+		{http.MethodPut, "/api/v1/system/config"},
+	}
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			if status := serve(router, tenantKey, route.method, route.path, "{}"); status != http.StatusForbidden {
+				t.Errorf("tenant caller got %d, want 403", status)
+			}
+			if status := serve(router, globalKey, route.method, route.path, "{}"); status == http.StatusForbidden {
+				t.Error("caller across tenants refused")
+			}
+		})
+	}
+}
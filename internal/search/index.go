@@ -0,0 +1,328 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Field weights, matches on identifying fields rank higher
+const (
+	weightSerial       = 10
+	weightDeviceID     = 8
+	weightIPAddress    = 6
+	weightTag          = 5
+	weightModel        = 4
+	weightOUI          = 4
+	weightManufacturer = 3
+	weightProductClass = 3
+	weightParameter    = 2
+)
+
+// Match kind multipliers
+const (
+	scoreExact  = 3.0
+	scorePrefix = 2.0
+	scoreFuzzy  = 1.0
+)
+
+// Result is a ranked search hit
+type Result struct {
+	DeviceID string  `json:"deviceId"`
+	Score    float64 `json:"score"`
+}
+
+// Index is an inverted index over device identifiers, tags, addresses and
+// selected parameter values. It supports exact, prefix and fuzzy term
+// matching and is updated one device at a time.
+type Index struct {
+	mu sync.RWMutex
+
+	// token -> device ID -> best field weight
+	postings map[string]map[string]int
+	// device ID -> tokens, used to remove a device before reindexing it
+	documents map[string][]string
+	// sorted token list for prefix and fuzzy lookups
+	tokens []string
+
+	parameters []string
+}
+
+// NewIndex creates an empty index. parameters lists the parameter paths
+// whose values are searchable.
+func NewIndex(parameters []string) *Index {
+	return &Index{
+		postings:   make(map[string]map[string]int),
+		documents:  make(map[string][]string),
+		parameters: parameters,
+	}
+}
+
+// SetParameters changes the searchable parameter paths. Devices indexed
+// earlier keep their old terms until they are updated.
+func (idx *Index) SetParameters(parameters []string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.parameters = parameters
+}
+
+// Update indexes a device, replacing what was indexed for it before
+func (idx *Index) Update(device *models.Device) {
+	terms := make(map[string]int)
+	add := func(value string, weight int) {
+		for _, token := range Tokenize(value) {
+			if terms[token] < weight {
+				terms[token] = weight
+			}
+		}
+	}
+
+	add(device.DeviceID.SerialNumber, weightSerial)
+	add(device.ID, weightDeviceID)
+	add(device.DeviceID.IPAddress, weightIPAddress)
+	add(device.DeviceID.ExternalIPAddress, weightIPAddress)
+	add(device.DeviceID.ModelName, weightModel)
+	add(device.DeviceID.OUI, weightOUI)
+	add(device.DeviceID.Manufacturer, weightManufacturer)
+	add(device.DeviceID.ProductClass, weightProductClass)
+	for tag := range device.Tags {
+		add(tag, weightTag)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, path := range idx.parameters {
+		if value, ok := device.ParameterValue(path); ok {
+			add(fmt.Sprintf("%v", value), weightParameter)
+		}
+	}
+
+	// Keep tokens the device still has, splicing them out of the sorted
+	// token list and back in costs a copy of the whole list
+	idx.remove(device.ID, terms)
+
+	tokens := make([]string, 0, len(terms))
+	for token, weight := range terms {
+		posting, exists := idx.postings[token]
+		if !exists {
+			posting = make(map[string]int)
+			idx.postings[token] = posting
+			idx.insertToken(token)
+		}
+		posting[device.ID] = weight
+		tokens = append(tokens, token)
+	}
+	idx.documents[device.ID] = tokens
+}
+
+// Remove drops a device from the index
+func (idx *Index) Remove(deviceID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(deviceID, nil)
+}
+
+// Len returns the number of indexed devices
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.documents)
+}
+
+// Search returns the devices matching every term of the query, best match
+// first. A limit of 0 returns all hits.
+func (idx *Index) Search(query string, limit int) []Result {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var scores map[string]float64
+	for _, term := range terms {
+		termScores := idx.matchTerm(term)
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		// Every term has to match
+		for deviceID, score := range scores {
+			if termScore, ok := termScores[deviceID]; ok {
+				scores[deviceID] = score + termScore
+			} else {
+				delete(scores, deviceID)
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for deviceID, score := range scores {
+		results = append(results, Result{DeviceID: deviceID, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].DeviceID < results[j].DeviceID
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// matchTerm scores every device with a token matching the term. A device
+// keeps the best of its exact, prefix and fuzzy matches.
+// Must be called with mu held.
+func (idx *Index) matchTerm(term string) map[string]float64 {
+	scores := make(map[string]float64)
+	collect := func(token string, multiplier float64) {
+		for deviceID, weight := range idx.postings[token] {
+			if score := float64(weight) * multiplier; score > scores[deviceID] {
+				scores[deviceID] = score
+			}
+		}
+	}
+
+	// Exact and prefix matches are a contiguous run of the sorted tokens
+	start := sort.SearchStrings(idx.tokens, term)
+	for i := start; i < len(idx.tokens) && strings.HasPrefix(idx.tokens[i], term); i++ {
+		if idx.tokens[i] == term {
+			collect(idx.tokens[i], scoreExact)
+		} else {
+			collect(idx.tokens[i], scorePrefix)
+		}
+	}
+
+	maxDistance := fuzzyDistance(term)
+	if maxDistance == 0 {
+		return scores
+	}
+	for _, token := range idx.tokens {
+		if abs(len(token)-len(term)) > maxDistance || strings.HasPrefix(token, term) {
+			continue
+		}
+		if distance := levenshtein(term, token, maxDistance); distance <= maxDistance {
+			collect(token, scoreFuzzy/float64(distance))
+		}
+	}
+	return scores
+}
+
+// remove drops a device from the postings. Tokens listed in keep stay in the
+// token list even when no device uses them anymore, the caller is about to
+// add them again. Must be called with mu held.
+func (idx *Index) remove(deviceID string, keep map[string]int) {
+	for _, token := range idx.documents[deviceID] {
+		posting := idx.postings[token]
+		delete(posting, deviceID)
+		if _, kept := keep[token]; len(posting) == 0 && !kept {
+			delete(idx.postings, token)
+			idx.deleteToken(token)
+		}
+	}
+	delete(idx.documents, deviceID)
+}
+
+// insertToken adds a token to the sorted token list. Must be called with mu
+// held.
+func (idx *Index) insertToken(token string) {
+	i := sort.SearchStrings(idx.tokens, token)
+	idx.tokens = append(idx.tokens, "")
+	copy(idx.tokens[i+1:], idx.tokens[i:])
+	idx.tokens[i] = token
+}
+
+// deleteToken removes a token from the sorted token list. Must be called
+// with mu held.
+func (idx *Index) deleteToken(token string) {
+	i := sort.SearchStrings(idx.tokens, token)
+	if i < len(idx.tokens) && idx.tokens[i] == token {
+		idx.tokens = append(idx.tokens[:i], idx.tokens[i+1:]...)
+	}
+}
+
+// Tokenize lowercases a value and returns the whole value plus its
+// alphanumeric parts, so "192.168.1.10" matches both as an address and by
+// octet, and "LTE-FDD_N2" by each word.
+func Tokenize(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil
+	}
+
+	tokens := make([]string, 0, 4)
+	seen := make(map[string]bool)
+	add := func(token string) {
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, word := range strings.Fields(value) {
+		add(word)
+		for _, part := range strings.FieldsFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(part)
+		}
+	}
+	return tokens
+}
+
+// fuzzyDistance returns the edit distance tolerated for a term. Short terms
+// must match exactly or by prefix.
+func fuzzyDistance(term string) int {
+	switch n := len(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// levenshtein returns the edit distance between two strings, stopping early
+// once it exceeds max
+func levenshtein(a, b string, max int) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/web/templates"
+)
+
+// auditPageRecords is how many of the matching records the audit page shows,
+// the CSV export has all of them
+const auditPageRecords = 200
+
+// Audit renders the audit log page
+func Audit(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get theme
+		theme := c.GetString("theme")
+		if theme == "" {
+			theme = "dark"
+		}
+
+		// Prepare data for template
+		data := templates.AuditPageData{
+			BasePageData: templates.BasePageData{
+				Title:       "Audit Log",
+				Theme:       theme,
+				CurrentPath: "/audit",
+				User:        currentUser(c),
+				CSRFToken:   csrfToken(c),
+			},
+			Query: c.Request.URL.RawQuery,
+		}
+
+		filter, err := audit.ParseFilter(c)
+		if err != nil {
+			data.Error = err.Error()
+		} else {
+			records := appContext.GetAuditRecords(filter)
+			data.Total = len(records)
+			if len(records) > auditPageRecords {
+				records = records[:auditPageRecords]
+			}
+			data.Records = records
+		}
+		data.Filter = filter
+
+		// Render the audit page
+		component := templates.AuditPage(data)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+
+		if err := component.Render(c.Request.Context(), c.Writer); err != nil {
+			logger.WebLog.Errorf("Failed to render audit page: %v", err)
+			c.String(http.StatusInternalServerError, "Failed to render page")
+			return
+		}
+	}
+}
+
+// GetAuditRecords returns the matching audit records, newest first, as JSON
+// or with format=csv as a CSV download
+func GetAuditRecords(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := audit.ParseFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		records := appContext.GetAuditRecords(filter)
+
+		if c.Query("format") == "csv" {
+			c.Header("Content-Type", "text/csv")
+			c.Header("Content-Disposition", "attachment; filename=audit.csv")
+
+			if err := audit.WriteCSV(c.Writer, records); err != nil {
+				logger.WebLog.Errorf("Failed to export audit records: %v", err)
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"records": records,
+			"total":   len(records),
+		})
+	}
+}
+
+// VerifyAuditLog checks the hash chain of the audit log
+func VerifyAuditLog(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verification, err := appContext.VerifyAuditLog()
+		if err != nil {
+			logger.WebLog.Errorf("Failed to verify audit log: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to read audit log",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, verification)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/web/templates"
+)
+
+// currentUser returns the user of a request with the permissions of its
+// roles, so pages can hide the actions the user cannot perform
+func currentUser(c *gin.Context) *templates.UserInfo {
+	principal, exists := auth.GetPrincipal(c)
+	if !exists {
+		return nil
+	}
+
+	user := &templates.UserInfo{
+		Username:    principal.Name,
+		Role:        strings.Join(principal.Roles, ", "),
+		Permissions: make(map[string]bool, len(principal.Permissions)),
+	}
+	for _, permission := range principal.Permissions {
+		user.Permissions[permission] = true
+	}
+	// The audit log spans tenants, users limited to one are refused it
+	if principal.Tenant != "" {
+		delete(user.Permissions, models.PermAuditView)
+	}
+	return user
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
 	"github.com/nextranet/gateway/c-plane/internal/models"
@@ -63,15 +65,30 @@ func Devices(appContext *context.Context) gin.HandlerFunc {
 			filter.Tags = strings.Split(tags, ",")
 		}
 
+		ipNegate, _ := strconv.ParseBool(c.Query("ipNegate"))
+		ipRange, ipErr := models.ParseIPRange(c.Query("startIP"), c.Query("endIP"), c.Query("cidr"), c.Query("ipField"), ipNegate)
+		if ipErr != nil {
+			logger.WebLog.Warnf("Ignoring invalid IP filter: %v", ipErr)
+		}
+		filter.IPRange = ipRange
+		filter.Tenant = auth.RequestTenant(c)
+
 		// Get devices from GenieACS
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
-		devices, err := genieService.GetDevices(filter)
-		if err != nil {
-			logger.WebLog.Errorf("Failed to get devices: %v", err)
-			// Fall back to cached data
+		var devices []*models.Device
+		var err error
+		if filter.Search != "" {
+			// Searches run against the inventory index, ranked by relevance
 			devices = appContext.GetFilteredDevices(filter)
+		} else {
+			devices, err = genieService.GetDevices(filter)
+			if err != nil {
+				logger.WebLog.Errorf("Failed to get devices: %v", err)
+				// Fall back to cached data
+				devices = appContext.GetFilteredDevices(filter)
+			}
 		}
 
 		// Convert to display format
@@ -79,8 +96,8 @@ func Devices(appContext *context.Context) gin.HandlerFunc {
 		for _, device := range devices {
 			displayDevice := &templates.DeviceDisplay{
 				Device:       device,
-				StatusClass:  getDeviceStatusClass(device.Status.Online),
-				StatusText:   getDeviceStatusText(device.Status.Online),
+				StatusClass:  getDeviceStatusClass(device.Status),
+				StatusText:   getDeviceStatusText(device.Status),
 				LastSeenText: formatLastSeen(device.Status.LastSeen),
 				TagList:      getTagList(device.Tags),
 			}
@@ -107,6 +124,8 @@ func Devices(appContext *context.Context) gin.HandlerFunc {
 				Title:       "Devices",
 				Theme:       theme,
 				CurrentPath: "/devices",
+				User:        currentUser(c),
+				CSRFToken:   csrfToken(c),
 			},
 			Devices:       displayDevices,
 			TotalCount:    len(devices),
@@ -121,6 +140,9 @@ func Devices(appContext *context.Context) gin.HandlerFunc {
 				Model:  filter.ModelName,
 				Status: c.Query("status"),
 				Tags:   filter.Tags,
+
+				IPRange: filter.IPRange,
+				IPError: errorText(ipErr),
 			},
 
 			Vendors: vendors,
@@ -161,6 +183,7 @@ func DeviceDetail(appContext *context.Context) gin.HandlerFunc {
 			c.String(http.StatusNotFound, "Device not found")
 			return
 		}
+		appContext.AddDevice(device)
 
 		// Get device parameters
 		paramNames := []string{
@@ -180,8 +203,13 @@ func DeviceDetail(appContext *context.Context) gin.HandlerFunc {
 		// Get tasks for device
 		tasks, _ := genieService.GetTasks(deviceID)
 
-		// Get faults for device
-		faults, _ := genieService.GetFaults(deviceID)
+		// Get faults for device, merged with the lifecycle state kept in
+		// the context (status, assignee, notes)
+		genieFaults, _ := genieService.GetFaults(deviceID)
+		for _, fault := range genieFaults {
+			appContext.AddFault(fault)
+		}
+		faults := appContext.GetDeviceFaults(deviceID)
 
 		// Get theme
 		theme := c.GetString("theme")
@@ -190,19 +218,28 @@ func DeviceDetail(appContext *context.Context) gin.HandlerFunc {
 		}
 
 		// Prepare data for template
+		user := currentUser(c)
 		data := templates.DeviceDetailData{
 			BasePageData: templates.BasePageData{
 				Title:       "Device: " + device.DeviceID.SerialNumber,
 				Theme:       theme,
 				CurrentPath: "/devices",
+				User:        user,
+				CSRFToken:   csrfToken(c),
 			},
 			Device:     device,
 			Parameters: parameters,
 			Tasks:      tasks,
 			Faults:     faults,
 
-			IsOnline:  device.Status.Online,
-			CanManage: true, // Based on user permissions
+			StatusHistory: getStatusHistory(appContext, device.ID, 20),
+			Availability:  getAvailability(appContext, device.ID),
+			IsOnline:      device.Status.Online,
+
+			CanManage:       user.Can(models.PermDeviceManage),
+			CanReboot:       user.Can(models.PermDeviceReboot),
+			CanFactoryReset: user.Can(models.PermDeviceFactoryReset),
+			CanAcknowledge:  user.Can(models.PermFaultAcknowledge),
 		}
 
 		// Render the device detail page
@@ -220,7 +257,7 @@ func DeviceDetail(appContext *context.Context) gin.HandlerFunc {
 // DeviceStatusUpdate returns device status updates for AJAX
 func DeviceStatusUpdate(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		devices := appContext.GetAllDevices()
+		devices := appContext.GetFilteredDevices(&models.DeviceFilter{Tenant: auth.Tenant(c)})
 
 		statusUpdate := make([]gin.H, 0, len(devices))
 		for _, device := range devices {
@@ -404,6 +441,8 @@ func UpdateParameter(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		audit.RecordParameterChanges(c, appContext, deviceID, map[string]interface{}{request.Parameter: request.Value})
+
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
@@ -448,6 +487,7 @@ func AddDeviceTag(appContext *context.Context) gin.HandlerFunc {
 		cfg := factory.GetConfig()
 		genieService := service.NewGenieACSService(cfg.GenieACS, appContext)
 
+		audit.RecordChange(c, deviceID, "tag", nil, request.Tag)
 		err := genieService.AddDeviceTag(deviceID, request.Tag)
 		if err != nil {
 			logger.WebLog.Errorf("Failed to add device tag: %v", err)
@@ -498,18 +538,62 @@ func RemoveDeviceTag(appContext *context.Context) gin.HandlerFunc {
 
 // Helper functions
 
-func getDeviceStatusClass(online bool) string {
-	if online {
+// errorText returns the message of an error or an empty string
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// getStatusHistory returns the most recent device events, newest first
+func getStatusHistory(appContext *context.Context, deviceID string, limit int) []templates.StatusEvent {
+	events := appContext.GetDeviceHistory(deviceID, time.Time{})
+
+	history := make([]templates.StatusEvent, 0, min(limit, len(events)))
+	for i := len(events) - 1; i >= 0 && len(history) < limit; i-- {
+		history = append(history, templates.StatusEvent{
+			Timestamp: events[i].Timestamp,
+			Status:    events[i].Type,
+			Message:   events[i].Message,
+		})
+	}
+	return history
+}
+
+// getAvailability returns the device availability over the default ranges
+func getAvailability(appContext *context.Context, deviceID string) []models.Availability {
+	now := time.Now()
+	availability := make([]models.Availability, 0, len(models.DefaultAvailabilityRanges))
+	for _, r := range models.DefaultAvailabilityRanges {
+		d, _ := models.ParseHistoryRange(r)
+		a := appContext.GetDeviceAvailability(deviceID, now.Add(-d), now)
+		a.Range = r
+		availability = append(availability, a)
+	}
+	return availability
+}
+
+func getDeviceStatusClass(status models.DeviceStatus) string {
+	switch {
+	case status.ConnectionStatus == models.ConnectionStatusLate:
+		return "text-yellow-500"
+	case status.Online:
 		return "text-green-500"
+	default:
+		return "text-red-500"
 	}
-	return "text-red-500"
 }
 
-func getDeviceStatusText(online bool) string {
-	if online {
-		return "online"
+func getDeviceStatusText(status models.DeviceStatus) string {
+	switch {
+	case status.ConnectionStatus == models.ConnectionStatusLate:
+		return models.ConnectionStatusLate
+	case status.Online:
+		return models.ConnectionStatusOnline
+	default:
+		return models.ConnectionStatusOffline
 	}
-	return "offline"
 }
 
 func getDeviceMapColor(online bool) string {
@@ -15,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
 	"github.com/nextranet/gateway/c-plane/internal/web/templates"
 	"github.com/nextranet/gateway/c-plane/pkg/factory"
 )
@@ -49,11 +50,14 @@ func Files(appContext *context.Context) gin.HandlerFunc {
 		}
 
 		// Prepare data for template
+		user := currentUser(c)
 		data := templates.FilesPageData{
 			BasePageData: templates.BasePageData{
 				Title:       "Files",
 				Theme:       theme,
 				CurrentPath: "/files",
+				User:        user,
+				CSRFToken:   csrfToken(c),
 			},
 			Files:     files,
 			TotalSize: totalSize,
@@ -61,6 +65,7 @@ func Files(appContext *context.Context) gin.HandlerFunc {
 				Type:   c.Query("type"),
 				Search: c.Query("search"),
 			},
+			CanManage: user.Can(models.PermFirmwareManage),
 		}
 
 		// Render the files page
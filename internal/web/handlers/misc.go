@@ -8,20 +8,14 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
 	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/realtime"
 	"github.com/nextranet/gateway/c-plane/internal/web/templates"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow all origins in development, restrict in production
-		return true
-	},
-}
-
 // Faults renders the faults/alarms page
 func Faults(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -38,15 +32,31 @@ func Faults(appContext *context.Context) gin.HandlerFunc {
 
 		// Build filter from query parameters
 		filter := &models.FaultFilter{
-			DeviceID:  c.Query("deviceId"),
-			Severity:  c.Query("severity"),
-			Status:    c.Query("status"),
-			Channel:   c.Query("channel"),
-			TimeRange: c.Query("timeRange"),
+			DeviceID:   c.Query("deviceId"),
+			Severity:   c.Query("severity"),
+			Status:     c.Query("status"),
+			Channel:    c.Query("channel"),
+			TimeRange:  c.Query("timeRange"),
+			IncidentID: c.Query("incidentId"),
+			Assignee:   c.Query("assignee"),
 		}
 
 		// Get faults from context
-		allFaults := appContext.GetActiveFaults()
+		tenant := auth.RequestTenant(c)
+		allFaults := appContext.FilterTenantFaults(tenant, appContext.GetActiveFaults())
+		user := currentUser(c)
+
+		// Drill down into a single incident shows all of its members
+		var incident *templates.IncidentDisplay
+		if filter.IncidentID != "" {
+			if inc, exists := appContext.GetIncident(filter.IncidentID); exists {
+				if scoped := appContext.FilterTenantIncidents(tenant, []*models.Incident{inc}); len(scoped) > 0 {
+					incident = newIncidentDisplay(scoped[0], user)
+					allFaults, _ = appContext.GetIncidentFaults(filter.IncidentID)
+					allFaults = appContext.FilterTenantFaults(tenant, allFaults)
+				}
+			}
+		}
 
 		// Apply filters
 		filteredFaults := filterFaults(allFaults, filter)
@@ -76,8 +86,8 @@ func Faults(appContext *context.Context) gin.HandlerFunc {
 				SeverityClass:  getSeverityClass(fault.Severity),
 				StatusClass:    getStatusClass(fault.Status),
 				TimeAgoText:    formatTimeAgo(fault.Timestamp),
-				CanAcknowledge: fault.Status == models.FaultStatusActive,
-				CanResolve:     fault.Status == models.FaultStatusActive || fault.Status == models.FaultStatusAcknowledged,
+				CanAcknowledge: fault.Status == models.FaultStatusActive && user.Can(models.PermFaultAcknowledge),
+				CanResolve:     (fault.Status == models.FaultStatusActive || fault.Status == models.FaultStatusAcknowledged) && user.Can(models.PermFaultResolve),
 			}
 			displayFaults = append(displayFaults, displayFault)
 		}
@@ -87,8 +97,14 @@ func Faults(appContext *context.Context) gin.HandlerFunc {
 		activeCount := 0
 		acknowledgedCount := 0
 
+		suppressedCount := 0
+
 		for _, fault := range allFaults {
-			severityStats[fault.Severity]++
+			if fault.Suppressed {
+				suppressedCount++
+			} else {
+				severityStats[fault.Severity]++
+			}
 			switch fault.Status {
 			case models.FaultStatusActive:
 				activeCount++
@@ -97,6 +113,31 @@ func Faults(appContext *context.Context) gin.HandlerFunc {
 			}
 		}
 
+		// Get open correlated incidents
+		incidents := appContext.FilterTenantIncidents(tenant, appContext.GetIncidents(&models.IncidentFilter{
+			MinFaults: appContext.GetCorrelationConfig().MinFaults,
+		}))
+		displayIncidents := make([]*templates.IncidentDisplay, 0, len(incidents))
+		for _, inc := range incidents {
+			if inc.Status == models.FaultStatusResolved {
+				continue
+			}
+			displayIncidents = append(displayIncidents, newIncidentDisplay(inc, user))
+		}
+
+		// Get current and upcoming maintenance windows. They span tenants,
+		// users limited to a tenant do not see them.
+		now := time.Now()
+		maintenance := make([]*templates.MaintenanceWindowDisplay, 0)
+		if auth.Tenant(c) == "" {
+			for _, window := range appContext.GetMaintenanceWindows() {
+				if window.IsExpired(now) {
+					continue
+				}
+				maintenance = append(maintenance, newMaintenanceWindowDisplay(window, now))
+			}
+		}
+
 		// Get theme
 		theme := c.GetString("theme")
 		if theme == "" {
@@ -109,6 +150,8 @@ func Faults(appContext *context.Context) gin.HandlerFunc {
 				Title:       "Faults & Alarms",
 				Theme:       theme,
 				CurrentPath: "/faults",
+				User:        user,
+				CSRFToken:   csrfToken(c),
 			},
 			Faults:            displayFaults,
 			TotalCount:        totalCount,
@@ -118,13 +161,19 @@ func Faults(appContext *context.Context) gin.HandlerFunc {
 			PageSize:          pageSize,
 			TotalPages:        totalPages,
 			Filters: templates.FaultFilters{
-				DeviceID:  filter.DeviceID,
-				Severity:  filter.Severity,
-				Status:    filter.Status,
-				Channel:   filter.Channel,
-				TimeRange: filter.TimeRange,
+				DeviceID:   filter.DeviceID,
+				Severity:   filter.Severity,
+				Status:     filter.Status,
+				Channel:    filter.Channel,
+				TimeRange:  filter.TimeRange,
+				IncidentID: filter.IncidentID,
+				Assignee:   filter.Assignee,
 			},
-			SeverityStats: severityStats,
+			SeverityStats:   severityStats,
+			Incidents:       displayIncidents,
+			Incident:        incident,
+			SuppressedCount: suppressedCount,
+			Maintenance:     maintenance,
 		}
 
 		// Render the faults page
@@ -165,6 +214,16 @@ func filterFaults(faults []*models.Fault, filter *models.FaultFilter) []*models.
 			continue
 		}
 
+		// Apply incident filter
+		if filter.IncidentID != "" && fault.IncidentID != filter.IncidentID {
+			continue
+		}
+
+		// Apply assignee filter
+		if filter.Assignee != "" && !strings.EqualFold(fault.Assignee, filter.Assignee) {
+			continue
+		}
+
 		// Apply time range filter
 		if filter.TimeRange != "" {
 			if !isWithinTimeRange(fault.Timestamp, filter.TimeRange) {
@@ -178,6 +237,46 @@ func filterFaults(faults []*models.Fault, filter *models.FaultFilter) []*models.
 	return filtered
 }
 
+func newIncidentDisplay(incident *models.Incident, user *templates.UserInfo) *templates.IncidentDisplay {
+	return &templates.IncidentDisplay{
+		Incident:       incident,
+		TimeAgoText:    formatTimeAgo(incident.LastSeen),
+		CanAcknowledge: incident.Status == models.FaultStatusActive && user.Can(models.PermFaultAcknowledge),
+		CanResolve:     (incident.Status == models.FaultStatusActive || incident.Status == models.FaultStatusAcknowledged) && user.Can(models.PermFaultResolve),
+	}
+}
+
+func newMaintenanceWindowDisplay(window *models.MaintenanceWindow, now time.Time) *templates.MaintenanceWindowDisplay {
+	schedule := fmt.Sprintf("%s - %s",
+		window.StartTime.Local().Format("2006-01-02 15:04"),
+		window.EndTime.Local().Format("2006-01-02 15:04"))
+	if window.Recurrence != models.RecurrenceNone {
+		schedule += ", repeats " + window.Recurrence
+	}
+
+	selectors := make([]string, 0, 3)
+	if len(window.DeviceIDs) > 0 {
+		selectors = append(selectors, fmt.Sprintf("%d devices", len(window.DeviceIDs)))
+	}
+	if len(window.Tags) > 0 {
+		selectors = append(selectors, "tags: "+strings.Join(window.Tags, ", "))
+	}
+	if len(window.Models) > 0 {
+		selectors = append(selectors, "models: "+strings.Join(window.Models, ", "))
+	}
+	selector := "All devices"
+	if len(selectors) > 0 {
+		selector = strings.Join(selectors, "; ")
+	}
+
+	return &templates.MaintenanceWindowDisplay{
+		MaintenanceWindow: window,
+		Active:            window.IsActiveAt(now),
+		ScheduleText:      schedule,
+		SelectorText:      selector,
+	}
+}
+
 func getDeviceName(serial string, appContext *context.Context) string {
 	if device, exists := appContext.GetDeviceBySerial(serial); exists {
 		if device.DeviceID.ModelName != "" {
@@ -268,6 +367,7 @@ func AcknowledgeFault(appContext *context.Context) gin.HandlerFunc {
 			})
 			return
 		}
+		req.AcknowledgedBy = auth.ActorName(c, req.AcknowledgedBy)
 
 		err := appContext.AcknowledgeFault(faultID, req.AcknowledgedBy)
 		if err != nil {
@@ -277,6 +377,10 @@ func AcknowledgeFault(appContext *context.Context) gin.HandlerFunc {
 			return
 		}
 
+		if req.Notes != "" {
+			appContext.AddFaultNote(faultID, req.AcknowledgedBy, req.Notes, "")
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
 			"message": "Fault acknowledged successfully",
@@ -297,6 +401,7 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 
 		var req struct {
 			ResolvedBy string `json:"resolvedBy"`
+			Category   string `json:"category"`
 			Resolution string `json:"resolution"`
 			Notes      string `json:"notes"`
 		}
@@ -307,15 +412,26 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 			})
 			return
 		}
+		req.ResolvedBy = auth.ActorName(c, req.ResolvedBy)
 
-		err := appContext.ResolveFault(faultID, req.ResolvedBy)
+		err := appContext.ResolveFault(faultID, req.ResolvedBy, req.Category, req.Resolution)
 		if err != nil {
+			if err == models.ErrInvalidResolution {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid resolution category",
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": "Failed to resolve fault",
 			})
 			return
 		}
 
+		if req.Notes != "" {
+			appContext.AddFaultNote(faultID, req.ResolvedBy, req.Notes, "")
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
 			"message": "Fault resolved successfully",
@@ -323,6 +439,181 @@ func ResolveFault(appContext *context.Context) gin.HandlerFunc {
 	}
 }
 
+// GetFault returns a single fault with its notes for the detail modal
+func GetFault(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fault, exists := appContext.GetFault(c.Param("faultId"))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Fault not found",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, fault)
+	}
+}
+
+// AssignFault handles fault assignment
+func AssignFault(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		faultID := c.Param("faultId")
+
+		var req struct {
+			Assignee   string `json:"assignee"`
+			AssignedBy string `json:"assignedBy"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+		req.AssignedBy = auth.ActorName(c, req.AssignedBy)
+
+		if err := appContext.AssignFault(faultID, req.Assignee, req.AssignedBy); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to assign fault",
+			})
+			return
+		}
+
+		message := "Fault assigned to " + req.Assignee
+		if req.Assignee == "" {
+			message = "Fault unassigned"
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": message,
+		})
+	}
+}
+
+// AddFaultNote handles adding a note to a fault
+func AddFaultNote(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		faultID := c.Param("faultId")
+
+		var req struct {
+			Author  string `json:"author"`
+			Text    string `json:"text"`
+			ReplyTo string `json:"replyTo"`
+		}
+
+		err := c.ShouldBindJSON(&req)
+		req.Author = auth.ActorName(c, req.Author)
+		if err != nil || req.Author == "" || req.Text == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Author and text are required",
+			})
+			return
+		}
+
+		note, err := appContext.AddFaultNote(faultID, req.Author, req.Text, req.ReplyTo)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to add note",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Note added successfully",
+			"note":    note,
+		})
+	}
+}
+
+// AcknowledgeIncident handles acknowledgment of a whole fault group
+func AcknowledgeIncident(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		incidentID := c.Param("incidentId")
+		if incidentID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Incident ID is required",
+			})
+			return
+		}
+
+		var req struct {
+			AcknowledgedBy string `json:"acknowledgedBy"`
+			Notes          string `json:"notes"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+		req.AcknowledgedBy = auth.ActorName(c, req.AcknowledgedBy)
+
+		err := appContext.AcknowledgeIncident(auth.Tenant(c), incidentID, req.AcknowledgedBy)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to acknowledge incident",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Incident acknowledged successfully",
+		})
+	}
+}
+
+// ResolveIncident handles resolution of a whole fault group
+func ResolveIncident(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		incidentID := c.Param("incidentId")
+		if incidentID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Incident ID is required",
+			})
+			return
+		}
+
+		var req struct {
+			ResolvedBy string `json:"resolvedBy"`
+			Category   string `json:"category"`
+			Resolution string `json:"resolution"`
+			Notes      string `json:"notes"`
+		}
+
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+		req.ResolvedBy = auth.ActorName(c, req.ResolvedBy)
+
+		resolved, err := appContext.ResolveIncident(auth.Tenant(c), incidentID, req.ResolvedBy, req.Category, req.Resolution)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to resolve incident",
+			})
+			return
+		}
+
+		if req.Notes != "" {
+			for _, faultID := range resolved {
+				appContext.AddFaultNote(faultID, req.ResolvedBy, req.Notes, "")
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success":        true,
+			"message":        "Incident resolved successfully",
+			"resolvedFaults": len(resolved),
+		})
+	}
+}
+
 // RecentFaults returns recent faults for AJAX updates
 func RecentFaults(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -333,7 +624,7 @@ func RecentFaults(appContext *context.Context) gin.HandlerFunc {
 			}
 		}
 
-		faults := appContext.GetActiveFaults()
+		faults := appContext.FilterTenantFaults(auth.Tenant(c), appContext.GetActiveFaults())
 		if len(faults) > limit {
 			faults = faults[:limit]
 		}
@@ -410,81 +701,6 @@ func DeleteDeviceFilter(appContext *context.Context) gin.HandlerFunc {
 }
 
 // WebSocketHandler handles WebSocket connections for real-time updates
-func WebSocketHandler(appContext *context.Context) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-		if err != nil {
-			logger.WebLog.Errorf("WebSocket upgrade failed: %v", err)
-			return
-		}
-		defer conn.Close()
-
-		// Send initial connection message
-		conn.WriteJSON(gin.H{
-			"type":    "connected",
-			"message": "WebSocket connection established",
-		})
-
-		// Create a ticker for periodic updates
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-
-		// Channel for client messages
-		clientMsg := make(chan []byte, 10)
-		go func() {
-			for {
-				_, msg, err := conn.ReadMessage()
-				if err != nil {
-					if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-						logger.WebLog.Errorf("WebSocket read error: %v", err)
-					}
-					close(clientMsg)
-					return
-				}
-				clientMsg <- msg
-			}
-		}()
-
-		for {
-			select {
-			case <-ticker.C:
-				// Send periodic updates
-				stats := appContext.GetDeviceStats()
-				genieStatus := appContext.GetGenieACSStatus()
-
-				update := gin.H{
-					"type": "stats_update",
-					"data": gin.H{
-						"devices": gin.H{
-							"total":   stats.TotalDevices,
-							"online":  stats.OnlineDevices,
-							"offline": stats.OfflineDevices,
-						},
-						"faults": gin.H{
-							"active":   stats.ActiveFaults,
-							"critical": stats.CriticalFaults,
-						},
-						"system": gin.H{
-							"cwmpConnected": genieStatus.CWMPConnected,
-							"nbiConnected":  genieStatus.NBIConnected,
-							"fsConnected":   genieStatus.FSConnected,
-						},
-						"timestamp": time.Now().UTC().Format(time.RFC3339),
-					},
-				}
-
-				if err := conn.WriteJSON(update); err != nil {
-					logger.WebLog.Errorf("WebSocket write error: %v", err)
-					return
-				}
-
-			case msg, ok := <-clientMsg:
-				if !ok {
-					return
-				}
-				// Handle client messages (e.g., subscription requests)
-				logger.WebLog.Debugf("Received WebSocket message: %s", string(msg))
-			}
-		}
-	}
+func WebSocketHandler(appContext *context.Context, allowedOrigins []string) gin.HandlerFunc {
+	return realtime.WebSocketHandler(appContext, allowedOrigins, logger.WebLog)
 }
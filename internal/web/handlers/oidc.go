@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+const (
+	// oidcStateCookieName binds a login in progress to the browser that
+	// started it
+	oidcStateCookieName = "ntg_oidc_state"
+
+	// oidcLoginTimeout limits how long a user may take at the provider
+	oidcLoginTimeout = 10 * time.Minute
+
+	// maxPendingOIDCLogins bounds the logins in progress, starting one
+	// needs no credentials
+	maxPendingOIDCLogins = 10000
+
+	// oidcKeyRefreshInterval limits fetching the provider keys when tokens
+	// name unknown keys
+	oidcKeyRefreshInterval = time.Minute
+)
+
+// OIDCProvider logs users in via an OpenID Connect provider with the
+// authorization code flow and PKCE. The endpoints of the provider are
+// discovered on the first login, so the gateway starts while the provider
+// is unreachable.
+type OIDCProvider struct {
+	cfg    *config.OIDC
+	client *http.Client
+
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+	keysRefresh chan struct{} // closed when the keys being fetched are stored
+	pending     map[string]*oidcLogin
+}
+
+// oidcDiscovery is the part of the provider metadata the login needs
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcLogin is a login waiting for the provider to redirect back
+type oidcLogin struct {
+	verifier  string
+	nonce     string
+	next      string
+	expiresAt time.Time
+}
+
+// oidcTokenResponse is the response of the token endpoint
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// NewOIDCProvider creates the single sign-on login of a provider
+func NewOIDCProvider(cfg *config.OIDC) *OIDCProvider {
+	return &OIDCProvider{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		pending: make(map[string]*oidcLogin),
+	}
+}
+
+// Name returns the label of the login button, empty without a provider
+func (p *OIDCProvider) Name() string {
+	if p == nil {
+		return ""
+	}
+	return p.cfg.Name
+}
+
+// OIDCLogin sends the browser to the provider to log in
+func OIDCLogin(provider *OIDCProvider, cookie *SessionCookie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		next := safeRedirect(c.Query("next"))
+
+		authURL, state, err := provider.start(next)
+		if err != nil {
+			logger.WebLog.Errorf("Failed to start single sign-on: %v", err)
+			renderLogin(c, http.StatusServiceUnavailable, provider, next, "", "Single sign-on is unavailable, please try again later")
+			return
+		}
+
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(oidcStateCookieName, state, int(oidcLoginTimeout.Seconds()), "/login/oidc", "", cookie.secure, true)
+		c.Redirect(http.StatusFound, authURL)
+	}
+}
+
+// OIDCCallback completes a login when the provider redirects back. The
+// user gets the role of its groups and a session like a local user.
+func OIDCCallback(appContext *context.Context, provider *OIDCProvider, cookie *SessionCookie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := c.Query("state")
+		cookieState, _ := c.Cookie(oidcStateCookieName)
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(oidcStateCookieName, "", -1, "/login/oidc", "", cookie.secure, true)
+
+		login, ok := provider.finish(state)
+		if !ok || cookieState != state {
+			auditOIDCLogin(c, appContext, "", http.StatusBadRequest, "unknown or expired state")
+			renderLogin(c, http.StatusBadRequest, provider, "/overview", "", "Single sign-on expired, please try again")
+			return
+		}
+
+		if providerError := c.Query("error"); providerError != "" {
+			logger.WebLog.Warnf("Single sign-on refused by provider: %s %s", providerError, c.Query("error_description"))
+			auditOIDCLogin(c, appContext, "", http.StatusUnauthorized, "refused by provider: "+providerError)
+			renderLogin(c, http.StatusUnauthorized, provider, login.next, "", "Single sign-on was refused")
+			return
+		}
+
+		claims, err := provider.exchange(c.Query("code"), login)
+		if err != nil {
+			logger.WebLog.Warnf("Single sign-on from %s failed: %v", c.ClientIP(), err)
+			auditOIDCLogin(c, appContext, "", http.StatusUnauthorized, err.Error())
+			renderLogin(c, http.StatusUnauthorized, provider, login.next, "", "Single sign-on failed")
+			return
+		}
+
+		username, _ := claims[provider.cfg.UsernameClaim].(string)
+		subject, _ := claims["sub"].(string)
+		if username == "" || subject == "" {
+			logger.WebLog.Warnf("Single sign-on ID token lacks the %s or sub claim", provider.cfg.UsernameClaim)
+			auditOIDCLogin(c, appContext, username, http.StatusUnauthorized, "ID token lacks the "+provider.cfg.UsernameClaim+" or sub claim")
+			renderLogin(c, http.StatusUnauthorized, provider, login.next, "", "Single sign-on failed")
+			return
+		}
+
+		role, tenant, ok := provider.role(claims[provider.cfg.GroupsClaim])
+		if !ok {
+			logger.WebLog.Warnf("Single sign-on user %s has no group mapped to a role", username)
+			auditOIDCLogin(c, appContext, username, http.StatusForbidden, "no group mapped to a role")
+			renderLogin(c, http.StatusForbidden, provider, login.next, "", "You are not allowed to use the gateway")
+			return
+		}
+
+		user, err := appContext.LoginExternalUser(models.UserProviderOIDC, subject, username, role, tenant)
+		if err != nil {
+			logger.WebLog.Warnf("Single sign-on user %s refused: %v", username, err)
+			auditOIDCLogin(c, appContext, username, http.StatusForbidden, err.Error())
+			message := "Single sign-on failed"
+			if errors.Is(err, models.ErrUserExists) {
+				message = "The username belongs to another account"
+			}
+			renderLogin(c, http.StatusForbidden, provider, login.next, "", message)
+			return
+		}
+
+		// A new session ID on every login prevents session fixation
+		if sessionID, ok := cookie.Get(c); ok {
+			appContext.RemoveSession(sessionID)
+		}
+		session, err := appContext.CreateSession(user.ID, c.ClientIP())
+		if err != nil {
+			logger.WebLog.Errorf("Failed to create session: %v", err)
+			auditOIDCLogin(c, appContext, user.Username, http.StatusInternalServerError, err.Error(), user.ID)
+			renderLogin(c, http.StatusInternalServerError, provider, login.next, "", "Login failed, please try again")
+			return
+		}
+		cookie.Set(c, session)
+
+		logger.WebLog.Infof("User %s logged in via single sign-on from %s with role %s", user.Username, c.ClientIP(), user.Role)
+		auditOIDCLogin(c, appContext, user.Username, http.StatusSeeOther, "", user.ID)
+		c.Redirect(http.StatusSeeOther, login.next)
+	}
+}
+
+// auditOIDCLogin records the outcome of a single sign-on in the audit log.
+// The audit middleware skips GET requests, so the callback records its
+// logins itself.
+func auditOIDCLogin(c *gin.Context, appContext *context.Context, username string, status int, reason string, targets ...string) {
+	if username == "" {
+		username = "anonymous"
+	}
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	record := &models.AuditRecord{
+		Timestamp:  time.Now(),
+		Actor:      username,
+		AuthMethod: models.AuthMethodOIDC,
+		ClientIP:   c.ClientIP(),
+		RequestID:  requestID,
+		Source:     models.AuditSourceUI,
+		Action:     c.Request.Method + " " + c.FullPath(),
+		Targets:    targets,
+		Status:     status,
+		Outcome:    audit.Outcome(status),
+		Error:      reason,
+	}
+	if err := appContext.AppendAuditRecord(record); err != nil {
+		logger.AuditLog.Errorf("Failed to record %s by %s: %v", record.Action, record.Actor, err)
+	}
+}
+
+// start records a login and returns the authorization URL and state
+func (p *OIDCProvider) start(next string) (string, string, error) {
+	discovery, err := p.discover()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	for id, login := range p.pending {
+		if now.After(login.expiresAt) {
+			delete(p.pending, id)
+		}
+	}
+	if len(p.pending) >= maxPendingOIDCLogins {
+		p.mu.Unlock()
+		return "", "", errors.New("too many logins in progress")
+	}
+	p.pending[state] = &oidcLogin{
+		verifier:  verifier,
+		nonce:     nonce,
+		next:      next,
+		expiresAt: now.Add(oidcLoginTimeout),
+	}
+	p.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, p.cfg.Scopes...), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), state, nil
+}
+
+// finish removes a login in progress, each state can be used once
+func (p *OIDCProvider) finish(state string) (*oidcLogin, bool) {
+	if state == "" {
+		return nil, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	login, exists := p.pending[state]
+	delete(p.pending, state)
+	if !exists || time.Now().After(login.expiresAt) {
+		return nil, false
+	}
+	return login, true
+}
+
+// exchange redeems an authorization code and returns the verified claims
+// of the ID token
+func (p *OIDCProvider) exchange(code string, login *oidcLogin) (jwt.MapClaims, error) {
+	if code == "" {
+		return nil, errors.New("no authorization code")
+	}
+	discovery, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {login.verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token oidcTokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("token request refused: %d %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no ID token")
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if _, err := parser.ParseWithClaims(token.IDToken, claims, p.verificationKey); err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+	if nonce, _ := claims["nonce"].(string); nonce != login.nonce {
+		return nil, errors.New("ID token nonce does not match")
+	}
+	if azp, exists := claims["azp"].(string); exists && azp != p.cfg.ClientID {
+		return nil, fmt.Errorf("ID token issued to %s", azp)
+	}
+	return claims, nil
+}
+
+// role returns the role and tenant of the first mapped group, or the
+// defaults
+func (p *OIDCProvider) role(claim interface{}) (string, string, bool) {
+	var groups []string
+	switch value := claim.(type) {
+	case string:
+		groups = []string{value}
+	case []interface{}:
+		for _, item := range value {
+			if group, ok := item.(string); ok {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	for _, mapping := range p.cfg.RoleMappings {
+		for _, group := range groups {
+			if group == mapping.Group {
+				return mapping.Role, mapping.Tenant, true
+			}
+		}
+	}
+	return p.cfg.DefaultRole, p.cfg.DefaultTenant, p.cfg.DefaultRole != ""
+}
+
+// discover fetches the provider metadata once
+func (p *OIDCProvider) discover() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	discovery := p.discovery
+	p.mu.Unlock()
+	if discovery != nil {
+		return discovery, nil
+	}
+
+	discovery = &oidcDiscovery{}
+	if err := p.getJSON(strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, fmt.Errorf("discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.cfg.Issuer, "/") {
+		return nil, fmt.Errorf("discovery names issuer %s", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("discovery lacks endpoints")
+	}
+
+	p.mu.Lock()
+	p.discovery = discovery
+	p.mu.Unlock()
+	logger.WebLog.Infof("Discovered OpenID Connect provider %s", discovery.Issuer)
+	return discovery, nil
+}
+
+// verificationKey returns the provider key that signed a token. The keys
+// are fetched again when a token names an unknown one, so the provider can
+// rotate them. The fetch runs without holding mu so a slow provider does
+// not hold up starting and finishing other logins, concurrent lookups wait
+// for the fetch in progress instead.
+func (p *OIDCProvider) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	if key := lookupKey(p.keys, kid); key != nil {
+		p.mu.Unlock()
+		return key, nil
+	}
+
+	if refresh := p.keysRefresh; refresh != nil {
+		p.mu.Unlock()
+		<-refresh
+		p.mu.Lock()
+		key := lookupKey(p.keys, kid)
+		p.mu.Unlock()
+		if key != nil {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+
+	if time.Since(p.keysFetched) < oidcKeyRefreshInterval {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	refresh := make(chan struct{})
+	p.keysRefresh = refresh
+	p.keysFetched = time.Now()
+	jwksURI := p.discovery.JWKSURI
+	p.mu.Unlock()
+
+	keys, err := p.fetchKeys(jwksURI)
+
+	p.mu.Lock()
+	if err == nil {
+		p.keys = keys
+	}
+	p.keysRefresh = nil
+	close(refresh)
+	p.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys: %w", err)
+	}
+	if key := lookupKey(keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key ID %q", kid)
+}
+
+// fetchKeys fetches the RSA signing keys of the provider
+func (p *OIDCProvider) fetchKeys(jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// getJSON fetches and decodes a JSON document of the provider
+func (p *OIDCProvider) getJSON(rawURL string, v interface{}) error {
+	resp, err := p.client.Get(rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", rawURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// lookupKey finds a key by ID, or the only key when the token names none
+func lookupKey(keys map[string]*rsa.PublicKey, kid string) *rsa.PublicKey {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+// randomString returns 256 random bits, URL-safe encoded. PKCE verifiers
+// need at least 43 characters, this gives exactly that.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+const (
+	testClientID    = "nextranet-gateway"
+	testRedirectURL = "https://gateway.example.com/login/oidc/callback"
+	testKeyID       = "k1"
+)
+
+// mockIdP is an OpenID Connect provider serving discovery, the token
+// endpoint with PKCE and its signing keys
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]*mockGrant
+
+	// signingKey signs ID tokens, the published key unless a test forges
+	// tokens
+	signingKey *rsa.PrivateKey
+
+	// nonce replaces the nonce of the login in ID tokens when set
+	nonce string
+
+	// jwksHold delays the key set until closed when set
+	jwksHold chan struct{}
+}
+
+// mockGrant is an authorization code issued by the provider
+type mockGrant struct {
+	challenge string
+	nonce     string
+	subject   string
+	username  string
+	groups    []string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, signingKey: key, grants: make(map[string]*mockGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/token", idp.token)
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		hold := idp.jwksHold
+		idp.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": testKeyID,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize plays the user logging in at the provider and returns the
+// authorization code
+func (idp *mockIdP) authorize(t *testing.T, authURL, username string, groups ...string) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != testClientID ||
+		query.Get("redirect_uri") != testRedirectURL || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization request %s", authURL)
+	}
+	if query.Get("code_challenge") == "" || query.Get("nonce") == "" {
+		t.Fatalf("authorization request without PKCE challenge or nonce")
+	}
+
+	code := "code-" + username + "-" + query.Get("state")[:8]
+	idp.mu.Lock()
+	idp.grants[code] = &mockGrant{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		subject:   "sub-" + username,
+		username:  username,
+		groups:    groups,
+	}
+	idp.mu.Unlock()
+	return code
+}
+
+// token redeems an authorization code after checking the PKCE verifier
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	idp.mu.Lock()
+	grant, exists := idp.grants[r.PostForm.Get("code")]
+	delete(idp.grants, r.PostForm.Get("code"))
+	signingKey, nonce := idp.signingKey, idp.nonce
+	idp.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !exists || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != testClientID || r.PostForm.Get("redirect_uri") != testRedirectURL ||
+		base64.RawURLEncoding.EncodeToString(verifier[:]) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	if nonce == "" {
+		nonce = grant.nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                idp.server.URL,
+		"aud":                testClientID,
+		"sub":                grant.subject,
+		"preferred_username": grant.username,
+		"groups":             grant.groups,
+		"nonce":              nonce,
+		"iat":                time.Now().Unix(),
+		"exp":                time.Now().Add(5 * time.Minute).Unix(),
+	})
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(signingKey)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+// oidcFixture is a gateway UI with single sign-on against a mock provider
+type oidcFixture struct {
+	idp        *mockIdP
+	provider   *OIDCProvider
+	appContext *context.Context
+	router     *gin.Engine
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	idp := newMockIdP(t)
+	provider := NewOIDCProvider(&config.OIDC{
+		Enabled:       true,
+		Name:          "Test SSO",
+		Issuer:        idp.server.URL,
+		ClientID:      testClientID,
+		RedirectURL:   testRedirectURL,
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+		RoleMappings: []config.OIDCRoleMapping{
+			{Group: "noc-admins", Role: models.RoleAdmin},
+			{Group: "noc", Role: models.RoleOperator},
+		},
+	})
+	cookie, err := NewSessionCookie(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appContext := context.NewContext()
+	router := gin.New()
+	router.GET("/login/oidc", OIDCLogin(provider, cookie))
+	router.GET("/login/oidc/callback", OIDCCallback(appContext, provider, cookie))
+	return &oidcFixture{idp: idp, provider: provider, appContext: appContext, router: router}
+}
+
+// start begins a login and returns the authorization URL and the state
+// cookie
+func (f *oidcFixture) start(t *testing.T) (string, *http.Cookie) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/login/oidc?next=/devices", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("login start returned %d, want %d", w.Code, http.StatusFound)
+	}
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == oidcStateCookieName {
+			return w.Header().Get("Location"), cookie
+		}
+	}
+	t.Fatal("login start set no state cookie")
+	return "", nil
+}
+
+// callback returns to the gateway from the provider
+func (f *oidcFixture) callback(code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/login/oidc/callback?"+url.Values{"code": {code}, "state": {state}}.Encode(), nil)
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+// login runs a complete login of a user with groups
+func (f *oidcFixture) login(t *testing.T, username string, groups ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	authURL, stateCookie := f.start(t)
+	code := f.idp.authorize(t, authURL, username, groups...)
+	return f.callback(code, stateCookie.Value, stateCookie)
+}
+
+// user finds a user by name
+func (f *oidcFixture) user(username string) *models.User {
+	for _, user := range f.appContext.GetUsers() {
+		if user.Username == username {
+			return user
+		}
+	}
+	return nil
+}
+
+func TestOIDCLogin(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	w := f.login(t, "alice", "staff", "noc")
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/devices" {
+		t.Fatalf("callback returned %d to %q, want %d to /devices: %s", w.Code, w.Header().Get("Location"), http.StatusSeeOther, w.Body.String())
+	}
+	sessionSet := false
+	for _, cookie := range w.Result().Cookies() {
+		sessionSet = sessionSet || (cookie.Name == SessionCookieName && cookie.Value != "")
+	}
+	if !sessionSet {
+		t.Error("callback set no session cookie")
+	}
+
+	user := f.user("alice")
+	if user == nil {
+		t.Fatal("user alice was not created")
+	}
+	if user.Role != models.RoleOperator || user.Provider != models.UserProviderOIDC || user.Subject != "sub-alice" {
+		t.Errorf("user alice has role %s via %s/%s, want %s via %s/sub-alice",
+			user.Role, user.Provider, user.Subject, models.RoleOperator, models.UserProviderOIDC)
+	}
+}
+
+func TestOIDCRoleMapping(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	// The first mapping the user has a group of wins
+	if w := f.login(t, "bob", "noc", "noc-admins"); w.Code != http.StatusSeeOther {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body.String())
+	}
+	if user := f.user("bob"); user == nil || user.Role != models.RoleAdmin {
+		t.Fatalf("bob has %+v, want role %s", user, models.RoleAdmin)
+	}
+
+	// The role follows the groups on every login
+	if w := f.login(t, "bob", "noc"); w.Code != http.StatusSeeOther {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body.String())
+	}
+	if user := f.user("bob"); user.Role != models.RoleOperator {
+		t.Errorf("bob has role %s after losing noc-admins, want %s", user.Role, models.RoleOperator)
+	}
+
+	// Without a mapped group or default role the user is refused
+	if w := f.login(t, "carol", "staff"); w.Code != http.StatusForbidden {
+		t.Errorf("unmapped user got %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if f.user("carol") != nil {
+		t.Error("unmapped user was created")
+	}
+
+	f.provider.cfg.DefaultRole = models.RoleViewer
+	if w := f.login(t, "carol", "staff"); w.Code != http.StatusSeeOther {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body.String())
+	}
+	if user := f.user("carol"); user == nil || user.Role != models.RoleViewer {
+		t.Errorf("carol has %+v, want the default role %s", user, models.RoleViewer)
+	}
+}
+
+func TestOIDCLoginAudited(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	// Logins that fail, from the first to the last check of the callback
+	if w := f.callback("code", "forged-state", &http.Cookie{Name: oidcStateCookieName, Value: "forged-state"}); w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown state got %d", w.Code)
+	}
+	if w := f.login(t, "carol", "staff"); w.Code != http.StatusForbidden {
+		t.Fatalf("unmapped user got %d", w.Code)
+	}
+	if w := f.login(t, "alice", "noc"); w.Code != http.StatusSeeOther {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body.String())
+	}
+
+	records := f.appContext.GetAuditRecords(models.AuditFilter{})
+	if len(records) != 3 {
+		t.Fatalf("%d logins recorded, want 3", len(records))
+	}
+	tests := []struct {
+		actor   string
+		status  int
+		outcome string
+	}{
+		{"alice", http.StatusSeeOther, models.AuditOutcomeSuccess},
+		{"carol", http.StatusForbidden, models.AuditOutcomeDenied},
+		{"anonymous", http.StatusBadRequest, models.AuditOutcomeFailure},
+	}
+	for i, tt := range tests {
+		record := records[i]
+		if record.Actor != tt.actor || record.Status != tt.status || record.Outcome != tt.outcome {
+			t.Errorf("record %d is %s %d %s, want %s %d %s", i, record.Actor, record.Status, record.Outcome, tt.actor, tt.status, tt.outcome)
+		}
+		if record.Action != "GET /login/oidc/callback" || record.AuthMethod != models.AuthMethodOIDC || record.Source != models.AuditSourceUI {
+			t.Errorf("record %d is %s via %s from %s", i, record.Action, record.AuthMethod, record.Source)
+		}
+		if tt.outcome != models.AuditOutcomeSuccess && record.Error == "" {
+			t.Errorf("record %d of a failed login has no error", i)
+		}
+	}
+	if user := f.user("alice"); user == nil || len(records[0].Targets) != 1 || records[0].Targets[0] != user.ID {
+		t.Errorf("login recorded targets %v, want the user", records[0].Targets)
+	}
+}
+
+func TestOIDCBadState(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	authURL, stateCookie := f.start(t)
+	code := f.idp.authorize(t, authURL, "alice", "noc")
+
+	// A state that was never issued
+	if w := f.callback(code, "forged-state", &http.Cookie{Name: oidcStateCookieName, Value: "forged-state"}); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown state got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// A state issued to another browser
+	otherURL, _ := f.start(t)
+	otherState, _ := url.Parse(otherURL)
+	if w := f.callback(code, otherState.Query().Get("state"), stateCookie); w.Code != http.StatusBadRequest {
+		t.Errorf("state of another browser got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// The real state works once
+	if w := f.callback(code, stateCookie.Value, stateCookie); w.Code != http.StatusSeeOther {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body.String())
+	}
+	if w := f.callback(code, stateCookie.Value, stateCookie); w.Code != http.StatusBadRequest {
+		t.Errorf("replayed state got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestOIDCBadPKCEVerifier(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	authURL, stateCookie := f.start(t)
+	code := f.idp.authorize(t, authURL, "alice", "noc")
+
+	// A code intercepted for another login has a different challenge
+	f.idp.mu.Lock()
+	f.idp.grants[code].challenge = "intercepted"
+	f.idp.mu.Unlock()
+
+	if w := f.callback(code, stateCookie.Value, stateCookie); w.Code != http.StatusUnauthorized {
+		t.Errorf("callback with a mismatching verifier got %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if f.user("alice") != nil {
+		t.Error("user was created without a valid code exchange")
+	}
+}
+
+func TestOIDCBadNonce(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.idp.nonce = "nonce-of-another-login"
+
+	if w := f.login(t, "alice", "noc"); w.Code != http.StatusUnauthorized {
+		t.Errorf("ID token with a foreign nonce got %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if f.user("alice") != nil {
+		t.Error("user was created from a token with a foreign nonce")
+	}
+}
+
+func TestOIDCBadSignature(t *testing.T) {
+	f := newOIDCFixture(t)
+	forger, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.idp.signingKey = forger
+
+	if w := f.login(t, "alice", "noc-admins"); w.Code != http.StatusUnauthorized {
+		t.Errorf("forged ID token got %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if f.user("alice") != nil {
+		t.Error("user was created from a forged token")
+	}
+}
+
+// TestOIDCSlowKeyFetch checks that logins can start and finish while the
+// keys of the provider are being fetched
+func TestOIDCSlowKeyFetch(t *testing.T) {
+	f := newOIDCFixture(t)
+	hold := make(chan struct{})
+	f.idp.jwksHold = hold
+
+	authURL, stateCookie := f.start(t)
+	code := f.idp.authorize(t, authURL, "alice", "noc")
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		done <- f.callback(code, stateCookie.Value, stateCookie)
+	}()
+
+	// Wait until the callback fetches the keys
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f.provider.mu.Lock()
+		fetching := f.provider.keysRefresh != nil
+		f.provider.mu.Unlock()
+		if fetching {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("callback did not fetch the keys")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	started := make(chan error, 1)
+	go func() {
+		_, state, err := f.provider.start("/overview")
+		f.provider.finish(state)
+		started <- err
+	}()
+	select {
+	case err := <-started:
+		if err != nil {
+			t.Errorf("starting a login failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("starting a login waited for the key fetch")
+	}
+
+	close(hold)
+	if w := <-done; w.Code != http.StatusSeeOther {
+		t.Fatalf("callback returned %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
 	"github.com/nextranet/gateway/c-plane/internal/models"
@@ -135,12 +136,20 @@ func Overview(appContext *context.Context) gin.HandlerFunc {
 			logger.WebLog.Infof("Added test data: %d devices, %d faults", len(testDevices), len(testFaults))
 		}
 
+		// Statistics and faults are those of the tenant of the user
+		tenant := auth.Tenant(c)
+		deviceStats = appContext.GetTenantDeviceStats(tenant)
+
 		// Get active faults
-		faults := appContext.GetActiveFaults()
+		faults := appContext.FilterTenantFaults(tenant, appContext.GetActiveFaults())
 		faultsBySeverity := make(map[string]int)
 		criticalFaults := make([]*models.Fault, 0)
 
 		for _, fault := range faults {
+			// Faults raised during maintenance do not affect health
+			if fault.Suppressed {
+				continue
+			}
 			faultsBySeverity[fault.Severity]++
 			if fault.Severity == models.SeverityCritical {
 				criticalFaults = append(criticalFaults, fault)
@@ -227,8 +236,11 @@ func Overview(appContext *context.Context) gin.HandlerFunc {
 		// Prepare data for template
 		data := templates.OverviewData{
 			BasePageData: templates.BasePageData{
-				Title: "Overview",
-				Theme: theme,
+				Title:       "Overview",
+				Theme:       theme,
+				CurrentPath: "/overview",
+				User:        currentUser(c),
+				CSRFToken:   csrfToken(c),
 			},
 			Stats: templates.OverviewStats{
 				TotalDevices:   deviceStats.TotalDevices,
@@ -268,11 +280,12 @@ func Overview(appContext *context.Context) gin.HandlerFunc {
 // RealtimeStats returns real-time statistics for AJAX updates
 func RealtimeStats(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		stats := appContext.GetDeviceStats()
+		tenant := auth.Tenant(c)
+		stats := appContext.GetTenantDeviceStats(tenant)
 		genieStatus := appContext.GetGenieACSStatus()
 
 		// Calculate current metrics
-		activeFaults := appContext.GetActiveFaults()
+		activeFaults := appContext.FilterTenantFaults(tenant, appContext.GetActiveFaults())
 		criticalCount := 0
 		for _, fault := range activeFaults {
 			if fault.Severity == models.SeverityCritical {
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/web/templates"
+)
+
+// SessionCookieName is the cookie holding the signed session ID
+const SessionCookieName = "ntg_session"
+
+// sessionKey is the gin context key of the session of a request
+const sessionKey = "session"
+
+// SessionCookie signs and verifies session cookies. The key is random per
+// process; sessions are kept in memory and end with it anyway.
+type SessionCookie struct {
+	key    []byte
+	secure bool
+}
+
+// NewSessionCookie creates a session cookie codec. Secure cookies are only
+// sent over HTTPS.
+func NewSessionCookie(secure bool) (*SessionCookie, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &SessionCookie{key: key, secure: secure}, nil
+}
+
+// Set sends the cookie for a session. It lives until the absolute timeout
+// of the session, the idle timeout is enforced by the context.
+func (s *SessionCookie) Set(c *gin.Context, session *models.Session) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookieName, session.ID+"."+s.sign(session.ID),
+		int(time.Until(session.ExpiresAt).Seconds()), "/", "", s.secure, true)
+}
+
+// Get returns the session ID of a request if its signature is valid
+func (s *SessionCookie) Get(c *gin.Context) (string, bool) {
+	value, err := c.Cookie(SessionCookieName)
+	if err != nil {
+		return "", false
+	}
+	id, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(s.sign(id))) {
+		return "", false
+	}
+	return id, true
+}
+
+// Clear removes the cookie from the browser
+func (s *SessionCookie) Clear(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(SessionCookieName, "", -1, "/", "", s.secure, true)
+}
+
+// sign returns the HMAC-SHA256 of a session ID
+func (s *SessionCookie) sign(id string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SetSession records the session of a request
+func SetSession(c *gin.Context, session *models.Session) {
+	c.Set(sessionKey, session)
+}
+
+// GetSession returns the session of a request, there is none while login
+// is disabled
+func GetSession(c *gin.Context) (*models.Session, bool) {
+	value, exists := c.Get(sessionKey)
+	if !exists {
+		return nil, false
+	}
+	session, ok := value.(*models.Session)
+	return session, ok
+}
+
+// csrfToken returns the CSRF token pages send with state-changing requests
+func csrfToken(c *gin.Context) string {
+	if session, ok := GetSession(c); ok {
+		return session.CSRFToken
+	}
+	return ""
+}
+
+// ShowLogin renders the login page, users with a session go straight on.
+// The page offers single sign-on when sso is not nil.
+func ShowLogin(appContext *context.Context, cookie *SessionCookie, sso *OIDCProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		next := safeRedirect(c.Query("next"))
+		if sessionID, ok := cookie.Get(c); ok {
+			if _, err := appContext.TouchSession(sessionID); err == nil {
+				c.Redirect(http.StatusSeeOther, next)
+				return
+			}
+		}
+		renderLogin(c, http.StatusOK, sso, next, "", "")
+	}
+}
+
+// Login checks the credentials of the login form and starts a session
+func Login(appContext *context.Context, cookie *SessionCookie, sso *OIDCProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		username := strings.TrimSpace(c.PostForm("username"))
+		password := c.PostForm("password")
+		next := safeRedirect(c.PostForm("next"))
+		audit.SetActor(c, username)
+
+		user, err := appContext.AuthenticateUser(username, password)
+		if err != nil {
+			logger.WebLog.Warnf("Failed login for %q from %s: %v", username, c.ClientIP(), err)
+			message := "Invalid username or password"
+			if errors.Is(err, models.ErrUserLocked) {
+				message = "Too many failed logins, try again later"
+			}
+			renderLogin(c, http.StatusUnauthorized, sso, next, username, message)
+			return
+		}
+
+		// A new session ID on every login prevents session fixation
+		if sessionID, ok := cookie.Get(c); ok {
+			appContext.RemoveSession(sessionID)
+		}
+		session, err := appContext.CreateSession(user.ID, c.ClientIP())
+		if err != nil {
+			logger.WebLog.Errorf("Failed to create session: %v", err)
+			renderLogin(c, http.StatusInternalServerError, sso, next, username, "Login failed, please try again")
+			return
+		}
+		cookie.Set(c, session)
+
+		logger.WebLog.Infof("User %s logged in from %s", user.Username, c.ClientIP())
+		c.Redirect(http.StatusSeeOther, next)
+	}
+}
+
+// Logout ends the session of the request
+func Logout(appContext *context.Context, cookie *SessionCookie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if session, ok := GetSession(c); ok {
+			appContext.RemoveSession(session.ID)
+		}
+		cookie.Clear(c)
+		c.Redirect(http.StatusSeeOther, "/login")
+	}
+}
+
+// renderLogin renders the login form
+func renderLogin(c *gin.Context, status int, sso *OIDCProvider, next, username, message string) {
+	theme := c.GetString("theme")
+	if theme == "" {
+		theme = "dark"
+	}
+
+	data := templates.LoginPageData{
+		Title:    "Login",
+		Theme:    theme,
+		Next:     next,
+		Username: username,
+		Error:    message,
+		SSOName:  sso.Name(),
+	}
+
+	component := templates.LoginPage(data)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+
+	if err := component.Render(c.Request.Context(), c.Writer); err != nil {
+		logger.WebLog.Errorf("Failed to render login page: %v", err)
+		c.String(http.StatusInternalServerError, "Failed to render page")
+	}
+}
+
+// safeRedirect keeps redirects after login on the UI, an open redirect
+// would help phishing
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/overview"
+	}
+	return next
+}
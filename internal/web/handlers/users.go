@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/web/templates"
+)
+
+// userRequest is the request body for creating and updating a user. The
+// password is optional on updates.
+type userRequest struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Tenant   string `json:"tenant"`
+	Disabled bool   `json:"disabled"`
+	Password string `json:"password"`
+}
+
+// Users renders the user management page
+func Users(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users := visibleUsers(c, appContext.GetUsers())
+
+		// Get theme
+		theme := c.GetString("theme")
+		if theme == "" {
+			theme = "dark"
+		}
+
+		// Prepare data for template
+		data := templates.UsersPageData{
+			BasePageData: templates.BasePageData{
+				Title:       "Users",
+				Theme:       theme,
+				CurrentPath: "/users",
+				User:        currentUser(c),
+				CSRFToken:   csrfToken(c),
+			},
+			Users: users,
+			Roles: appContext.GetRoles(),
+		}
+		if auth.Tenant(c) == "" {
+			data.Tenants = appContext.GetTenants()
+		}
+
+		if session, ok := GetSession(c); ok {
+			data.CurrentUserID = session.UserID
+		}
+
+		// Render the users page
+		component := templates.UsersPage(data)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+
+		if err := component.Render(c.Request.Context(), c.Writer); err != nil {
+			logger.WebLog.Errorf("Failed to render users page: %v", err)
+			c.String(http.StatusInternalServerError, "Failed to render page")
+			return
+		}
+	}
+}
+
+// GetUsers returns the users visible to the caller without their password
+// hashes
+func GetUsers(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users := visibleUsers(c, appContext.GetUsers())
+
+		redacted := make([]*models.User, 0, len(users))
+		for _, user := range users {
+			redacted = append(redacted, user.Redacted())
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"users": redacted,
+			"total": len(redacted),
+		})
+	}
+}
+
+// CreateUser creates a local user
+func CreateUser(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req userRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		// Users created by a tenant admin belong to the same tenant
+		if req.Tenant == "" {
+			req.Tenant = auth.Tenant(c)
+		}
+		if !auth.CanAssignTenant(c, req.Tenant) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "You cannot create users of another tenant",
+			})
+			return
+		}
+
+		user := &models.User{
+			Username:  req.Username,
+			Role:      req.Role,
+			Tenant:    req.Tenant,
+			Disabled:  req.Disabled,
+			CreatedBy: auth.ActorName(c, ""),
+		}
+		if err := appContext.AddUser(user, req.Password); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, models.ErrUserExists) {
+				status = http.StatusConflict
+			}
+			c.JSON(status, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		audit.AddTargets(c, user.ID)
+		audit.RecordChange(c, user.ID, "role", nil, user.Role)
+		if user.Tenant != "" {
+			audit.RecordChange(c, user.ID, "tenant", nil, user.Tenant)
+		}
+		logger.WebLog.Infof("User %s created user %s with role %s", auth.ActorName(c, "anonymous"), user.Username, user.Role)
+
+		c.JSON(http.StatusCreated, gin.H{
+			"success": true,
+			"message": "User created successfully",
+			"user":    user.Redacted(),
+		})
+	}
+}
+
+// UpdateUser changes the role, tenant, disabled flag or password of a user.
+// Users cannot change their own role or tenant or disable themselves, so
+// the last admin cannot lock everyone out.
+func UpdateUser(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("userId")
+
+		var req userRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		existing, exists := appContext.GetUser(userID)
+		if !exists || !userVisible(c, existing) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		if auth.Tenant(c) != "" {
+			req.Tenant = auth.Tenant(c)
+		}
+		if isCurrentUser(c, userID) && (req.Role != existing.Role || req.Tenant != existing.Tenant || req.Disabled) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "You cannot change your own role or tenant or disable yourself",
+			})
+			return
+		}
+
+		audit.RecordChange(c, userID, "role", existing.Role, req.Role)
+		if req.Tenant != existing.Tenant {
+			audit.RecordChange(c, userID, "tenant", existing.Tenant, req.Tenant)
+		}
+		audit.RecordChange(c, userID, "disabled", existing.Disabled, req.Disabled)
+		if req.Password != "" {
+			audit.RecordChange(c, userID, "password", nil, "changed")
+		}
+
+		user, err := appContext.UpdateUser(userID, req.Role, req.Tenant, req.Disabled, req.Password)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, models.ErrUserNotFound) {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		logger.WebLog.Infof("User %s updated user %s", auth.ActorName(c, "anonymous"), user.Username)
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "User updated successfully",
+			"user":    user.Redacted(),
+		})
+	}
+}
+
+// DeleteUser deletes a local user and ends its sessions
+func DeleteUser(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("userId")
+
+		if isCurrentUser(c, userID) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "You cannot delete yourself",
+			})
+			return
+		}
+
+		if existing, exists := appContext.GetUser(userID); !exists || !userVisible(c, existing) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		if err := appContext.RemoveUser(userID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+
+		logger.WebLog.Infof("User %s deleted user %s", auth.ActorName(c, "anonymous"), userID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "User deleted successfully",
+		})
+	}
+}
+
+// ChangePassword changes the password of the logged in user. All of its
+// sessions end, including the current one.
+func ChangePassword(appContext *context.Context) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, ok := GetSession(c)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Login is disabled",
+			})
+			return
+		}
+
+		var req struct {
+			CurrentPassword string `json:"currentPassword"`
+			NewPassword     string `json:"newPassword"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+			return
+		}
+
+		if err := appContext.ChangeUserPassword(session.UserID, req.CurrentPassword, req.NewPassword); err != nil {
+			status := http.StatusBadRequest
+			message := err.Error()
+			if errors.Is(err, models.ErrInvalidCredentials) {
+				status = http.StatusForbidden
+				message = "Current password is wrong"
+			}
+			c.JSON(status, gin.H{
+				"error": message,
+			})
+			return
+		}
+
+		logger.WebLog.Infof("User %s changed their password", auth.ActorName(c, ""))
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Password changed, please log in again",
+		})
+	}
+}
+
+// isCurrentUser reports whether a user is the one logged in
+func isCurrentUser(c *gin.Context, userID string) bool {
+	session, ok := GetSession(c)
+	return ok && session.UserID == userID
+}
+
+// userVisible reports whether the caller may see and manage a user. Callers
+// limited to a tenant only see the users of that tenant.
+func userVisible(c *gin.Context, user *models.User) bool {
+	tenant := auth.Tenant(c)
+	return tenant == "" || user.Tenant == tenant
+}
+
+// visibleUsers returns the users the caller may see
+func visibleUsers(c *gin.Context, users []*models.User) []*models.User {
+	visible := make([]*models.User, 0, len(users))
+	for _, user := range users {
+		if userVisible(c, user) {
+			visible = append(visible, user)
+		}
+	}
+	return visible
+}
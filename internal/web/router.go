@@ -1,64 +1,136 @@
 package web
 
 import (
-	"fmt"
+	"crypto/subtle"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/audit"
+	"github.com/nextranet/gateway/c-plane/internal/auth"
 	"github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
 	"github.com/nextranet/gateway/c-plane/internal/web/handlers"
 )
 
-// InitRouter initializes the web UI router with all routes
-func InitRouter(router *gin.Engine, appContext *context.Context) {
+// InitRouter initializes the web UI router with all routes. Pages and
+// their API routes require the permissions of the actions they offer.
+// With login enabled they also require a session, and state-changing
+// requests a CSRF token. State-changing requests, logins included, are
+// recorded in the audit log.
+func InitRouter(router *gin.Engine, appContext *context.Context, cfg *config.UI) error {
+	router.Use(audit.Middleware(appContext, models.AuditSourceUI))
+
 	// Static files
 	router.StaticFS("/static", GetStaticFS())
 
+	view := auth.RequirePermission(models.PermView)
+	loginEnabled := cfg.Auth != nil && cfg.Auth.Enabled
+
+	var ui *gin.RouterGroup
+	if loginEnabled {
+		cookie, err := handlers.NewSessionCookie(cfg.Auth.SecureCookie)
+		if err != nil {
+			return err
+		}
+
+		var sso *handlers.OIDCProvider
+		if oidc := cfg.Auth.OIDC; oidc != nil && oidc.Enabled {
+			sso = handlers.NewOIDCProvider(oidc)
+			router.GET("/login/oidc", handlers.OIDCLogin(sso, cookie))
+			router.GET("/login/oidc/callback", handlers.OIDCCallback(appContext, sso, cookie))
+		}
+
+		router.GET("/login", handlers.ShowLogin(appContext, cookie, sso))
+		router.POST("/login", handlers.Login(appContext, cookie, sso))
+
+		ui = router.Group("", SessionMiddleware(appContext, cookie), CSRFMiddleware())
+		ui.POST("/logout", handlers.Logout(appContext, cookie))
+	} else {
+		logger.WebLog.Warnf("UI login is disabled, every visitor gets the %s role", cfg.AnonymousRole)
+		ui = router.Group("", AnonymousMiddleware(appContext, cfg.AnonymousRole))
+	}
+
+	// Devices, faults and incidents outside the tenant of the user are
+	// answered as if they did not exist
+	ui.Use(auth.RequireDeviceAccess(appContext), auth.RequireFaultAccess(appContext), auth.RequireIncidentAccess(appContext))
+
 	// UI routes
-	router.GET("/", handlers.RedirectToOverview())
-	router.GET("/overview", handlers.Overview(appContext))
-	router.GET("/devices", handlers.Devices(appContext))
-	router.GET("/devices/:deviceId", handlers.DeviceDetail(appContext))
-	router.GET("/files", handlers.Files(appContext))
-	router.GET("/faults", handlers.Faults(appContext))
+	ui.GET("/", handlers.RedirectToOverview())
+	ui.GET("/overview", view, handlers.Overview(appContext))
+	ui.GET("/devices", view, handlers.Devices(appContext))
+	ui.GET("/devices/:deviceId", view, handlers.DeviceDetail(appContext))
+	ui.GET("/files", view, handlers.Files(appContext))
+	ui.GET("/faults", view, handlers.Faults(appContext))
 
 	// AJAX/API routes for UI
-	api := router.Group("/api")
+	api := ui.Group("/api", view)
 	{
+		manageDevice := auth.RequirePermission(models.PermDeviceManage)
+		manageFirmware := auth.RequirePermission(models.PermFirmwareManage)
+		acknowledge := auth.RequirePermission(models.PermFaultAcknowledge)
+		resolve := auth.RequirePermission(models.PermFaultResolve)
+
 		// Real-time data endpoints
 		api.GET("/stats/realtime", handlers.RealtimeStats(appContext))
 		api.GET("/devices/status", handlers.DeviceStatusUpdate(appContext))
 		api.GET("/faults/recent", handlers.RecentFaults(appContext))
 
 		// Device operations
-		api.POST("/devices/:deviceId/refresh", handlers.RefreshDevice(appContext))
-		api.POST("/devices/:deviceId/reboot", handlers.RebootDevice(appContext))
+		api.POST("/devices/:deviceId/refresh", manageDevice, handlers.RefreshDevice(appContext))
+		api.POST("/devices/:deviceId/reboot", auth.RequirePermission(models.PermDeviceReboot), handlers.RebootDevice(appContext))
 		api.GET("/devices/:deviceId/config/download", handlers.DownloadConfig(appContext))
-		api.POST("/devices/:deviceId/factory-reset", handlers.FactoryReset(appContext))
-		api.PUT("/devices/:deviceId/parameters", handlers.UpdateParameter(appContext))
-		api.POST("/devices/:deviceId/tags", handlers.AddDeviceTag(appContext))
-		api.DELETE("/devices/:deviceId/tags/:tag", handlers.RemoveDeviceTag(appContext))
+		api.POST("/devices/:deviceId/factory-reset", auth.RequirePermission(models.PermDeviceFactoryReset), handlers.FactoryReset(appContext))
+		api.PUT("/devices/:deviceId/parameters", manageDevice, handlers.UpdateParameter(appContext))
+		api.POST("/devices/:deviceId/tags", manageDevice, handlers.AddDeviceTag(appContext))
+		api.DELETE("/devices/:deviceId/tags/:tag", manageDevice, handlers.RemoveDeviceTag(appContext))
 
 		// File operations
-		api.POST("/files/upload", handlers.UploadFiles(appContext))
+		api.POST("/files/upload", manageFirmware, handlers.UploadFiles(appContext))
 		api.GET("/files/:fileId/download", handlers.DownloadFile(appContext))
 		api.POST("/files/download-bulk", handlers.DownloadBulkFiles(appContext))
-		api.DELETE("/files/:fileId", handlers.DeleteFile(appContext))
+		api.DELETE("/files/:fileId", manageFirmware, handlers.DeleteFile(appContext))
 
 		// Fault operations
-		api.PUT("/faults/:faultId/acknowledge", handlers.AcknowledgeFault(appContext))
-		api.PUT("/faults/:faultId/resolve", handlers.ResolveFault(appContext))
-
-		// Filter presets
+		api.GET("/faults/:faultId", handlers.GetFault(appContext))
+		api.PUT("/faults/:faultId/acknowledge", acknowledge, handlers.AcknowledgeFault(appContext))
+		api.PUT("/faults/:faultId/resolve", resolve, handlers.ResolveFault(appContext))
+		api.PUT("/faults/:faultId/assign", acknowledge, handlers.AssignFault(appContext))
+		api.POST("/faults/:faultId/notes", acknowledge, handlers.AddFaultNote(appContext))
+		api.PUT("/incidents/:incidentId/acknowledge", acknowledge, handlers.AcknowledgeIncident(appContext))
+		api.PUT("/incidents/:incidentId/resolve", resolve, handlers.ResolveIncident(appContext))
+
+		// Filter presets are shared, so only operators change them
 		api.GET("/filters/devices", handlers.GetDeviceFilters(appContext))
-		api.POST("/filters/devices", handlers.SaveDeviceFilter(appContext))
-		api.DELETE("/filters/devices/:filterId", handlers.DeleteDeviceFilter(appContext))
+		api.POST("/filters/devices", manageDevice, handlers.SaveDeviceFilter(appContext))
+		api.DELETE("/filters/devices/:filterId", manageDevice, handlers.DeleteDeviceFilter(appContext))
+	}
+
+	// User management, local users only exist with login enabled
+	if loginEnabled {
+		manageUsers := auth.RequirePermission(models.PermUserManage)
+
+		ui.GET("/users", manageUsers, handlers.Users(appContext))
+		api.GET("/users", manageUsers, handlers.GetUsers(appContext))
+		api.POST("/users", manageUsers, handlers.CreateUser(appContext))
+		api.PUT("/users/:userId", manageUsers, handlers.UpdateUser(appContext))
+		api.DELETE("/users/:userId", manageUsers, handlers.DeleteUser(appContext))
+		api.PUT("/account/password", handlers.ChangePassword(appContext))
 	}
 
+	// Audit log, it spans tenants
+	viewAudit := auth.RequirePermission(models.PermAuditView)
+	allTenants := auth.RequireAllTenants()
+	ui.GET("/audit", viewAudit, allTenants, handlers.Audit(appContext))
+	api.GET("/audit", viewAudit, allTenants, handlers.GetAuditRecords(appContext))
+	api.GET("/audit/verify", viewAudit, allTenants, handlers.VerifyAuditLog(appContext))
+
 	// WebSocket for real-time updates
-	router.GET("/ws", handlers.WebSocketHandler(appContext))
+	ui.GET("/ws", view, handlers.WebSocketHandler(appContext, cfg.AllowedOrigins))
 
 	// Health check for UI
 	router.GET("/health", func(c *gin.Context) {
@@ -67,6 +139,17 @@ func InitRouter(router *gin.Engine, appContext *context.Context) {
 			"time":   time.Now().UTC().Format(time.RFC3339),
 		})
 	})
+
+	return nil
+}
+
+// AnonymousMiddleware gives UI visitors the permissions of the anonymous
+// role while login is disabled
+func AnonymousMiddleware(appContext *context.Context, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth.SetPrincipal(c, appContext.NewPrincipal("", models.AuthMethodAnonymous, []string{role}))
+		c.Next()
+	}
 }
 
 // LoggerMiddleware creates a logger middleware for the web UI
@@ -120,17 +203,81 @@ func SecurityMiddleware() gin.HandlerFunc {
 	}
 }
 
-// SessionMiddleware handles session management
-func SessionMiddleware() gin.HandlerFunc {
+// SessionMiddleware requires a live session and gives the request the
+// permissions of the role of its user. Pages redirect to the login form,
+// API and WebSocket requests get a 401.
+func SessionMiddleware(appContext *context.Context, cookie *handlers.SessionCookie) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, ok := cookie.Get(c)
+		if !ok {
+			loginRequired(c)
+			return
+		}
+
+		session, err := appContext.TouchSession(sessionID)
+		if err != nil {
+			cookie.Clear(c)
+			loginRequired(c)
+			return
+		}
+
+		user, exists := appContext.GetUser(session.UserID)
+		if !exists || user.Disabled {
+			appContext.RemoveSession(sessionID)
+			cookie.Clear(c)
+			loginRequired(c)
+			return
+		}
+
+		principal := appContext.NewPrincipal(user.Username, models.AuthMethodSession, []string{user.Role})
+		principal.Tenant = user.Tenant
+		auth.SetPrincipal(c, principal)
+		handlers.SetSession(c, session)
+		c.Next()
+	}
+}
+
+// loginRequired rejects a request without a session
+func loginRequired(c *gin.Context) {
+	path := c.Request.URL.Path
+	if strings.HasPrefix(path, "/api/") || path == "/ws" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "Login required",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/login?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+	c.Abort()
+}
+
+// CSRFMiddleware requires the CSRF token of the session on state-changing
+// requests, in the X-CSRF-Token header or the csrf_token form field
+func CSRFMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get or create session ID
-		sessionID := c.GetHeader("X-Session-ID")
-		if sessionID == "" {
-			sessionID = generateSessionID()
-			c.Header("X-Session-ID", sessionID)
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		session, ok := handlers.GetSession(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader("X-CSRF-Token")
+		if token == "" {
+			token = c.PostForm("csrf_token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(session.CSRFToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Invalid CSRF token",
+			})
+			return
 		}
 
-		c.Set("sessionID", sessionID)
 		c.Next()
 	}
 }
@@ -196,12 +343,6 @@ func ErrorHandlerMiddleware() gin.HandlerFunc {
 // loadTemplates is not needed since we use templ components directly
 // Templates are rendered in handlers using templ.Render()
 
-// generateSessionID generates a unique session ID
-func generateSessionID() string {
-	// Simple implementation - in production use UUID or similar
-	return fmt.Sprintf("sess_%d", time.Now().UnixNano())
-}
-
 // NotFoundHandler handles 404 errors
 func NotFoundHandler(appContext *context.Context) gin.HandlerFunc {
 	return func(c *gin.Context) {
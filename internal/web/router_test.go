@@ -0,0 +1,50 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+func TestFilterPresetPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPost, "/api/filters/devices", `{"id":"vip","name":"VIP devices"}`},
+		{http.MethodDelete, "/api/filters/devices/vip", ""},
+	}
+	for _, role := range []string{models.RoleViewer, models.RoleOperator} {
+		router := gin.New()
+		if err := InitRouter(router, context.NewContext(), &config.UI{AnonymousRole: role}); err != nil {
+			t.Fatalf("InitRouter: %v", err)
+		}
+
+		for _, tt := range tests {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if denied := w.Code == http.StatusForbidden; denied != (role == models.RoleViewer) {
+				t.Errorf("%s %s as %s returned %d", tt.method, tt.path, role, w.Code)
+			}
+		}
+
+		// Every role may read the presets
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/filters/devices", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET /api/filters/devices as %s returned %d", role, w.Code)
+		}
+	}
+}
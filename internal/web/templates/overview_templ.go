@@ -138,7 +138,7 @@ func OverviewPage(data OverviewData) templ.Component {
 			}
 			return nil
 		})
-		templ_7745c5c3_Err = Page(data.Title, data.Theme, "/overview").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = Page(data.BasePageData).Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -12,6 +12,45 @@ type BasePageData struct {
 	Theme       string
 	CurrentPath string
 	User        *UserInfo
+
+	// CSRFToken is sent with state-changing requests, empty while login
+	// is disabled
+	CSRFToken string
+}
+
+// LoginPageData contains data for the login page
+type LoginPageData struct {
+	Title    string
+	Theme    string
+	Next     string
+	Username string
+	Error    string
+
+	// SSOName labels the single sign-on button, empty without single
+	// sign-on
+	SSOName string
+}
+
+// UsersPageData contains data for the user management page
+type UsersPageData struct {
+	BasePageData
+	Users         []*models.User
+	Roles         []string
+	CurrentUserID string
+
+	// Tenants users can be assigned to, empty for callers limited to a
+	// tenant, whose users always belong to it
+	Tenants []*models.Tenant
+}
+
+// AuditPageData contains data for the audit log page
+type AuditPageData struct {
+	BasePageData
+	Records []*models.AuditRecord
+	Total   int
+	Filter  models.AuditFilter
+	Query   string
+	Error   string
 }
 
 // UserInfo contains user information for display
@@ -19,6 +58,15 @@ type UserInfo struct {
 	Username string
 	Role     string
 	Avatar   string
+
+	// Permissions holds the permissions granted by the roles of the user
+	Permissions map[string]bool
+}
+
+// Can reports whether the user has a permission. Pages without a user
+// grant nothing.
+func (u *UserInfo) Can(permission string) bool {
+	return u != nil && u.Permissions[permission]
 }
 
 // OverviewData contains data for the overview page
@@ -83,6 +131,7 @@ type DeviceFilters struct {
 	Status  string
 	Tags    []string
 	IPRange *models.IPRange
+	IPError string
 }
 
 // DeviceDetailData contains data for the device detail page
@@ -94,8 +143,14 @@ type DeviceDetailData struct {
 	Faults     []*models.Fault
 
 	StatusHistory []StatusEvent
+	Availability  []models.Availability
 	IsOnline      bool
-	CanManage     bool
+
+	// Permissions of the user for the device
+	CanManage       bool
+	CanReboot       bool
+	CanFactoryReset bool
+	CanAcknowledge  bool
 }
 
 // StatusEvent represents a device status change event
@@ -117,6 +172,26 @@ type FaultsPageData struct {
 	TotalPages        int
 	Filters           FaultFilters
 	SeverityStats     map[string]int
+	Incidents         []*IncidentDisplay
+	Incident          *IncidentDisplay
+	SuppressedCount   int
+	Maintenance       []*MaintenanceWindowDisplay
+}
+
+// IncidentDisplay contains correlated fault group information for display
+type IncidentDisplay struct {
+	*models.Incident
+	TimeAgoText    string
+	CanAcknowledge bool
+	CanResolve     bool
+}
+
+// MaintenanceWindowDisplay represents a maintenance window for display
+type MaintenanceWindowDisplay struct {
+	*models.MaintenanceWindow
+	Active       bool
+	ScheduleText string
+	SelectorText string
 }
 
 // FaultDisplay contains fault information for display
@@ -132,11 +207,13 @@ type FaultDisplay struct {
 
 // FaultFilters contains active filters for fault list
 type FaultFilters struct {
-	DeviceID  string
-	Severity  string
-	Status    string
-	Channel   string
-	TimeRange string
+	DeviceID   string
+	Severity   string
+	Status     string
+	Channel    string
+	TimeRange  string
+	IncidentID string
+	Assignee   string
 }
 
 // ZonesPageData contains data for the zones page
@@ -292,6 +369,7 @@ type FilesPageData struct {
 	Files     []FileInfo
 	TotalSize int64
 	Filters   FileFilters
+	CanManage bool
 }
 
 // FileInfo contains file information for display
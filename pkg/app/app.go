@@ -2,10 +2,13 @@ package app
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"sync"
 	"syscall"
 	"time"
@@ -14,6 +17,7 @@ import (
 	"github.com/nextranet/gateway/c-plane/config"
 	appContext "github.com/nextranet/gateway/c-plane/internal/context"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
 	"github.com/nextranet/gateway/c-plane/internal/sbi"
 	"github.com/nextranet/gateway/c-plane/internal/web"
 	"github.com/nextranet/gateway/c-plane/pkg/factory"
@@ -58,6 +62,54 @@ func New(cfgPath string) (*App, error) {
 	// Get application context
 	appCtx := appContext.GetContext()
 	appCtx.SetConfig(cfg)
+	appCtx.SetCorrelationConfig(appContext.CorrelationConfig{
+		Enabled:   cfg.Faults.Correlation.Enabled,
+		Window:    cfg.Faults.Correlation.Window,
+		MinFaults: cfg.Faults.Correlation.MinFaults,
+		Retention: cfg.Faults.Correlation.Retention,
+	})
+	if cfg.Search != nil && len(cfg.Search.Parameters) > 0 {
+		appCtx.SetSearchParameters(cfg.Search.Parameters)
+	}
+	appCtx.SetTenants(tenantsFromConfig(cfg.Tenants))
+	appCtx.SetFaultStateFile(cfg.Faults.StateFile)
+	if err := appCtx.LoadFaultState(); err != nil {
+		logger.InitLog.Warnf("Failed to load fault state: %v", err)
+	}
+	appCtx.SetWebhookStateFile(cfg.Notifications.Webhooks.StateFile)
+	appCtx.SetWebhookDeliveryHistory(cfg.Notifications.Webhooks.DeliveryHistory)
+	if err := appCtx.LoadWebhookState(); err != nil {
+		logger.InitLog.Warnf("Failed to load webhook state: %v", err)
+	}
+	if cfg.RBAC != nil {
+		appCtx.SetRoles(cfg.RBAC.Roles)
+	}
+	appCtx.SetAuditLog(cfg.Audit.File, cfg.Audit.MemoryRecords)
+	if err := appCtx.LoadAuditLog(); err != nil {
+		logger.InitLog.Warnf("Failed to load audit log: %v", err)
+	}
+	if cfg.NBI != nil {
+		appCtx.SetAPIKeyStateFile(cfg.NBI.Auth.APIKeyStateFile)
+		if err := appCtx.LoadAPIKeyState(); err != nil {
+			logger.InitLog.Warnf("Failed to load API key state: %v", err)
+		}
+	}
+	if cfg.UI != nil && cfg.UI.Auth.Enabled {
+		appCtx.SetSessionConfig(appContext.SessionConfig{
+			IdleTimeout:     cfg.UI.Auth.IdleTimeout,
+			AbsoluteTimeout: cfg.UI.Auth.AbsoluteTimeout,
+			MaxFailedLogins: cfg.UI.Auth.MaxFailedLogins,
+			LockoutDuration: cfg.UI.Auth.LockoutDuration,
+		})
+		appCtx.SetUserStateFile(cfg.UI.Auth.UserStateFile)
+		if err := appCtx.LoadUserState(); err != nil {
+			logger.InitLog.Warnf("Failed to load user state: %v", err)
+		}
+		if err := bootstrapAdmin(appCtx, cfg.UI.Auth.UserStateFile); err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create initial admin user: %w", err)
+		}
+	}
 
 	app := &App{
 		cfg:        cfg,
@@ -69,6 +121,64 @@ func New(cfgPath string) (*App, error) {
 	return app, nil
 }
 
+// tenantsFromConfig converts the configured tenants, naming those without
+// a name after their ID
+func tenantsFromConfig(configured []config.Tenant) []*models.Tenant {
+	tenants := make([]*models.Tenant, 0, len(configured))
+	for _, tenant := range configured {
+		name := tenant.Name
+		if name == "" {
+			name = tenant.ID
+		}
+		tenants = append(tenants, &models.Tenant{
+			ID:          tenant.ID,
+			Name:        name,
+			Description: tenant.Description,
+			Selector: models.TenantSelector{
+				OUIs:              tenant.OUIs,
+				Tags:              tenant.Tags,
+				ProvisioningCodes: tenant.ProvisioningCodes,
+			},
+		})
+	}
+	return tenants
+}
+
+// bootstrapAdmin creates the admin user on first start, so the UI can be
+// reached once login is enabled. Its random password is written next to
+// the user state file, readable by the gateway user only.
+func bootstrapAdmin(appCtx *appContext.Context, userStateFile string) error {
+	if len(appCtx.GetUsers()) > 0 {
+		return nil
+	}
+
+	secret := make([]byte, 18)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	password := base64.RawURLEncoding.EncodeToString(secret)
+
+	admin := &models.User{
+		Username:  "admin",
+		Role:      models.RoleAdmin,
+		CreatedBy: "system",
+	}
+	if err := appCtx.AddUser(admin, password); err != nil {
+		return err
+	}
+
+	passwordFile := filepath.Join(filepath.Dir(userStateFile), "initial-admin-password")
+	if err := os.MkdirAll(filepath.Dir(passwordFile), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(passwordFile, []byte(password+"\n"), 0o600); err != nil {
+		return err
+	}
+
+	logger.InitLog.Warnf("Created UI user admin, its password is in %s. Change it after the first login and delete the file.", passwordFile)
+	return nil
+}
+
 // Start starts the application services
 func (a *App) Start() error {
 	logger.InitLog.Info("Starting Nextranet Gateway services...")
@@ -86,12 +196,102 @@ func (a *App) Start() error {
 		genieService.StartMonitoring(a.ctx)
 	}()
 
+	// Start inventory sync and alarm rule evaluation
+	inventoryService := service.NewInventoryService(a.cfg.GenieACS, a.appContext)
+	inventoryService.LoadRules(a.cfg.Faults.Rules)
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		inventoryService.Start(a.ctx)
+	}()
+
+	// Start fault escalation worker
+	escalationService := service.NewEscalationService(a.cfg.Faults.Escalation, a.appContext)
+	escalationService.LoadPolicies()
+	if a.cfg.Faults.Escalation.Enabled {
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			escalationService.Start(a.ctx)
+		}()
+	}
+
+	// Start webhook delivery
+	if a.cfg.Notifications.Webhooks.Enabled {
+		webhookService := service.NewWebhookService(a.cfg.Notifications.Webhooks, a.appContext)
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			webhookService.Start(a.ctx)
+		}()
+	}
+
+	// Start SNMP traps
+	if a.cfg.Notifications.SNMP.Enabled {
+		trapService, err := service.NewSNMPTrapService(a.cfg.Notifications.SNMP, a.appContext)
+		if err != nil {
+			return fmt.Errorf("failed to initialize SNMP traps: %w", err)
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			trapService.Start(a.ctx)
+		}()
+	}
+
+	// Start syslog forwarding
+	if a.cfg.Notifications.Syslog.Enabled {
+		syslogService, err := service.NewSyslogService(a.cfg.Notifications.Syslog, a.appContext)
+		if err != nil {
+			return fmt.Errorf("failed to initialize syslog forwarding: %w", err)
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			syslogService.Start(a.ctx)
+		}()
+	}
+
+	// Start MQTT publishing
+	if a.cfg.Notifications.MQTT.Enabled {
+		mqttService, err := service.NewMQTTService(a.cfg.Notifications.MQTT, a.appContext)
+		if err != nil {
+			return fmt.Errorf("failed to initialize MQTT publishing: %w", err)
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			mqttService.Start(a.ctx)
+		}()
+	}
+
+	// Start email notifications
+	if a.cfg.Notifications.Email.Enabled {
+		emailService, err := service.NewEmailService(a.cfg.Notifications.Email, a.appContext)
+		if err != nil {
+			return fmt.Errorf("failed to initialize email notifications: %w", err)
+		}
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			emailService.Start(a.ctx)
+		}()
+	}
+
 	// Start NBI server
 	if a.cfg.NBI != nil {
+		auth, err := sbi.NewAuthenticator(a.cfg.NBI.Auth, a.appContext)
+		if err != nil {
+			return fmt.Errorf("failed to initialize NBI authentication: %w", err)
+		}
+		if !auth.Enabled() {
+			logger.InitLog.Warn("NBI authentication is disabled, the API is open to every client")
+		}
+
 		a.wg.Add(1)
 		go func() {
 			defer a.wg.Done()
-			if err := a.startNBI(); err != nil {
+			if err := a.startNBI(auth); err != nil {
 				logger.InitLog.Errorf("NBI server error: %v", err)
 			}
 		}()
@@ -120,7 +320,7 @@ func (a *App) Start() error {
 }
 
 // startNBI starts the NBI (North Bound Interface) server
-func (a *App) startNBI() error {
+func (a *App) startNBI(auth *sbi.Authenticator) error {
 	logger.InitLog.Info("Starting NBI server...")
 
 	// Set Gin mode
@@ -140,7 +340,7 @@ func (a *App) startNBI() error {
 	router.Use(sbi.CORSMiddleware())
 
 	// Initialize SBI routes
-	sbi.InitRouter(router, a.appContext)
+	sbi.InitRouter(router, a.appContext, auth)
 
 	// Determine binding address
 	bindAddr := fmt.Sprintf("%s:%d", a.cfg.NBI.BindingIPv4, a.cfg.NBI.Port)
@@ -184,7 +384,9 @@ func (a *App) startUI() error {
 	router.Use(web.LoggerMiddleware())
 
 	// Initialize web routes
-	web.InitRouter(router, a.appContext)
+	if err := web.InitRouter(router, a.appContext, a.cfg.UI); err != nil {
+		return fmt.Errorf("failed to initialize UI routes: %w", err)
+	}
 
 	// Determine binding address
 	bindAddr := fmt.Sprintf("%s:%d", a.cfg.UI.BindingIPv4, a.cfg.UI.Port)
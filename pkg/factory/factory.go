@@ -1,8 +1,13 @@
 package factory
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +15,7 @@ import (
 
 	"github.com/nextranet/gateway/c-plane/config"
 	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
@@ -118,6 +124,37 @@ func applyDefaults(cfg *config.Config) {
 		if cfg.NBI.WriteTimeout == 0 {
 			cfg.NBI.WriteTimeout = 30 * time.Second
 		}
+
+		// Authentication is on unless explicitly disabled
+		if cfg.NBI.Auth == nil {
+			cfg.NBI.Auth = &config.NBIAuth{Enabled: true}
+		}
+		for i := range cfg.NBI.Auth.APIKeys {
+			if cfg.NBI.Auth.APIKeys[i].Role == "" {
+				cfg.NBI.Auth.APIKeys[i].Role = models.RoleViewer
+			}
+		}
+		if jwt := cfg.NBI.Auth.JWT; jwt != nil {
+			if len(jwt.Algorithms) == 0 {
+				if jwt.JWKSFile != "" {
+					jwt.Algorithms = []string{"RS256"}
+				} else {
+					jwt.Algorithms = []string{"HS256"}
+				}
+			}
+			if jwt.NameClaim == "" {
+				jwt.NameClaim = "sub"
+			}
+			if jwt.RolesClaim == "" {
+				jwt.RolesClaim = "roles"
+			}
+			if jwt.DefaultRole == "" {
+				jwt.DefaultRole = models.RoleViewer
+			}
+			if jwt.Leeway == 0 {
+				jwt.Leeway = 30 * time.Second
+			}
+		}
 	}
 
 	// UI defaults
@@ -140,6 +177,45 @@ func applyDefaults(cfg *config.Config) {
 		if cfg.UI.Theme == "" {
 			cfg.UI.Theme = "dark"
 		}
+		// Anonymous visitors only look unless more is granted explicitly
+		if cfg.UI.AnonymousRole == "" {
+			cfg.UI.AnonymousRole = models.RoleViewer
+		}
+		if cfg.UI.Auth == nil {
+			cfg.UI.Auth = &config.UIAuth{Enabled: true}
+		}
+		if cfg.UI.Auth.UserStateFile == "" {
+			cfg.UI.Auth.UserStateFile = "./data/users.json"
+		}
+		if cfg.UI.Auth.IdleTimeout == 0 {
+			cfg.UI.Auth.IdleTimeout = 30 * time.Minute
+		}
+		if cfg.UI.Auth.AbsoluteTimeout == 0 {
+			cfg.UI.Auth.AbsoluteTimeout = 12 * time.Hour
+		}
+		if cfg.UI.Auth.MaxFailedLogins == 0 {
+			cfg.UI.Auth.MaxFailedLogins = 5
+		}
+		if cfg.UI.Auth.LockoutDuration == 0 {
+			cfg.UI.Auth.LockoutDuration = 15 * time.Minute
+		}
+		if oidc := cfg.UI.Auth.OIDC; oidc != nil {
+			if oidc.Name == "" {
+				oidc.Name = "Single Sign-On"
+			}
+			if len(oidc.Scopes) == 0 {
+				oidc.Scopes = []string{"profile", "email"}
+			}
+			if oidc.UsernameClaim == "" {
+				oidc.UsernameClaim = "preferred_username"
+			}
+			if oidc.GroupsClaim == "" {
+				oidc.GroupsClaim = "groups"
+			}
+		}
+		if cfg.UI.Scheme == "https" {
+			cfg.UI.Auth.SecureCookie = true
+		}
 	}
 
 	// Database defaults
@@ -184,6 +260,203 @@ func applyDefaults(cfg *config.Config) {
 		if cfg.GenieACS.Timeout == 0 {
 			cfg.GenieACS.Timeout = 30 * time.Second
 		}
+		if cfg.GenieACS.SyncInterval == 0 {
+			cfg.GenieACS.SyncInterval = time.Minute
+		}
+		if cfg.GenieACS.Heartbeat == nil {
+			cfg.GenieACS.Heartbeat = &config.Heartbeat{}
+		}
+		if cfg.GenieACS.Heartbeat.DefaultInterval == 0 {
+			cfg.GenieACS.Heartbeat.DefaultInterval = 5 * time.Minute
+		}
+		if cfg.GenieACS.Heartbeat.Tolerance == 0 {
+			cfg.GenieACS.Heartbeat.Tolerance = 1.5
+		}
+		if cfg.GenieACS.Heartbeat.MissedInforms == 0 {
+			cfg.GenieACS.Heartbeat.MissedInforms = 3
+		}
+		if cfg.GenieACS.Heartbeat.FaultSeverity == "" {
+			cfg.GenieACS.Heartbeat.FaultSeverity = "major"
+		}
+	}
+
+	// Audit log defaults
+	if cfg.Audit == nil {
+		cfg.Audit = &config.Audit{File: "./data/audit.log"}
+	}
+	if cfg.Audit.MemoryRecords == 0 {
+		cfg.Audit.MemoryRecords = 100000
+	}
+
+	// Fault management defaults
+	if cfg.Faults == nil {
+		cfg.Faults = &config.Faults{}
+	}
+	if cfg.Faults.Correlation == nil {
+		cfg.Faults.Correlation = &config.FaultCorrelation{Enabled: true}
+	}
+	if cfg.Faults.Correlation.Window == 0 {
+		cfg.Faults.Correlation.Window = 5 * time.Minute
+	}
+	if cfg.Faults.Correlation.MinFaults == 0 {
+		cfg.Faults.Correlation.MinFaults = 2
+	}
+	if cfg.Faults.Correlation.Retention == 0 {
+		cfg.Faults.Correlation.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.Faults.Escalation == nil {
+		cfg.Faults.Escalation = &config.FaultEscalation{Enabled: true}
+	}
+	if cfg.Faults.Escalation.Interval == 0 {
+		cfg.Faults.Escalation.Interval = time.Minute
+	}
+
+	// Notification defaults
+	if cfg.Notifications == nil {
+		cfg.Notifications = &config.Notifications{}
+	}
+	if cfg.Notifications.Webhooks == nil {
+		cfg.Notifications.Webhooks = &config.Webhooks{Enabled: true}
+	}
+	if cfg.Notifications.Webhooks.Timeout == 0 {
+		cfg.Notifications.Webhooks.Timeout = 10 * time.Second
+	}
+	if cfg.Notifications.Webhooks.MaxAttempts == 0 {
+		cfg.Notifications.Webhooks.MaxAttempts = 8
+	}
+	if cfg.Notifications.Webhooks.InitialBackoff == 0 {
+		cfg.Notifications.Webhooks.InitialBackoff = 10 * time.Second
+	}
+	if cfg.Notifications.Webhooks.MaxBackoff == 0 {
+		cfg.Notifications.Webhooks.MaxBackoff = time.Hour
+	}
+	if cfg.Notifications.Webhooks.Workers == 0 {
+		cfg.Notifications.Webhooks.Workers = 4
+	}
+	if cfg.Notifications.Webhooks.DeliveryHistory == 0 {
+		cfg.Notifications.Webhooks.DeliveryHistory = 500
+	}
+	if cfg.Notifications.SNMP == nil {
+		cfg.Notifications.SNMP = &config.SNMPTraps{}
+	}
+	if cfg.Notifications.SNMP.EnterpriseOID == "" {
+		cfg.Notifications.SNMP.EnterpriseOID = "1.3.6.1.4.1.32473"
+	}
+	if cfg.Notifications.SNMP.EngineBootsFile == "" {
+		cfg.Notifications.SNMP.EngineBootsFile = "./data/snmp-engine-boots"
+	}
+	if cfg.Notifications.SNMP.ResyncInterval == 0 {
+		cfg.Notifications.SNMP.ResyncInterval = 15 * time.Minute
+	}
+	for i := range cfg.Notifications.SNMP.Targets {
+		target := &cfg.Notifications.SNMP.Targets[i]
+		if target.Version == "" {
+			target.Version = "2c"
+		}
+		if target.Version == "2c" && target.Community == "" {
+			target.Community = "public"
+		}
+		if _, _, err := net.SplitHostPort(target.Address); err != nil && target.Address != "" {
+			target.Address = net.JoinHostPort(target.Address, "162")
+		}
+	}
+	if cfg.Notifications.Syslog == nil {
+		cfg.Notifications.Syslog = &config.Syslog{}
+	}
+	syslog := cfg.Notifications.Syslog
+	if syslog.Network == "" {
+		syslog.Network = "udp"
+	}
+	if _, _, err := net.SplitHostPort(syslog.Address); err != nil && syslog.Address != "" {
+		// RFC 5425 assigns 6514 to syslog over TLS
+		port := "514"
+		if syslog.Network == "tls" {
+			port = "6514"
+		}
+		syslog.Address = net.JoinHostPort(syslog.Address, port)
+	}
+	if syslog.Facility == "" {
+		syslog.Facility = "local0"
+	}
+	if syslog.AuditFacility == "" {
+		syslog.AuditFacility = "authpriv"
+	}
+	if syslog.AppName == "" {
+		syslog.AppName = "nextranet-gateway"
+	}
+	if syslog.EnterpriseID == "" {
+		syslog.EnterpriseID = "32473"
+	}
+	if len(syslog.Events) == 0 {
+		syslog.Events = []string{"fault.*", "task.queued"}
+	}
+	if syslog.BufferSize == 0 {
+		syslog.BufferSize = 10000
+	}
+	if syslog.WriteTimeout == 0 {
+		syslog.WriteTimeout = 5 * time.Second
+	}
+	if syslog.ReconnectBackoff == 0 {
+		syslog.ReconnectBackoff = time.Second
+	}
+	if syslog.MaxReconnectBackoff == 0 {
+		syslog.MaxReconnectBackoff = time.Minute
+	}
+	if cfg.Notifications.MQTT == nil {
+		cfg.Notifications.MQTT = &config.MQTT{}
+	}
+	mqtt := cfg.Notifications.MQTT
+	if mqtt.TopicPrefix == "" {
+		mqtt.TopicPrefix = "cplane"
+	}
+	if len(mqtt.Events) == 0 {
+		mqtt.Events = []string{"device.*", "fault.*", "task.*"}
+	}
+	if mqtt.QueueSize == 0 {
+		mqtt.QueueSize = 10000
+	}
+	if mqtt.KeepAlive == 0 {
+		mqtt.KeepAlive = 30 * time.Second
+	}
+	if mqtt.ConnectTimeout == 0 {
+		mqtt.ConnectTimeout = 10 * time.Second
+	}
+	if mqtt.PublishTimeout == 0 {
+		mqtt.PublishTimeout = 10 * time.Second
+	}
+	if mqtt.MaxReconnectInterval == 0 {
+		mqtt.MaxReconnectInterval = time.Minute
+	}
+	if cfg.Notifications.Email == nil {
+		cfg.Notifications.Email = &config.Email{}
+	}
+	email := cfg.Notifications.Email
+	if email.SMTP == nil {
+		email.SMTP = &config.SMTP{}
+	}
+	if email.SMTP.Security == "" {
+		email.SMTP.Security = "starttls"
+	}
+	if email.SMTP.Port == 0 {
+		switch email.SMTP.Security {
+		case "tls":
+			email.SMTP.Port = 465
+		case "none":
+			email.SMTP.Port = 25
+		default:
+			email.SMTP.Port = 587
+		}
+	}
+	if email.SMTP.Timeout == 0 {
+		email.SMTP.Timeout = 30 * time.Second
+	}
+	if email.MaxPerHour == 0 {
+		email.MaxPerHour = 20
+	}
+	for i := range email.Digests {
+		if email.Digests[i].At == "" {
+			email.Digests[i].At = "08:00"
+		}
 	}
 }
 
@@ -197,6 +470,25 @@ func validateConfig(cfg *config.Config) error {
 		}
 	}
 
+	// Validate roles, the NBI and UI refer to them
+	if cfg.RBAC != nil {
+		if err := validateRBAC(cfg.RBAC); err != nil {
+			return err
+		}
+	}
+	roles := configuredRoles(cfg)
+
+	// Validate tenants, API keys and users refer to them
+	tenants, err := validateTenants(cfg.Tenants)
+	if err != nil {
+		return err
+	}
+
+	// Validate audit log
+	if cfg.Audit != nil && cfg.Audit.MemoryRecords < 0 {
+		return fmt.Errorf("invalid audit memoryRecords: %d", cfg.Audit.MemoryRecords)
+	}
+
 	// Validate NBI
 	if cfg.NBI != nil {
 		if cfg.NBI.Port < 1 || cfg.NBI.Port > 65535 {
@@ -219,6 +511,14 @@ func validateConfig(cfg *config.Config) error {
 				return fmt.Errorf("TLS key file not found: %s", cfg.NBI.TLS.Key)
 			}
 		}
+		if cfg.NBI.Auth.Enabled {
+			if err := validateNBIAuth(cfg.NBI.Auth, roles, tenants); err != nil {
+				return err
+			}
+		}
+		if err := validateOrigins("NBI", cfg.NBI.AllowedOrigins); err != nil {
+			return err
+		}
 	}
 
 	// Validate UI
@@ -247,6 +547,17 @@ func validateConfig(cfg *config.Config) error {
 		if !contains(validThemes, cfg.UI.Theme) {
 			return fmt.Errorf("invalid UI theme: %s", cfg.UI.Theme)
 		}
+		if !contains(roles, cfg.UI.AnonymousRole) {
+			return fmt.Errorf("invalid UI anonymousRole: %s", cfg.UI.AnonymousRole)
+		}
+		if cfg.UI.Auth.Enabled {
+			if err := validateUIAuth(cfg.UI.Auth, roles, tenants); err != nil {
+				return err
+			}
+		}
+		if err := validateOrigins("UI", cfg.UI.AllowedOrigins); err != nil {
+			return err
+		}
 	}
 
 	// Validate Database
@@ -274,6 +585,123 @@ func validateConfig(cfg *config.Config) error {
 		if cfg.GenieACS.FSURL == "" {
 			return fmt.Errorf("GenieACS FS URL is required")
 		}
+		if cfg.GenieACS.SyncInterval < 0 {
+			return fmt.Errorf("invalid GenieACS sync interval: %s", cfg.GenieACS.SyncInterval)
+		}
+		if hb := cfg.GenieACS.Heartbeat; hb != nil {
+			if hb.DefaultInterval < 0 {
+				return fmt.Errorf("invalid heartbeat default interval: %s", hb.DefaultInterval)
+			}
+			if hb.Tolerance < 1 {
+				return fmt.Errorf("invalid heartbeat tolerance: %v (must be at least 1)", hb.Tolerance)
+			}
+			if hb.MissedInforms < 1 {
+				return fmt.Errorf("invalid heartbeat missedInforms: %d", hb.MissedInforms)
+			}
+			validSeverities := []string{"critical", "major", "minor", "warning", "info"}
+			if !contains(validSeverities, hb.FaultSeverity) {
+				return fmt.Errorf("invalid heartbeat fault severity: %s", hb.FaultSeverity)
+			}
+		}
+	}
+
+	// Validate fault management
+	if cfg.Faults != nil && cfg.Faults.Correlation != nil {
+		if cfg.Faults.Correlation.Window < 0 {
+			return fmt.Errorf("invalid fault correlation window: %s", cfg.Faults.Correlation.Window)
+		}
+		if cfg.Faults.Correlation.MinFaults < 1 {
+			return fmt.Errorf("invalid fault correlation minFaults: %d", cfg.Faults.Correlation.MinFaults)
+		}
+		if cfg.Faults.Correlation.Retention < 0 {
+			return fmt.Errorf("invalid fault correlation retention: %s", cfg.Faults.Correlation.Retention)
+		}
+	}
+	if cfg.Faults != nil && cfg.Faults.Escalation != nil {
+		if cfg.Faults.Escalation.Interval < 0 {
+			return fmt.Errorf("invalid fault escalation interval: %s", cfg.Faults.Escalation.Interval)
+		}
+		for _, policy := range cfg.Faults.Escalation.Policies {
+			if policy.Name == "" {
+				return fmt.Errorf("escalation policy name is required")
+			}
+			if len(policy.Levels) == 0 {
+				return fmt.Errorf("escalation policy %s has no levels", policy.Name)
+			}
+		}
+	}
+
+	if cfg.Faults != nil {
+		for _, rule := range cfg.Faults.Rules {
+			if rule.Name == "" {
+				return fmt.Errorf("alarm rule name is required")
+			}
+			if rule.Condition == "" {
+				return fmt.Errorf("alarm rule %s has no condition", rule.Name)
+			}
+			if rule.RaiseAfter < 0 || rule.ClearAfter < 0 {
+				return fmt.Errorf("alarm rule %s has a negative hold-down timer", rule.Name)
+			}
+		}
+	}
+
+	if cfg.Notifications != nil && cfg.Notifications.Webhooks != nil {
+		wh := cfg.Notifications.Webhooks
+		if wh.Timeout < 0 {
+			return fmt.Errorf("invalid webhook timeout: %s", wh.Timeout)
+		}
+		if wh.MaxAttempts < 1 {
+			return fmt.Errorf("invalid webhook maxAttempts: %d", wh.MaxAttempts)
+		}
+		if wh.InitialBackoff < 0 || wh.MaxBackoff < wh.InitialBackoff {
+			return fmt.Errorf("invalid webhook backoff: %s to %s", wh.InitialBackoff, wh.MaxBackoff)
+		}
+		if wh.Workers < 1 {
+			return fmt.Errorf("invalid webhook workers: %d", wh.Workers)
+		}
+		if wh.DeliveryHistory < 1 {
+			return fmt.Errorf("invalid webhook deliveryHistory: %d", wh.DeliveryHistory)
+		}
+	}
+
+	if cfg.Notifications != nil && cfg.Notifications.SNMP != nil {
+		if err := validateSNMPTraps(cfg.Notifications.SNMP); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Notifications != nil && cfg.Notifications.Syslog != nil && cfg.Notifications.Syslog.Enabled {
+		if err := validateSyslog(cfg.Notifications.Syslog); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Notifications != nil && cfg.Notifications.MQTT != nil && cfg.Notifications.MQTT.Enabled {
+		mqtt := cfg.Notifications.MQTT
+		if !strings.Contains(mqtt.Broker, "://") {
+			return fmt.Errorf("invalid MQTT broker: %s (tcp://, ssl://, ws:// or wss:// URL)", mqtt.Broker)
+		}
+		if mqtt.QoS > 2 {
+			return fmt.Errorf("invalid MQTT qos: %d", mqtt.QoS)
+		}
+		if mqtt.TopicPrefix == "" || strings.ContainsAny(mqtt.TopicPrefix, "+#") {
+			return fmt.Errorf("invalid MQTT topicPrefix: %s", mqtt.TopicPrefix)
+		}
+		if mqtt.TLS != nil && (mqtt.TLS.Cert == "") != (mqtt.TLS.Key == "") {
+			return fmt.Errorf("MQTT TLS requires both a cert and a key")
+		}
+		if mqtt.QueueSize < 1 {
+			return fmt.Errorf("invalid MQTT queueSize: %d", mqtt.QueueSize)
+		}
+		if mqtt.KeepAlive < 0 || mqtt.ConnectTimeout < 0 || mqtt.PublishTimeout < 0 || mqtt.MaxReconnectInterval < 0 {
+			return fmt.Errorf("invalid MQTT timeouts")
+		}
+	}
+
+	if cfg.Notifications != nil && cfg.Notifications.Email != nil && cfg.Notifications.Email.Enabled {
+		if err := validateEmail(cfg.Notifications.Email); err != nil {
+			return err
+		}
 	}
 
 	// Validate Zone
@@ -281,6 +709,66 @@ func validateConfig(cfg *config.Config) error {
 	return nil
 }
 
+// validateSNMPTraps validates the SNMP trap targets and their credentials
+func validateSNMPTraps(snmp *config.SNMPTraps) error {
+	if snmp.ResyncInterval < 0 {
+		return fmt.Errorf("invalid SNMP resync interval: %s", snmp.ResyncInterval)
+	}
+	if snmp.EngineID != "" {
+		engineID, err := hex.DecodeString(snmp.EngineID)
+		if err != nil || len(engineID) < 5 || len(engineID) > 32 {
+			return fmt.Errorf("invalid SNMP engine ID: %s (5 to 32 hex encoded octets)", snmp.EngineID)
+		}
+	}
+
+	validSeverities := []string{"critical", "major", "minor", "warning", "info"}
+	for _, target := range snmp.Targets {
+		if target.Name == "" {
+			return fmt.Errorf("SNMP target name is required")
+		}
+		if _, _, err := net.SplitHostPort(target.Address); err != nil {
+			return fmt.Errorf("invalid SNMP target %s address: %s", target.Name, target.Address)
+		}
+		for _, severity := range target.Severities {
+			if !contains(validSeverities, severity) {
+				return fmt.Errorf("invalid SNMP target %s severity: %s", target.Name, severity)
+			}
+		}
+
+		switch target.Version {
+		case "2c":
+		case "3":
+			user := target.User
+			if user == nil || user.Name == "" {
+				return fmt.Errorf("SNMP target %s requires a user", target.Name)
+			}
+			switch user.SecurityLevel {
+			case "noAuthNoPriv":
+			case "authPriv":
+				if !contains([]string{"DES", "AES", "AES192", "AES256"}, user.PrivProtocol) {
+					return fmt.Errorf("invalid SNMP target %s privacy protocol: %s", target.Name, user.PrivProtocol)
+				}
+				if len(user.PrivPassword) < 8 {
+					return fmt.Errorf("SNMP target %s privacy password must have at least 8 characters", target.Name)
+				}
+				fallthrough
+			case "authNoPriv":
+				if !contains([]string{"MD5", "SHA", "SHA224", "SHA256", "SHA384", "SHA512"}, user.AuthProtocol) {
+					return fmt.Errorf("invalid SNMP target %s authentication protocol: %s", target.Name, user.AuthProtocol)
+				}
+				if len(user.AuthPassword) < 8 {
+					return fmt.Errorf("SNMP target %s authentication password must have at least 8 characters", target.Name)
+				}
+			default:
+				return fmt.Errorf("invalid SNMP target %s security level: %s", target.Name, user.SecurityLevel)
+			}
+		default:
+			return fmt.Errorf("invalid SNMP target %s version: %s (2c or 3)", target.Name, target.Version)
+		}
+	}
+	return nil
+}
+
 // getDefaultConfigPath returns the default configuration file path
 func getDefaultConfigPath() string {
 	// Check environment variable
@@ -355,3 +843,282 @@ func SaveConfig(cfg *config.Config, path string) error {
 	logger.InitLog.Infof("Configuration saved to: %s", path)
 	return nil
 }
+
+// validateRBAC validates the permissions of the configured roles
+func validateRBAC(rbac *config.RBAC) error {
+	for role, permissions := range rbac.Roles {
+		if role == "" {
+			return fmt.Errorf("RBAC role name is required")
+		}
+		for _, permission := range permissions {
+			if !models.IsValidPermission(permission) {
+				return fmt.Errorf("invalid permission for role %s: %s", role, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTenants checks that tenants have unique IDs and select devices,
+// and returns the IDs
+func validateTenants(tenants []config.Tenant) ([]string, error) {
+	ids := make([]string, 0, len(tenants))
+	for _, tenant := range tenants {
+		if tenant.ID == "" {
+			return nil, fmt.Errorf("tenant id is required")
+		}
+		if contains(ids, tenant.ID) {
+			return nil, fmt.Errorf("duplicate tenant id: %s", tenant.ID)
+		}
+		if len(tenant.OUIs) == 0 && len(tenant.Tags) == 0 && len(tenant.ProvisioningCodes) == 0 {
+			return nil, fmt.Errorf("tenant %s requires ouis, tags or provisioningCodes", tenant.ID)
+		}
+		ids = append(ids, tenant.ID)
+	}
+	return ids, nil
+}
+
+// configuredRoles returns the built-in and configured role names
+func configuredRoles(cfg *config.Config) []string {
+	var roles []string
+	for role := range models.DefaultRolePermissions {
+		roles = append(roles, role)
+	}
+	if cfg.RBAC != nil {
+		for role := range cfg.RBAC.Roles {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// validateNBIAuth validates the configured API keys and JWT settings
+func validateNBIAuth(auth *config.NBIAuth, roles, tenants []string) error {
+	for _, key := range auth.APIKeys {
+		if key.Name == "" {
+			return fmt.Errorf("NBI API key name is required")
+		}
+		if hash, err := hex.DecodeString(key.Hash); err != nil || len(hash) != sha256.Size {
+			return fmt.Errorf("invalid NBI API key hash for %s: expected hex SHA-256", key.Name)
+		}
+		if !contains(roles, key.Role) {
+			return fmt.Errorf("invalid role for NBI API key %s: %s", key.Name, key.Role)
+		}
+		if key.Tenant != "" && !contains(tenants, key.Tenant) {
+			return fmt.Errorf("invalid tenant for NBI API key %s: %s", key.Name, key.Tenant)
+		}
+	}
+
+	jwt := auth.JWT
+	if jwt == nil || !jwt.Enabled {
+		return nil
+	}
+	for _, alg := range jwt.Algorithms {
+		switch alg {
+		case "HS256":
+			if len(jwt.Secret) < 32 {
+				return fmt.Errorf("JWT HS256 requires a secret of at least 32 bytes")
+			}
+		case "RS256":
+			if jwt.JWKSFile == "" {
+				return fmt.Errorf("JWT RS256 requires a jwksFile")
+			}
+			if _, err := os.Stat(jwt.JWKSFile); err != nil {
+				return fmt.Errorf("JWKS file not found: %s", jwt.JWKSFile)
+			}
+		default:
+			return fmt.Errorf("invalid JWT algorithm: %s (HS256 or RS256)", alg)
+		}
+	}
+	if !contains(roles, jwt.DefaultRole) {
+		return fmt.Errorf("invalid JWT defaultRole: %s", jwt.DefaultRole)
+	}
+	if jwt.Leeway < 0 {
+		return fmt.Errorf("invalid JWT leeway: %s", jwt.Leeway)
+	}
+	return nil
+}
+
+// validateUIAuth checks the session timeouts, lockout policy and single
+// sign-on of the UI
+func validateUIAuth(auth *config.UIAuth, roles, tenants []string) error {
+	if auth.IdleTimeout < time.Minute {
+		return fmt.Errorf("invalid UI auth idleTimeout: %s (at least 1m)", auth.IdleTimeout)
+	}
+	if auth.AbsoluteTimeout < auth.IdleTimeout {
+		return fmt.Errorf("invalid UI auth absoluteTimeout: %s (at least idleTimeout)", auth.AbsoluteTimeout)
+	}
+	if auth.MaxFailedLogins < 0 {
+		return fmt.Errorf("invalid UI auth maxFailedLogins: %d", auth.MaxFailedLogins)
+	}
+	if auth.LockoutDuration < 0 {
+		return fmt.Errorf("invalid UI auth lockoutDuration: %s", auth.LockoutDuration)
+	}
+	if auth.OIDC != nil && auth.OIDC.Enabled {
+		return validateOIDC(auth.OIDC, roles, tenants)
+	}
+	return nil
+}
+
+// validateOIDC checks the provider, client and role mappings of single
+// sign-on
+func validateOIDC(oidc *config.OIDC, roles, tenants []string) error {
+	issuer, err := url.Parse(oidc.Issuer)
+	if err != nil || (issuer.Scheme != "https" && issuer.Scheme != "http") || issuer.Host == "" {
+		return fmt.Errorf("invalid OIDC issuer: %s", oidc.Issuer)
+	}
+	if oidc.ClientID == "" {
+		return fmt.Errorf("OIDC clientId is required")
+	}
+	redirect, err := url.Parse(oidc.RedirectURL)
+	if err != nil || (redirect.Scheme != "https" && redirect.Scheme != "http") || redirect.Host == "" {
+		return fmt.Errorf("invalid OIDC redirectUrl: %s", oidc.RedirectURL)
+	}
+	for _, mapping := range oidc.RoleMappings {
+		if mapping.Group == "" {
+			return fmt.Errorf("OIDC role mapping requires a group")
+		}
+		if !contains(roles, mapping.Role) {
+			return fmt.Errorf("invalid role for OIDC group %s: %s", mapping.Group, mapping.Role)
+		}
+		if mapping.Tenant != "" && !contains(tenants, mapping.Tenant) {
+			return fmt.Errorf("invalid tenant for OIDC group %s: %s", mapping.Group, mapping.Tenant)
+		}
+	}
+	if oidc.DefaultRole != "" && !contains(roles, oidc.DefaultRole) {
+		return fmt.Errorf("invalid OIDC defaultRole: %s", oidc.DefaultRole)
+	}
+	if oidc.DefaultTenant != "" && !contains(tenants, oidc.DefaultTenant) {
+		return fmt.Errorf("invalid OIDC defaultTenant: %s", oidc.DefaultTenant)
+	}
+	return nil
+}
+
+// validateOrigins checks that the WebSocket origins of a listener are given
+// as scheme://host[:port], the form browsers send
+func validateOrigins(listener string, origins []string) error {
+	for _, origin := range origins {
+		parsed, err := url.Parse(strings.TrimSuffix(origin, "/"))
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" ||
+			parsed.Path != "" || parsed.RawQuery != "" || parsed.User != nil {
+			return fmt.Errorf("invalid %s allowedOrigins entry: %s (scheme://host[:port])", listener, origin)
+		}
+	}
+	return nil
+}
+
+// validateSyslog validates the syslog collector and message header fields
+func validateSyslog(syslog *config.Syslog) error {
+	if !contains([]string{"udp", "tcp", "tls"}, syslog.Network) {
+		return fmt.Errorf("invalid syslog network: %s (udp, tcp or tls)", syslog.Network)
+	}
+	if _, _, err := net.SplitHostPort(syslog.Address); err != nil {
+		return fmt.Errorf("invalid syslog address: %s", syslog.Address)
+	}
+	if syslog.TLS != nil && (syslog.TLS.Cert == "") != (syslog.TLS.Key == "") {
+		return fmt.Errorf("syslog TLS requires both a cert and a key")
+	}
+
+	validFacilities := []string{
+		"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp", "cron",
+		"authpriv", "ftp", "local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+	}
+	if !contains(validFacilities, syslog.Facility) {
+		return fmt.Errorf("invalid syslog facility: %s", syslog.Facility)
+	}
+	if !contains(validFacilities, syslog.AuditFacility) {
+		return fmt.Errorf("invalid syslog audit facility: %s", syslog.AuditFacility)
+	}
+
+	// RFC 5424 header fields are printable US-ASCII without spaces
+	for field, value := range map[string]string{"hostname": syslog.Hostname, "appName": syslog.AppName} {
+		for _, r := range value {
+			if r < '!' || r > '~' {
+				return fmt.Errorf("invalid syslog %s: %q", field, value)
+			}
+		}
+	}
+	if len(syslog.Hostname) > 255 || len(syslog.AppName) > 48 {
+		return fmt.Errorf("syslog hostname or appName too long")
+	}
+	for _, r := range syslog.EnterpriseID {
+		if (r < '0' || r > '9') && r != '.' {
+			return fmt.Errorf("invalid syslog enterpriseId: %s", syslog.EnterpriseID)
+		}
+	}
+
+	if syslog.BufferSize < 1 {
+		return fmt.Errorf("invalid syslog bufferSize: %d", syslog.BufferSize)
+	}
+	if syslog.WriteTimeout < 0 || syslog.ReconnectBackoff < 0 || syslog.MaxReconnectBackoff < syslog.ReconnectBackoff {
+		return fmt.Errorf("invalid syslog timeouts")
+	}
+	return nil
+}
+
+// validateEmail validates the SMTP server, the recipients and the digest
+// schedules
+func validateEmail(email *config.Email) error {
+	if email.SMTP.Host == "" {
+		return fmt.Errorf("email SMTP host is required")
+	}
+	if !contains([]string{"starttls", "tls", "none"}, email.SMTP.Security) {
+		return fmt.Errorf("invalid email SMTP security: %s (starttls, tls or none)", email.SMTP.Security)
+	}
+	if email.SMTP.Port < 1 || email.SMTP.Port > 65535 {
+		return fmt.Errorf("invalid email SMTP port: %d", email.SMTP.Port)
+	}
+	if _, err := mail.ParseAddress(email.From); err != nil {
+		return fmt.Errorf("invalid email from address: %s", email.From)
+	}
+	if email.MaxPerHour < 1 {
+		return fmt.Errorf("invalid email maxPerHour: %d", email.MaxPerHour)
+	}
+
+	digestTypes := []string{"offline-devices", "unacknowledged-faults", "firmware-compliance"}
+	validSeverities := []string{"critical", "major", "minor", "warning", "info"}
+	names := make(map[string]bool)
+	for _, recipient := range email.Recipients {
+		if recipient.Name == "" || names[recipient.Name] {
+			return fmt.Errorf("email recipient names must be unique and not empty: %q", recipient.Name)
+		}
+		names[recipient.Name] = true
+
+		if len(recipient.To) == 0 {
+			return fmt.Errorf("email recipient %s has no addresses", recipient.Name)
+		}
+		for _, address := range recipient.To {
+			if _, err := mail.ParseAddress(address); err != nil {
+				return fmt.Errorf("invalid email recipient %s address: %s", recipient.Name, address)
+			}
+		}
+		for _, severity := range recipient.Severities {
+			if !contains(validSeverities, severity) {
+				return fmt.Errorf("invalid email recipient %s severity: %s", recipient.Name, severity)
+			}
+		}
+		for _, digest := range recipient.Digests {
+			if !contains(digestTypes, digest) {
+				return fmt.Errorf("invalid email recipient %s digest: %s", recipient.Name, digest)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, digest := range email.Digests {
+		if !contains(digestTypes, digest.Type) || seen[digest.Type] {
+			return fmt.Errorf("invalid or duplicate email digest type: %s", digest.Type)
+		}
+		seen[digest.Type] = true
+		if _, err := time.Parse("15:04", digest.At); err != nil {
+			return fmt.Errorf("invalid email digest %s time: %s (HH:MM)", digest.Type, digest.At)
+		}
+	}
+
+	for name := range email.Templates {
+		if name != "alert" && !contains(digestTypes, name) {
+			return fmt.Errorf("invalid email template: %s (alert or a digest type)", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+func TestAnonymousRoleDefault(t *testing.T) {
+	cfg := &config.Config{UI: &config.UI{}}
+	applyDefaults(cfg)
+	if cfg.UI.AnonymousRole != models.RoleViewer {
+		t.Errorf("anonymousRole defaults to %s, want %s", cfg.UI.AnonymousRole, models.RoleViewer)
+	}
+
+	cfg = &config.Config{UI: &config.UI{AnonymousRole: models.RoleOperator}}
+	applyDefaults(cfg)
+	if cfg.UI.AnonymousRole != models.RoleOperator {
+		t.Errorf("configured anonymousRole replaced by %s", cfg.UI.AnonymousRole)
+	}
+}
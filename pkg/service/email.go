@@ -0,0 +1,584 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Email digest types
+const (
+	digestOfflineDevices       = "offline-devices"
+	digestUnacknowledgedFaults = "unacknowledged-faults"
+	digestFirmwareCompliance   = "firmware-compliance"
+)
+
+// emailPendingAlerts is the number of alerts a recipient may have waiting
+// for the rate limit, the oldest are dropped beyond it
+const emailPendingAlerts = 100
+
+// severityRanks orders fault severities, most severe first
+var severityRanks = map[string]int{
+	models.SeverityCritical: 0,
+	models.SeverityMajor:    1,
+	models.SeverityMinor:    2,
+	models.SeverityWarning:  3,
+	models.SeverityInfo:     4,
+}
+
+// EmailService sends immediate fault alerts and scheduled digests over SMTP,
+// routed to recipients by severity, device tags and escalation groups
+type EmailService struct {
+	config     *config.Email
+	appContext *appContext.Context
+	templates  map[string]*emailTemplate
+	tlsConfig  *tls.Config
+	gateway    string
+
+	mu         sync.Mutex
+	recipients []*emailRecipient
+	wake       chan struct{}
+}
+
+// emailRecipient is a recipient with its alerts waiting to be sent and the
+// times of the emails sent within the last hour
+type emailRecipient struct {
+	config     config.EmailRecipient
+	severities map[string]bool
+	digests    map[string]bool
+
+	pending []*emailAlert
+	dropped int
+	sent    []time.Time
+}
+
+// emailAlert is a fault raised or escalated, as passed to the alert template
+type emailAlert struct {
+	Type       string
+	Severity   string
+	Title      string
+	Fault      *models.Fault
+	Escalation *models.EscalationEvent
+	Time       time.Time
+}
+
+// emailAlertData is passed to the alert template
+type emailAlertData struct {
+	Gateway string
+	Time    time.Time
+	// Severity is the highest severity of the alerts
+	Severity string
+	Alerts   []*emailAlert
+	// Dropped counts alerts lost to the rate limit since the last email
+	Dropped int
+}
+
+// emailDigestData is passed to the digest templates. Only the list of the
+// digest type is set.
+type emailDigestData struct {
+	Gateway  string
+	Time     time.Time
+	Type     string
+	Devices  []*models.Device
+	Faults   []*models.Fault
+	Firmware []*firmwareDeviation
+}
+
+// firmwareDeviation is a device running a software version that is not
+// approved for its model
+type firmwareDeviation struct {
+	Device   *models.Device
+	Version  string
+	Approved []string
+}
+
+// NewEmailService creates a new email notification channel. It fails on
+// templates that do not parse and TLS material that cannot be loaded.
+func NewEmailService(cfg *config.Email, ctx *appContext.Context) (*EmailService, error) {
+	templates, err := parseEmailTemplates(cfg.Templates)
+	if err != nil {
+		return nil, fmt.Errorf("email template: %w", err)
+	}
+
+	gateway, _ := os.Hostname()
+	if gateway == "" {
+		gateway = "nextranet-gateway"
+	}
+
+	s := &EmailService{
+		config:     cfg,
+		appContext: ctx,
+		templates:  templates,
+		gateway:    gateway,
+		wake:       make(chan struct{}, 1),
+	}
+
+	if cfg.SMTP.Security != "none" {
+		tlsConfig, err := clientTLSConfig(cfg.SMTP.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("email TLS: %w", err)
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = cfg.SMTP.Host
+		}
+		s.tlsConfig = tlsConfig
+	}
+
+	for _, recipientCfg := range cfg.Recipients {
+		recipient := &emailRecipient{
+			config:     recipientCfg,
+			severities: make(map[string]bool),
+			digests:    make(map[string]bool),
+		}
+		for _, severity := range recipientCfg.Severities {
+			recipient.severities[severity] = true
+		}
+		for _, digest := range recipientCfg.Digests {
+			recipient.digests[digest] = true
+		}
+		s.recipients = append(s.recipients, recipient)
+	}
+	return s, nil
+}
+
+// Start sends alerts and digests until the context is cancelled
+func (s *EmailService) Start(ctx context.Context) {
+	logger.NotifyLog.Infof("Starting email notifications to %d recipients...", len(s.recipients))
+
+	filter := appContext.EventFilter{Types: []string{
+		appContext.EventFaultRaised,
+		appContext.EventFaultEscalated,
+	}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeEvents(ctx, s.appContext, "Email notifications", filter, s.route)
+	}()
+
+	next := make(map[string]time.Time, len(s.config.Digests))
+	for _, digest := range s.config.Digests {
+		next[digest.Type] = nextDailyRun(digest.At, time.Now())
+		logger.NotifyLog.Debugf("Next %s email digest at %s", digest.Type, next[digest.Type])
+	}
+
+	// Held back alerts and digests are checked on the ticker, new alerts
+	// wake the loop right away
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logger.NotifyLog.Info("Stopped email notifications")
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+
+		s.sendAlerts()
+
+		now := time.Now()
+		for _, digest := range s.config.Digests {
+			if !now.Before(next[digest.Type]) {
+				s.sendDigest(digest, now)
+				next[digest.Type] = nextDailyRun(digest.At, now)
+			}
+		}
+	}
+}
+
+// route queues an alert for the recipients of a fault event
+func (s *EmailService) route(event *appContext.Event) {
+	if !isNotifiable(event) {
+		return
+	}
+
+	alert := s.alert(event)
+	if alert == nil {
+		return
+	}
+	_, tags := eventDeviceSelector(s.appContext, event)
+
+	s.mu.Lock()
+	for _, recipient := range s.recipients {
+		if !recipient.wantsAlert(alert, tags) {
+			continue
+		}
+		if len(recipient.pending) >= emailPendingAlerts {
+			recipient.pending = recipient.pending[1:]
+			recipient.dropped++
+		}
+		recipient.pending = append(recipient.pending, alert)
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// alert builds the alert of a raised or escalated fault
+func (s *EmailService) alert(event *appContext.Event) *emailAlert {
+	if event.Fault == nil {
+		return nil
+	}
+	alert := &emailAlert{Type: event.Type, Severity: event.Severity, Fault: event.Fault, Time: event.Timestamp}
+
+	if event.Escalation != nil {
+		alert.Escalation = event.Escalation
+		alert.Title = fmt.Sprintf("Fault %s on %s escalated to level %d", event.Fault.Code, faultDeviceName(event.Fault), event.Escalation.Level)
+	} else {
+		alert.Title = fmt.Sprintf("Fault %s raised on %s", event.Fault.Code, faultDeviceName(event.Fault))
+	}
+	return alert
+}
+
+// wantsAlert checks if a recipient is notified of an alert. Escalations go
+// to the groups of their level, raised faults by severity and device tags.
+func (r *emailRecipient) wantsAlert(alert *emailAlert, tags map[string]bool) bool {
+	if alert.Escalation != nil {
+		for _, group := range alert.Escalation.Notify {
+			if group == r.config.Name {
+				return true
+			}
+		}
+		return false
+	}
+	return r.severities[alert.Severity] && r.matchesTags(tags)
+}
+
+// matchesTags checks the tag filter of a recipient against device tags
+func (r *emailRecipient) matchesTags(tags map[string]bool) bool {
+	if len(r.config.Tags) == 0 {
+		return true
+	}
+	for _, tag := range r.config.Tags {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// allow checks the hourly rate limit of a recipient
+func (r *emailRecipient) allow(now time.Time, maxPerHour int) bool {
+	recent := r.sent[:0]
+	for _, sent := range r.sent {
+		if now.Sub(sent) < time.Hour {
+			recent = append(recent, sent)
+		}
+	}
+	r.sent = recent
+	return len(r.sent) < maxPerHour
+}
+
+// sendAlerts sends every recipient the alerts waiting for it as one email,
+// as far as the rate limit allows
+func (s *EmailService) sendAlerts() {
+	now := time.Now()
+	for _, recipient := range s.recipients {
+		s.mu.Lock()
+		if len(recipient.pending) == 0 || !recipient.allow(now, s.config.MaxPerHour) {
+			s.mu.Unlock()
+			continue
+		}
+		alerts, dropped := recipient.pending, recipient.dropped
+		recipient.pending, recipient.dropped = nil, 0
+		recipient.sent = append(recipient.sent, now)
+		s.mu.Unlock()
+
+		data := &emailAlertData{
+			Gateway:  s.gateway,
+			Time:     now,
+			Severity: highestSeverity(alerts),
+			Alerts:   alerts,
+			Dropped:  dropped,
+		}
+		if err := s.deliver(recipient, "alert", data); err != nil {
+			logger.NotifyLog.Errorf("Failed to email %d alerts to %s: %v", len(alerts), recipient.config.Name, err)
+		}
+	}
+}
+
+// sendDigest sends a digest to the recipients subscribed to it, each
+// limited to its devices. Empty digests are not sent.
+func (s *EmailService) sendDigest(digest config.EmailDigest, now time.Time) {
+	var devices []*models.Device
+	var faults []*models.Fault
+	var firmware []*firmwareDeviation
+
+	switch digest.Type {
+	case digestOfflineDevices:
+		devices = s.offlineDevices()
+	case digestUnacknowledgedFaults:
+		faults = s.unacknowledgedFaults()
+	case digestFirmwareCompliance:
+		firmware = s.firmwareDeviations(digest.Firmware)
+	}
+
+	for _, recipient := range s.recipients {
+		if !recipient.digests[digest.Type] {
+			continue
+		}
+
+		data := &emailDigestData{Gateway: s.gateway, Time: now, Type: digest.Type}
+		for _, device := range devices {
+			if recipient.matchesTags(device.Tags) {
+				data.Devices = append(data.Devices, device)
+			}
+		}
+		for _, fault := range faults {
+			if recipient.matchesTags(s.deviceTags(fault.DeviceID)) {
+				data.Faults = append(data.Faults, fault)
+			}
+		}
+		for _, deviation := range firmware {
+			if recipient.matchesTags(deviation.Device.Tags) {
+				data.Firmware = append(data.Firmware, deviation)
+			}
+		}
+
+		if len(data.Devices)+len(data.Faults)+len(data.Firmware) == 0 {
+			logger.NotifyLog.Debugf("Skipping empty %s digest for %s", digest.Type, recipient.config.Name)
+			continue
+		}
+
+		s.mu.Lock()
+		recipient.sent = append(recipient.sent, now)
+		s.mu.Unlock()
+
+		if err := s.deliver(recipient, digest.Type, data); err != nil {
+			logger.NotifyLog.Errorf("Failed to email %s digest to %s: %v", digest.Type, recipient.config.Name, err)
+			continue
+		}
+		logger.NotifyLog.Infof("Sent %s digest to %s", digest.Type, recipient.config.Name)
+	}
+}
+
+// offlineDevices returns the devices that are offline, longest silent first
+func (s *EmailService) offlineDevices() []*models.Device {
+	var offline []*models.Device
+	for _, device := range s.appContext.GetAllDevices() {
+		status := device.Status.ConnectionStatus
+		if status == models.ConnectionStatusOffline || (status == "" && !device.Status.Online) {
+			offline = append(offline, device)
+		}
+	}
+	sort.Slice(offline, func(i, j int) bool {
+		return offline[i].LastInform.Before(offline[j].LastInform)
+	})
+	return offline
+}
+
+// unacknowledgedFaults returns the active faults nobody acknowledged, most
+// severe and then oldest first
+func (s *EmailService) unacknowledgedFaults() []*models.Fault {
+	var faults []*models.Fault
+	for _, fault := range s.appContext.GetActiveFaults() {
+		if fault.Status == models.FaultStatusActive && !fault.Suppressed {
+			faults = append(faults, fault)
+		}
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		ri, rj := severityRank(faults[i].Severity), severityRank(faults[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return faults[i].Timestamp.Before(faults[j].Timestamp)
+	})
+	return faults
+}
+
+// firmwareDeviations returns the devices of a baseline model that run a
+// version not approved for it
+func (s *EmailService) firmwareDeviations(baselines []config.FirmwareBaseline) []*firmwareDeviation {
+	approved := make(map[string][]string, len(baselines))
+	for _, baseline := range baselines {
+		approved[baseline.Model] = baseline.Versions
+	}
+
+	var deviations []*firmwareDeviation
+	for _, device := range s.appContext.GetAllDevices() {
+		versions, ok := approved[device.DeviceID.ModelName]
+		if !ok || slices.Contains(versions, device.DeviceID.SoftwareVersion) {
+			continue
+		}
+		deviations = append(deviations, &firmwareDeviation{
+			Device:   device,
+			Version:  device.DeviceID.SoftwareVersion,
+			Approved: versions,
+		})
+	}
+	sort.Slice(deviations, func(i, j int) bool {
+		return deviations[i].Device.ID < deviations[j].Device.ID
+	})
+	return deviations
+}
+
+// deviceTags returns the tags of a device, nil for unknown devices
+func (s *EmailService) deviceTags(deviceID string) map[string]bool {
+	if device, exists := s.appContext.GetDevice(deviceID); exists {
+		return device.Tags
+	}
+	return nil
+}
+
+// deliver renders a template and sends the result to a recipient
+func (s *EmailService) deliver(recipient *emailRecipient, templateName string, data interface{}) error {
+	subject, body, err := s.templates[templateName].render(data)
+	if err != nil {
+		return fmt.Errorf("template %s: %w", templateName, err)
+	}
+	return s.send(recipient.config.To, subject, body)
+}
+
+// send delivers one email over SMTP
+func (s *EmailService) send(to []string, subject, body string) error {
+	smtpCfg := s.config.SMTP
+	address := net.JoinHostPort(smtpCfg.Host, strconv.Itoa(smtpCfg.Port))
+	dialer := &net.Dialer{Timeout: smtpCfg.Timeout}
+
+	var conn net.Conn
+	var err error
+	if smtpCfg.Security == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, s.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpCfg.Timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, smtpCfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello(s.gateway); err != nil {
+		return err
+	}
+	if smtpCfg.Security == "starttls" {
+		if err := client.StartTLS(s.tlsConfig); err != nil {
+			return err
+		}
+	}
+	if smtpCfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", smtpCfg.Username, smtpCfg.Password, smtpCfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	from, _ := mail.ParseAddress(s.config.From)
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		address, _ := mail.ParseAddress(recipient)
+		if err := client.Rcpt(address.Address); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(s.message(from.Address, to, subject, body)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message builds a plain text UTF-8 message
+func (s *EmailService) message(from string, to []string, subject, body string) []byte {
+	var id [12]byte
+	rand.Read(id[:])
+	domain := from[strings.LastIndex(from, "@")+1:]
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id[:]), domain)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	msg.WriteString("Auto-Submitted: auto-generated\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(&msg)
+	writer.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	writer.Close()
+	return msg.Bytes()
+}
+
+// nextDailyRun returns the next local time of day after now, given as
+// HH:MM
+func nextDailyRun(at string, now time.Time) time.Time {
+	clock, _ := time.Parse("15:04", at)
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// faultDeviceName names the device of a fault for people
+func faultDeviceName(fault *models.Fault) string {
+	if fault.DeviceSerial != "" {
+		return fault.DeviceSerial
+	}
+	return fault.DeviceID
+}
+
+// highestSeverity returns the most severe severity of a batch of alerts
+func highestSeverity(alerts []*emailAlert) string {
+	highest := alerts[0].Severity
+	for _, alert := range alerts[1:] {
+		if severityRank(alert.Severity) < severityRank(highest) {
+			highest = alert.Severity
+		}
+	}
+	return highest
+}
+
+// severityRank ranks unknown severities after the known ones
+func severityRank(severity string) int {
+	if rank, ok := severityRanks[severity]; ok {
+		return rank
+	}
+	return len(severityRanks)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+)
+
+// Built-in email templates, replaced per key by notifications.email.templates.
+// Alerts get an emailAlertData, digests an emailDigestData.
+var defaultEmailTemplates = map[string]config.EmailTemplate{
+	"alert": {
+		Subject: `{{if eq (len .Alerts) 1}}{{with index .Alerts 0}}[{{upper .Severity}}] {{.Title}}{{end}}` +
+			`{{else}}[{{upper .Severity}}] {{len .Alerts}} alerts{{end}} - {{.Gateway}}`,
+		Body: `{{range .Alerts}}{{upper .Severity}}: {{.Title}}
+  Device:   {{.Fault.DeviceID}}{{with .Fault.DeviceModel}} ({{.}}){{end}}{{with .Fault.DeviceSerial}}, serial {{.}}{{end}}
+  Code:     {{.Fault.Code}}
+  Message:  {{.Fault.Message}}
+  Raised:   {{datetime .Fault.Timestamp}}
+{{with .Escalation}}  Policy:   {{.PolicyName}}, level {{.Level}}
+{{end}}
+{{end}}{{if .Dropped}}{{.Dropped}} further alerts were dropped while the email rate limit was reached.
+
+{{end}}--
+Sent by {{.Gateway}} at {{datetime .Time}}
+`,
+	},
+	"offline-devices": {
+		Subject: `Offline devices: {{len .Devices}} - {{.Gateway}}`,
+		Body: `Devices offline at {{datetime .Time}}:
+
+{{range .Devices}}  {{.ID}}{{with .DeviceID.ModelName}} ({{.}}){{end}}, last inform {{datetime .LastInform}}
+{{end}}
+--
+Sent by {{.Gateway}}
+`,
+	},
+	"unacknowledged-faults": {
+		Subject: `Unacknowledged faults: {{len .Faults}} - {{.Gateway}}`,
+		Body: `Faults waiting for acknowledgement at {{datetime .Time}}:
+
+{{range .Faults}}  [{{upper .Severity}}] {{.Code}} on {{.DeviceID}} since {{datetime .Timestamp}}
+    {{.Message}}
+{{end}}
+--
+Sent by {{.Gateway}}
+`,
+	},
+	"firmware-compliance": {
+		Subject: `Firmware baseline deviations: {{len .Firmware}} - {{.Gateway}}`,
+		Body: `Devices not running an approved software version at {{datetime .Time}}:
+
+{{range .Firmware}}  {{.Device.ID}} ({{.Device.DeviceID.ModelName}}) runs {{or .Version "an unknown version"}}, approved: {{join .Approved ", "}}
+{{end}}
+--
+Sent by {{.Gateway}}
+`,
+	},
+}
+
+// emailTemplateFuncs are available to all email templates
+var emailTemplateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"join":  strings.Join,
+	"datetime": func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return t.Local().Format("2006-01-02 15:04 MST")
+	},
+}
+
+// emailTemplate is a parsed subject and body pair
+type emailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// parseEmailTemplates parses the built-in templates with the configured
+// ones in their place
+func parseEmailTemplates(configured map[string]config.EmailTemplate) (map[string]*emailTemplate, error) {
+	templates := make(map[string]*emailTemplate, len(defaultEmailTemplates))
+	for name, source := range defaultEmailTemplates {
+		if custom, ok := configured[name]; ok {
+			source = custom
+		}
+
+		subject, err := template.New(name + " subject").Funcs(emailTemplateFuncs).Parse(source.Subject)
+		if err != nil {
+			return nil, err
+		}
+		body, err := template.New(name + " body").Funcs(emailTemplateFuncs).Parse(source.Body)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = &emailTemplate{subject: subject, body: body}
+	}
+	return templates, nil
+}
+
+// render executes the template, the subject is kept to a single line
+func (t *emailTemplate) render(data interface{}) (string, string, error) {
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return "", "", err
+	}
+	return strings.Join(strings.Fields(subject.String()), " "), body.String(), nil
+}
@@ -0,0 +1,439 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// fakeSMTP is an SMTP server that records the messages it accepts
+type fakeSMTP struct {
+	listener net.Listener
+	// tlsConfig enables STARTTLS
+	tlsConfig *tls.Config
+	// username and password enable AUTH PLAIN
+	username, password string
+
+	messages chan *smtpMessage
+}
+
+// smtpMessage is a message as received by the fake server
+type smtpMessage struct {
+	from   string
+	to     []string
+	secure bool
+	user   string
+	header mail.Header
+	body   string
+}
+
+func newFakeSMTP(t *testing.T, tlsConfig *tls.Config, username, password string) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	s := &fakeSMTP{
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		username:  username,
+		password:  password,
+		messages:  make(chan *smtpMessage, 16),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// port returns the port the server listens on
+func (s *fakeSMTP) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve runs the SMTP session of one connection
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 fake ESMTP")
+
+	message := &smtpMessage{}
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, argument, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			extensions := []string{"fake"}
+			if s.tlsConfig != nil && !message.secure {
+				extensions = append(extensions, "STARTTLS")
+			}
+			if s.username != "" {
+				extensions = append(extensions, "AUTH PLAIN")
+			}
+			for i, extension := range extensions {
+				if i < len(extensions)-1 {
+					text.PrintfLine("250-%s", extension)
+				} else {
+					text.PrintfLine("250 %s", extension)
+				}
+			}
+		case "STARTTLS":
+			if s.tlsConfig == nil {
+				text.PrintfLine("502 5.5.1 STARTTLS not supported")
+				continue
+			}
+			text.PrintfLine("220 2.0.0 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+			message = &smtpMessage{secure: true}
+		case "AUTH":
+			mechanism, response, _ := strings.Cut(argument, " ")
+			credentials, _ := base64.StdEncoding.DecodeString(response)
+			fields := strings.Split(string(credentials), "\x00")
+			if mechanism != "PLAIN" || len(fields) != 3 || fields[1] != s.username || fields[2] != s.password {
+				text.PrintfLine("535 5.7.8 Authentication credentials invalid")
+				continue
+			}
+			message.user = fields[1]
+			text.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL":
+			if s.username != "" && message.user == "" {
+				text.PrintfLine("530 5.7.0 Authentication required")
+				continue
+			}
+			message.from = envelopeAddress(argument)
+			text.PrintfLine("250 2.1.0 OK")
+		case "RCPT":
+			message.to = append(message.to, envelopeAddress(argument))
+			text.PrintfLine("250 2.1.5 OK")
+		case "DATA":
+			text.PrintfLine("354 Go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+			if err != nil {
+				text.PrintfLine("554 5.6.0 Malformed message")
+				continue
+			}
+			body, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+			message.header = parsed.Header
+			message.body = string(body)
+			s.messages <- message
+			message = &smtpMessage{secure: message.secure, user: message.user}
+			text.PrintfLine("250 2.0.0 OK")
+		case "RSET", "NOOP":
+			text.PrintfLine("250 2.0.0 OK")
+		case "QUIT":
+			text.PrintfLine("221 2.0.0 Bye")
+			return
+		default:
+			text.PrintfLine("502 5.5.2 Command not recognized")
+		}
+	}
+}
+
+// envelopeAddress extracts the address of a MAIL FROM or RCPT TO argument
+func envelopeAddress(argument string) string {
+	start, end := strings.Index(argument, "<"), strings.Index(argument, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return argument[start+1 : end]
+}
+
+// expectMessage waits for the server to accept a message
+func (s *fakeSMTP) expectMessage(t *testing.T) *smtpMessage {
+	t.Helper()
+	select {
+	case message := <-s.messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+// expectNoMessage checks that the server accepted no further message
+func (s *fakeSMTP) expectNoMessage(t *testing.T) {
+	t.Helper()
+	select {
+	case message := <-s.messages:
+		t.Fatalf("unexpected message %q", message.header.Get("Subject"))
+	default:
+	}
+}
+
+// testCertificate creates a self-signed server certificate for 127.0.0.1
+// and writes it to a CA file
+func testCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "smtp.test"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+// newTestEmailService creates an email service sending through the server
+func newTestEmailService(t *testing.T, server *fakeSMTP, smtpCfg config.SMTP, maxPerHour int) *EmailService {
+	t.Helper()
+	smtpCfg.Host = "127.0.0.1"
+	smtpCfg.Port = server.port()
+	smtpCfg.Timeout = 5 * time.Second
+	if smtpCfg.Security == "" {
+		smtpCfg.Security = "none"
+	}
+
+	s, err := NewEmailService(&config.Email{
+		Enabled:    true,
+		SMTP:       &smtpCfg,
+		From:       "Gateway <gateway@example.net>",
+		MaxPerHour: maxPerHour,
+		Recipients: []config.EmailRecipient{
+			{Name: "noc", To: []string{"NOC <noc@example.net>", "oncall@example.net"}, Severities: []string{models.SeverityCritical, models.SeverityMajor}},
+			{Name: "field", To: []string{"field@example.net"}, Severities: []string{models.SeverityMinor}},
+		},
+	}, appContext.NewContext())
+	if err != nil {
+		t.Fatalf("NewEmailService: %v", err)
+	}
+	s.gateway = "gw-test"
+	return s
+}
+
+func testFaultRaised(id, severity string) *appContext.Event {
+	return &appContext.Event{
+		Type:      appContext.EventFaultRaised,
+		DeviceID:  "A1B2C3-CPE-0001",
+		Severity:  severity,
+		Timestamp: time.Now(),
+		Fault: &models.Fault{
+			ID:           id,
+			DeviceID:     "A1B2C3-CPE-0001",
+			DeviceSerial: "0001",
+			DeviceModel:  "G-240W",
+			Code:         "cwmp.9002",
+			Message:      "Internal error ü",
+			Severity:     severity,
+			Status:       models.FaultStatusActive,
+			Timestamp:    time.Now(),
+		},
+	}
+}
+
+func TestEmailAlert(t *testing.T) {
+	server := newFakeSMTP(t, nil, "", "")
+	s := newTestEmailService(t, server, config.SMTP{}, 10)
+
+	s.route(testFaultRaised("fault-1", models.SeverityCritical))
+	s.sendAlerts()
+
+	message := server.expectMessage(t)
+	if message.from != "gateway@example.net" {
+		t.Errorf("MAIL FROM %s, want gateway@example.net", message.from)
+	}
+	if strings.Join(message.to, ",") != "noc@example.net,oncall@example.net" {
+		t.Errorf("RCPT TO %v, want the addresses of the noc recipient", message.to)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("Subject: %v", err)
+	}
+	headers := map[string]string{
+		"From":                      "Gateway <gateway@example.net>",
+		"To":                        "NOC <noc@example.net>, oncall@example.net",
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "quoted-printable",
+		"Auto-Submitted":            "auto-generated",
+	}
+	for name, value := range headers {
+		if got := message.header.Get(name); got != value {
+			t.Errorf("%s: %q, want %q", name, got, value)
+		}
+	}
+	if subject != "[CRITICAL] Fault cwmp.9002 raised on 0001 - gw-test" {
+		t.Errorf("Subject: %q", subject)
+	}
+	if id := message.header.Get("Message-ID"); !strings.HasSuffix(id, "@example.net>") {
+		t.Errorf("Message-ID %s is not in the sender domain", id)
+	}
+	if _, err := message.header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+	for _, line := range []string{"Device:   A1B2C3-CPE-0001 (G-240W), serial 0001", "Message:  Internal error ü"} {
+		if !strings.Contains(message.body, line) {
+			t.Errorf("body does not contain %q:\n%s", line, message.body)
+		}
+	}
+
+	// Severities nobody subscribed to are not sent
+	s.route(testFaultRaised("fault-2", models.SeverityWarning))
+	s.sendAlerts()
+	server.expectNoMessage(t)
+}
+
+func TestEmailSTARTTLS(t *testing.T) {
+	certificate, caFile := testCertificate(t)
+	server := newFakeSMTP(t, &tls.Config{Certificates: []tls.Certificate{certificate}}, "gateway", "secret")
+	s := newTestEmailService(t, server, config.SMTP{
+		Security: "starttls",
+		Username: "gateway",
+		Password: "secret",
+		TLS:      &config.ClientTLS{CA: caFile},
+	}, 10)
+
+	if err := s.send([]string{"noc@example.net"}, "Test", "Body"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	message := server.expectMessage(t)
+	if !message.secure || message.user != "gateway" {
+		t.Errorf("message sent with TLS %t as %q, want TLS as gateway", message.secure, message.user)
+	}
+}
+
+func TestEmailSTARTTLSFailure(t *testing.T) {
+	certificate, caFile := testCertificate(t)
+
+	// The server does not offer STARTTLS
+	plain := newFakeSMTP(t, nil, "", "")
+	s := newTestEmailService(t, plain, config.SMTP{Security: "starttls", TLS: &config.ClientTLS{CA: caFile}}, 10)
+	if err := s.send([]string{"noc@example.net"}, "Test", "Body"); err == nil {
+		t.Error("sent without STARTTLS")
+	}
+	plain.expectNoMessage(t)
+
+	// The server certificate is not trusted
+	untrusted := newFakeSMTP(t, &tls.Config{Certificates: []tls.Certificate{certificate}}, "", "")
+	s = newTestEmailService(t, untrusted, config.SMTP{Security: "starttls"}, 10)
+	if err := s.send([]string{"noc@example.net"}, "Test", "Body"); err == nil {
+		t.Error("sent to a server with an untrusted certificate")
+	}
+	untrusted.expectNoMessage(t)
+}
+
+func TestEmailAuthFailure(t *testing.T) {
+	certificate, caFile := testCertificate(t)
+	server := newFakeSMTP(t, &tls.Config{Certificates: []tls.Certificate{certificate}}, "gateway", "secret")
+	s := newTestEmailService(t, server, config.SMTP{
+		Security: "starttls",
+		Username: "gateway",
+		Password: "wrong",
+		TLS:      &config.ClientTLS{CA: caFile},
+	}, 10)
+
+	err := s.send([]string{"noc@example.net"}, "Test", "Body")
+	if err == nil || !strings.Contains(err.Error(), "535") {
+		t.Errorf("send returned %v, want the authentication failure", err)
+	}
+	server.expectNoMessage(t)
+}
+
+func TestEmailBatching(t *testing.T) {
+	server := newFakeSMTP(t, nil, "", "")
+	s := newTestEmailService(t, server, config.SMTP{}, 1)
+
+	// Alerts raised before a send go out together, the most severe leads
+	s.route(testFaultRaised("fault-1", models.SeverityMajor))
+	s.route(testFaultRaised("fault-2", models.SeverityCritical))
+	s.route(testFaultRaised("fault-3", models.SeverityMajor))
+	s.sendAlerts()
+
+	message := server.expectMessage(t)
+	if subject := message.header.Get("Subject"); subject != "[CRITICAL] 3 alerts - gw-test" {
+		t.Errorf("Subject: %q", subject)
+	}
+	if count := strings.Count(message.body, "Code:     cwmp.9002"); count != 3 {
+		t.Errorf("body lists %d alerts, want 3", count)
+	}
+	server.expectNoMessage(t)
+
+	// The rate limit holds further alerts back, dropping the oldest beyond
+	// the pending limit
+	for i := 0; i < emailPendingAlerts+2; i++ {
+		s.route(testFaultRaised("held-"+strconv.Itoa(i), models.SeverityMajor))
+	}
+	s.sendAlerts()
+	server.expectNoMessage(t)
+
+	s.mu.Lock()
+	noc := s.recipients[0]
+	if len(noc.pending) != emailPendingAlerts || noc.dropped != 2 {
+		t.Errorf("%d alerts pending and %d dropped, want %d and 2", len(noc.pending), noc.dropped, emailPendingAlerts)
+	}
+
+	// Once the hour has passed they are sent as one email
+	noc.sent[0] = noc.sent[0].Add(-time.Hour)
+	s.mu.Unlock()
+	s.sendAlerts()
+
+	message = server.expectMessage(t)
+	if subject := message.header.Get("Subject"); subject != "[MAJOR] "+strconv.Itoa(emailPendingAlerts)+" alerts - gw-test" {
+		t.Errorf("Subject: %q", subject)
+	}
+	if !strings.Contains(message.body, "2 further alerts were dropped") {
+		t.Errorf("body does not report the dropped alerts:\n%s", message.body)
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// EscalationService periodically escalates unacknowledged faults according
+// to the escalation policies kept in the application context
+type EscalationService struct {
+	config     *config.FaultEscalation
+	appContext *appContext.Context
+}
+
+// NewEscalationService creates a new escalation service instance
+func NewEscalationService(cfg *config.FaultEscalation, ctx *appContext.Context) *EscalationService {
+	return &EscalationService{
+		config:     cfg,
+		appContext: ctx,
+	}
+}
+
+// LoadPolicies registers the policies defined in the configuration file
+func (s *EscalationService) LoadPolicies() {
+	for _, p := range s.config.Policies {
+		policy := &models.EscalationPolicy{
+			Name:       p.Name,
+			Enabled:    true,
+			Severities: p.Severities,
+			Tags:       p.Tags,
+		}
+		for _, l := range p.Levels {
+			policy.Levels = append(policy.Levels, models.EscalationLevel{
+				Level:        l.Level,
+				AfterMinutes: int(l.After / time.Minute),
+				Notify:       l.Notify,
+			})
+		}
+
+		if err := s.appContext.AddEscalationPolicy(policy); err != nil {
+			logger.FaultLog.Errorf("Invalid escalation policy %s: %v", p.Name, err)
+			continue
+		}
+		logger.FaultLog.Infof("Loaded escalation policy %s", p.Name)
+	}
+}
+
+// Start runs the escalation worker until the context is cancelled
+func (s *EscalationService) Start(ctx context.Context) {
+	logger.FaultLog.Info("Starting fault escalation worker...")
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FaultLog.Info("Stopping fault escalation worker...")
+			return
+		case <-ticker.C:
+			s.evaluate()
+		}
+	}
+}
+
+// evaluate escalates due faults and dispatches the escalations
+func (s *EscalationService) evaluate() {
+	for _, event := range s.appContext.EscalateFaults(time.Now()) {
+		s.notify(event)
+	}
+}
+
+// notify dispatches an escalation on the event bus. The notification
+// channels deliver it, email to the recipients named by the groups of its
+// level.
+func (s *EscalationService) notify(event appContext.Event) {
+	escalation := event.Escalation
+	logger.FaultLog.Warnf("Fault %s (%s) on device %s escalated to level %d by policy %s, notifying %v",
+		escalation.FaultID, escalation.Severity, escalation.DeviceID, escalation.Level, escalation.PolicyName, escalation.Notify)
+
+	s.appContext.PublishEvent(event)
+}
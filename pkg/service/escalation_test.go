@@ -0,0 +1,78 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+func TestEscalationDispatch(t *testing.T) {
+	server := newFakeSMTP(t, nil, "", "")
+	email := newTestEmailService(t, server, config.SMTP{}, 10)
+	appCtx := email.appContext
+
+	s := NewEscalationService(&config.FaultEscalation{
+		Enabled:  true,
+		Interval: time.Minute,
+		Policies: []config.EscalationPolicy{{
+			Name:       "Critical unacknowledged",
+			Severities: []string{models.SeverityCritical},
+			Levels: []config.EscalationLevel{
+				{Level: 1, After: 15 * time.Minute, Notify: []string{"field"}},
+				{Level: 2, After: time.Hour, Notify: []string{"noc"}},
+			},
+		}},
+	}, appCtx)
+	s.LoadPolicies()
+
+	sub, err := appCtx.SubscribeEvents(appContext.EventFilter{Types: []string{appContext.EventFaultEscalated}}, 16, 0)
+	if err != nil {
+		t.Fatalf("SubscribeEvents: %v", err)
+	}
+	defer sub.Close()
+
+	appCtx.AddFault(&models.Fault{
+		ID:           "fault-1",
+		DeviceID:     "A1B2C3-CPE-0001",
+		DeviceSerial: "0001",
+		Code:         "cwmp.9002",
+		Severity:     models.SeverityCritical,
+		Status:       models.FaultStatusActive,
+		Timestamp:    time.Now().Add(-20 * time.Minute),
+	})
+	s.evaluate()
+
+	var event appContext.Event
+	select {
+	case event = <-sub.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("escalation not dispatched")
+	}
+	if event.Escalation == nil || event.Escalation.Level != 1 || event.Fault == nil || event.Fault.EscalationLevel != 1 {
+		t.Fatalf("unexpected escalation event %+v", event)
+	}
+
+	// The email channel delivers it to the group of the level only
+	email.route(&event)
+	email.sendAlerts()
+	message := server.expectMessage(t)
+	if len(message.to) != 1 || message.to[0] != "field@example.net" {
+		t.Errorf("escalation emailed to %v, want the field group", message.to)
+	}
+	if subject := message.header.Get("Subject"); !strings.Contains(subject, "escalated to level 1") {
+		t.Errorf("Subject: %q", subject)
+	}
+	server.expectNoMessage(t)
+
+	// A fault is dispatched once per level
+	s.evaluate()
+	select {
+	case event := <-sub.C:
+		t.Errorf("level %d dispatched again", event.Escalation.Level)
+	default:
+	}
+}
@@ -157,6 +157,19 @@ func (s *GenieACSService) checkFSConnection() bool {
 
 // GetDevices retrieves devices from GenieACS
 func (s *GenieACSService) GetDevices(filter *models.DeviceFilter) ([]*models.Device, error) {
+	// IP filters GenieACS cannot express are applied here, which needs the
+	// full result before paginating
+	var ipRange *models.IPRange
+	var pagination *models.PaginationOptions
+	if filter != nil && filter.IPRange != nil {
+		if _, ok := ipRangeQuery(filter.IPRange); !ok {
+			scoped := *filter
+			ipRange, pagination = filter.IPRange, filter.Pagination
+			scoped.IPRange, scoped.Pagination = nil, nil
+			filter = &scoped
+		}
+	}
+
 	query := s.buildDeviceQuery(filter)
 
 	req, err := http.NewRequest("GET", s.config.NBIURL+"/devices"+query, nil)
@@ -187,9 +200,38 @@ func (s *GenieACSService) GetDevices(filter *models.DeviceFilter) ([]*models.Dev
 		devices = append(devices, device)
 	}
 
+	if ipRange != nil {
+		devices = filterByIPRange(devices, ipRange)
+		devices = paginateDevices(devices, pagination)
+	}
+
 	return devices, nil
 }
 
+// paginateDevices returns one page of devices
+func paginateDevices(devices []*models.Device, pagination *models.PaginationOptions) []*models.Device {
+	if pagination == nil {
+		return devices
+	}
+
+	limit := pagination.PageSize
+	if limit == 0 {
+		limit = 20
+	}
+	start := (pagination.Page - 1) * limit
+	if start < 0 {
+		start = 0
+	}
+	if start > len(devices) {
+		start = len(devices)
+	}
+	end := start + limit
+	if end > len(devices) {
+		end = len(devices)
+	}
+	return devices[start:end]
+}
+
 // GetDevice retrieves a single device from GenieACS
 func (s *GenieACSService) GetDevice(deviceID string) (*models.Device, error) {
 	// Build query parameter: {"_id":"deviceID"}
@@ -254,21 +296,64 @@ func (s *GenieACSService) CreateTask(deviceID string, task map[string]interface{
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
+		s.publishTaskEvent(appContext.EventTaskFailed, deviceID, task, nil, models.TaskStatusFailed)
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 	defer resp.Body.Close()
 
+	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create task: %s", string(body))
+		s.publishTaskEvent(appContext.EventTaskFailed, deviceID, task, respBody, models.TaskStatusFailed)
+		return fmt.Errorf("failed to create task: %s", string(respBody))
+	}
+
+	// GenieACS answers 200 once the device ran the task during a connection
+	// request and 202 when it stays queued for the next inform, the
+	// inventory sync follows up on queued tasks
+	if resp.StatusCode == http.StatusOK {
+		s.publishTaskEvent(appContext.EventTaskCompleted, deviceID, task, respBody, models.TaskStatusCompleted)
+	} else {
+		s.publishTaskEvent(appContext.EventTaskQueued, deviceID, task, respBody, models.TaskStatusQueued)
 	}
 
 	return nil
 }
 
-// GetTasks retrieves tasks for a device
+// publishTaskEvent announces the outcome of a task creation. The GenieACS
+// response carries the stored task when there is one.
+func (s *GenieACSService) publishTaskEvent(eventType, deviceID string, request map[string]interface{}, response []byte, status string) {
+	if s.appContext == nil {
+		return
+	}
+
+	var genieTask map[string]interface{}
+	if err := json.Unmarshal(response, &genieTask); err != nil || genieTask == nil {
+		genieTask = request
+	}
+
+	task := s.convertGenieTask(genieTask)
+	task.DeviceID = deviceID
+	task.Status = status
+	if task.Timestamp.IsZero() {
+		task.Timestamp = time.Now()
+	}
+
+	s.appContext.PublishEvent(appContext.Event{
+		Type:     eventType,
+		DeviceID: deviceID,
+		Task:     task,
+	})
+}
+
+// GetTasks retrieves the tasks of a device, or of all devices when deviceID
+// is empty
 func (s *GenieACSService) GetTasks(deviceID string) ([]*models.Task, error) {
-	req, err := http.NewRequest("GET", s.config.NBIURL+"/tasks?device="+url.QueryEscape(deviceID), nil)
+	query := ""
+	if deviceID != "" {
+		query = "?device=" + url.QueryEscape(deviceID)
+	}
+
+	req, err := http.NewRequest("GET", s.config.NBIURL+"/tasks"+query, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -669,22 +754,39 @@ func (s *GenieACSService) buildDeviceQuery(filter *models.DeviceFilter) string {
 	query := url.Values{}
 
 	// Add filters
-	filters := []string{}
+	filters := map[string]interface{}{}
 
 	if filter.Manufacturer != "" {
-		filters = append(filters, fmt.Sprintf(`_deviceId._Manufacturer:"%s"`, filter.Manufacturer))
+		filters["_deviceId._Manufacturer"] = filter.Manufacturer
 	}
 
 	if filter.ModelName != "" {
-		filters = append(filters, fmt.Sprintf(`_deviceId._ModelName:"%s"`, filter.ModelName))
+		filters["_deviceId._ModelName"] = filter.ModelName
 	}
 
 	if filter.ProductClass != "" {
-		filters = append(filters, fmt.Sprintf(`_deviceId._ProductClass:"%s"`, filter.ProductClass))
+		filters["_deviceId._ProductClass"] = filter.ProductClass
+	}
+
+	if filter.IPRange != nil {
+		if clause, ok := ipRangeQuery(filter.IPRange); ok {
+			for key, value := range clause {
+				filters[key] = value
+			}
+		}
+	}
+
+	if filter.Tenant != "" {
+		tenant, _ := s.appContext.GetTenant(filter.Tenant)
+		for key, value := range tenantQuery(tenant) {
+			filters[key] = value
+		}
 	}
 
 	if len(filters) > 0 {
-		query.Add("query", "{"+strings.Join(filters, ",")+"}")
+		if encoded, err := json.Marshal(filters); err == nil {
+			query.Add("query", string(encoded))
+		}
 	}
 
 	// Add pagination
@@ -721,9 +823,6 @@ func (s *GenieACSService) convertGenieDevice(genieDevice map[string]interface{})
 		DeviceInfo: genieDevice,
 		Parameters: make(map[string]models.Parameter),
 		Tags:       make(map[string]bool),
-		Status: models.DeviceStatus{
-			Online: true, // Will be updated based on last inform
-		},
 	}
 
 	// Extract device ID
@@ -750,17 +849,13 @@ func (s *GenieACSService) convertGenieDevice(genieDevice map[string]interface{})
 		if t, err := time.Parse(time.RFC3339, lastInform); err == nil {
 			device.LastInform = t
 			device.Status.LastSeen = t
-
-			// Update online status based on last inform
-			if time.Since(t) > 5*time.Minute {
-				device.Status.Online = false
-				device.Status.ConnectionStatus = "offline"
-			} else {
-				device.Status.ConnectionStatus = "online"
-			}
 		}
 	}
 
+	// Update online status from the device's own inform interval
+	device.Status.InformInterval = s.extractInformInterval(genieDevice)
+	updateHeartbeat(s.config.Heartbeat, &device.Status, device.LastInform, time.Now())
+
 	if lastBoot, ok := genieDevice["_lastBoot"].(string); ok {
 		if t, err := time.Parse(time.RFC3339, lastBoot); err == nil {
 			device.LastBoot = t
@@ -792,11 +887,11 @@ func (s *GenieACSService) convertGenieDevice(genieDevice map[string]interface{})
 	device.Parameters = s.extractParameters(genieDevice)
 
 	// Extract IP addresses
-	if ipAddr := s.getParameterValue(genieDevice, "InternetGatewayDevice.LANDevice.1.LANHostConfigManagement.IPInterface.1.IPInterfaceIPAddress"); ipAddr != "" {
+	if ipAddr := s.getParameterValue(genieDevice, lanIPPath); ipAddr != "" {
 		device.DeviceID.IPAddress = ipAddr
 	}
 
-	if extIPAddr := s.getParameterValue(genieDevice, "InternetGatewayDevice.WANDevice.1.WANConnectionDevice.1.WANIPConnection.1.ExternalIPAddress"); extIPAddr != "" {
+	if extIPAddr := s.getParameterValue(genieDevice, externalIPPath); extIPAddr != "" {
 		device.DeviceID.ExternalIPAddress = extIPAddr
 	}
 
@@ -940,13 +1035,30 @@ func (s *GenieACSService) getString(m map[string]interface{}, key string) string
 	return ""
 }
 
-// getParameterValue extracts a parameter value as string
+// getParameterValue extracts a parameter value as string. Both flat keys and
+// the nested document GenieACS returns are supported.
 func (s *GenieACSService) getParameterValue(genieDevice map[string]interface{}, path string) string {
 	if param, ok := genieDevice[path].(map[string]interface{}); ok {
 		if val, ok := param["_value"]; ok {
 			return fmt.Sprintf("%v", val)
 		}
 	}
+
+	var current interface{} = genieDevice
+	for _, part := range strings.Split(path, ".") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		if current, ok = node[part]; !ok {
+			return ""
+		}
+	}
+	if param, ok := current.(map[string]interface{}); ok {
+		if val, ok := param["_value"]; ok && val != nil {
+			return fmt.Sprintf("%v", val)
+		}
+	}
 	return ""
 }
 
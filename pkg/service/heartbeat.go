@@ -0,0 +1,73 @@
+package service
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// informIntervalPaths lists where TR-098 and TR-181 data models report the
+// periodic inform interval
+var informIntervalPaths = []string{
+	"InternetGatewayDevice.ManagementServer.PeriodicInformInterval",
+	"Device.ManagementServer.PeriodicInformInterval",
+}
+
+// extractInformInterval returns the periodic inform interval of a device in
+// seconds, or 0 if the CPE does not report one
+func (s *GenieACSService) extractInformInterval(genieDevice map[string]interface{}) int {
+	for _, path := range informIntervalPaths {
+		value := s.getParameterValue(genieDevice, path)
+		if value == "" {
+			continue
+		}
+		if interval, err := strconv.Atoi(value); err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return 0
+}
+
+// updateHeartbeat derives the connection state of a device from the time
+// since its last inform, measured in its own inform intervals.
+//
+// A device is online while the next inform is within interval x tolerance,
+// late once that passes, and offline after MissedInforms intervals without
+// an inform.
+func updateHeartbeat(cfg *config.Heartbeat, status *models.DeviceStatus, lastInform, now time.Time) {
+	if lastInform.IsZero() {
+		status.Online = false
+		status.ConnectionStatus = models.ConnectionStatusOffline
+		status.MissedInforms = 0
+		return
+	}
+
+	interval := cfg.DefaultInterval
+	if status.InformInterval > 0 {
+		interval = time.Duration(status.InformInterval) * time.Second
+	}
+
+	// The grace period applies once, missed informs count whole intervals
+	// after it
+	grace := time.Duration(float64(interval) * (cfg.Tolerance - 1))
+	missed := 0
+	if elapsed := now.Sub(lastInform) - grace; elapsed > interval {
+		missed = int(math.Floor(float64(elapsed) / float64(interval)))
+	}
+
+	status.MissedInforms = missed
+	switch {
+	case missed == 0:
+		status.Online = true
+		status.ConnectionStatus = models.ConnectionStatusOnline
+	case missed < cfg.MissedInforms:
+		status.Online = true
+		status.ConnectionStatus = models.ConnectionStatusLate
+	default:
+		status.Online = false
+		status.ConnectionStatus = models.ConnectionStatusOffline
+	}
+}
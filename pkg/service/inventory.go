@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+	"github.com/nextranet/gateway/c-plane/internal/rules"
+)
+
+// InventoryService periodically pulls devices and faults from GenieACS into
+// the application context, tracks device heartbeats and evaluates the
+// gateway alarm rules
+type InventoryService struct {
+	config       *config.GenieACS
+	appContext   *appContext.Context
+	genieService *GenieACSService
+	engine       *rules.Engine
+
+	// Open offline fault per device, only touched by the sync loop
+	offlineFaults map[string]string
+
+	// unreachableSince is when fetching devices from GenieACS started to
+	// fail, zero while it works, and recoveredAt when the last outage ended.
+	// Only touched by the sync loop.
+	unreachableSince time.Time
+	recoveredAt      time.Time
+
+	// Tasks GenieACS queued for the next inform by task ID, followed up by
+	// the sync loop until they complete or fail
+	tasksMu     sync.Mutex
+	queuedTasks map[string]*models.Task
+}
+
+// NewInventoryService creates a new inventory sync service instance
+func NewInventoryService(cfg *config.GenieACS, ctx *appContext.Context) *InventoryService {
+	return &InventoryService{
+		config:       cfg,
+		appContext:   ctx,
+		genieService: NewGenieACSService(cfg, ctx),
+		engine:       rules.NewEngine(),
+
+		offlineFaults: make(map[string]string),
+		queuedTasks:   make(map[string]*models.Task),
+	}
+}
+
+// LoadRules registers the alarm rules defined in the configuration file.
+// Rule IDs are derived from the name so faults raised before a restart stay
+// linked to their rule.
+func (s *InventoryService) LoadRules(ruleConfigs []config.AlarmRule) {
+	for _, r := range ruleConfigs {
+		rule := &models.AlarmRule{
+			ID:                uuid.NewSHA1(uuid.NameSpaceOID, []byte("alarm-rule:"+r.Name)).String(),
+			Name:              r.Name,
+			Description:       r.Description,
+			Enabled:           true,
+			Code:              r.Code,
+			Severity:          r.Severity,
+			Condition:         r.Condition,
+			ClearCondition:    r.ClearCondition,
+			RaiseAfterSeconds: int(r.RaiseAfter / time.Second),
+			ClearAfterSeconds: int(r.ClearAfter / time.Second),
+			Tags:              r.Tags,
+			Models:            r.Models,
+		}
+
+		if err := s.appContext.AddAlarmRule(rule); err != nil {
+			logger.FaultLog.Errorf("Invalid alarm rule %s: %v", r.Name, err)
+			continue
+		}
+		logger.FaultLog.Infof("Loaded alarm rule %s", r.Name)
+	}
+}
+
+// Start runs the inventory sync until the context is cancelled
+func (s *InventoryService) Start(ctx context.Context) {
+	logger.GenieACSLog.Info("Starting inventory sync...")
+
+	filter := appContext.EventFilter{Types: []string{appContext.EventTaskQueued}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeEvents(ctx, s.appContext, "Task follow-up", filter, s.trackTask)
+	}()
+
+	s.restore()
+	s.sync()
+
+	ticker := time.NewTicker(s.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logger.GenieACSLog.Info("Stopping inventory sync...")
+			return
+		case <-ticker.C:
+			s.sync()
+		}
+	}
+}
+
+// restore adopts gateway faults that were still open when the gateway
+// stopped, so they are cleared rather than raised a second time
+func (s *InventoryService) restore() {
+	for ruleID, faults := range s.appContext.GetOpenRuleFaults() {
+		for _, fault := range faults {
+			s.engine.Restore(ruleID, fault.DeviceID, fault.ID)
+		}
+	}
+
+	for _, fault := range s.appContext.GetActiveFaults() {
+		if fault.Channel == models.FaultChannelHeartbeat {
+			s.offlineFaults[fault.DeviceID] = fault.ID
+		}
+	}
+}
+
+// sync refreshes devices and faults and evaluates heartbeats and alarm
+// rules. While GenieACS is unreachable the inventory is stale, so neither
+// heartbeats nor alarm rules are evaluated until it answers again.
+func (s *InventoryService) sync() {
+	now := time.Now()
+
+	devices, err := s.genieService.GetDevices(nil)
+	if err != nil {
+		if s.unreachableSince.IsZero() {
+			s.unreachableSince = now
+			logger.GenieACSLog.Warnf("Inventory sync failed to fetch devices, pausing heartbeat and alarm rule evaluation: %v", err)
+		} else {
+			logger.GenieACSLog.Debugf("Inventory sync failed to fetch devices: %v", err)
+		}
+		return
+	}
+	if !s.unreachableSince.IsZero() {
+		logger.GenieACSLog.Infof("GenieACS answered again after %s, resuming heartbeat and alarm rule evaluation",
+			now.Sub(s.unreachableSince).Round(time.Second))
+		s.unreachableSince = time.Time{}
+		s.recoveredAt = now
+	}
+
+	for _, device := range devices {
+		s.appContext.AddDevice(device)
+	}
+
+	faults, err := s.genieService.GetFaults("")
+	if err != nil {
+		logger.GenieACSLog.Warnf("Inventory sync failed to fetch faults: %v", err)
+	} else {
+		for _, fault := range faults {
+			s.appContext.AddFault(fault)
+		}
+
+		// Queued tasks are only settled on a complete view of GenieACS
+		s.followTasks(devices, faults, now)
+	}
+
+	s.checkHeartbeats(devices, now)
+	s.evaluateRules(devices, now)
+}
+
+// trackTask remembers a task GenieACS queued for the next inform of an
+// offline device. Tasks without an ID cannot be followed up.
+func (s *InventoryService) trackTask(event *appContext.Event) {
+	if event.Task == nil || event.Task.ID == "" {
+		logger.GenieACSLog.Debugf("Cannot follow up queued task on device %s without a task ID", event.DeviceID)
+		return
+	}
+
+	task := *event.Task
+	if task.QueuedAt.IsZero() {
+		task.QueuedAt = event.Timestamp
+	}
+
+	s.tasksMu.Lock()
+	s.queuedTasks[task.ID] = &task
+	s.tasksMu.Unlock()
+}
+
+// followTasks settles the queued tasks GenieACS no longer holds or recorded
+// a fault for. A task that faulted failed even though GenieACS retries it,
+// a task gone after the device informed again completed, and a task gone
+// before that was deleted or expired.
+func (s *InventoryService) followTasks(devices []*models.Device, faults []*models.Fault, now time.Time) {
+	s.tasksMu.Lock()
+	tracked := make([]*models.Task, 0, len(s.queuedTasks))
+	for _, task := range s.queuedTasks {
+		tracked = append(tracked, task)
+	}
+	s.tasksMu.Unlock()
+
+	if len(tracked) == 0 {
+		return
+	}
+
+	pending, err := s.genieService.GetTasks("")
+	if err != nil {
+		logger.GenieACSLog.Warnf("Inventory sync failed to fetch tasks: %v", err)
+		return
+	}
+	open := make(map[string]bool, len(pending))
+	for _, task := range pending {
+		open[task.ID] = true
+	}
+
+	// GenieACS records the faults of a task on the channel task_<id>
+	taskFaults := make(map[string]*models.Fault)
+	for _, fault := range faults {
+		if taskID, ok := strings.CutPrefix(fault.Channel, "task_"); ok {
+			taskFaults[taskID] = fault
+		}
+	}
+
+	lastInform := make(map[string]time.Time, len(devices))
+	for _, device := range devices {
+		lastInform[device.ID] = device.LastInform
+	}
+
+	for _, queued := range tracked {
+		task := *queued
+		informed, known := lastInform[task.DeviceID]
+
+		switch {
+		case taskFaults[task.ID] != nil:
+			task.Status = models.TaskStatusFailed
+			task.Fault = taskFaults[task.ID]
+		case !known:
+			task.Status = models.TaskStatusFailed
+		case open[task.ID]:
+			continue
+		case informed.After(task.QueuedAt):
+			task.Status = models.TaskStatusCompleted
+		default:
+			task.Status = models.TaskStatusFailed
+		}
+
+		s.tasksMu.Lock()
+		delete(s.queuedTasks, task.ID)
+		s.tasksMu.Unlock()
+
+		task.CompletedAt = &now
+		eventType := appContext.EventTaskCompleted
+		if task.Status == models.TaskStatusFailed {
+			eventType = appContext.EventTaskFailed
+			logger.GenieACSLog.Warnf("Queued task %s (%s) failed on device %s", task.ID, task.Name, task.DeviceID)
+		} else {
+			logger.GenieACSLog.Infof("Queued task %s (%s) completed on device %s", task.ID, task.Name, task.DeviceID)
+		}
+
+		s.appContext.PublishEvent(appContext.Event{
+			Type:     eventType,
+			DeviceID: task.DeviceID,
+			Task:     &task,
+		})
+	}
+}
+
+// checkHeartbeats raises a fault for devices that missed too many informs
+// and clears it once they inform again
+func (s *InventoryService) checkHeartbeats(devices []*models.Device, now time.Time) {
+	for _, device := range devices {
+		faultID, raised := s.offlineFaults[device.ID]
+
+		switch {
+		case device.Status.ConnectionStatus == models.ConnectionStatusOffline && !raised:
+			if device.LastInform.IsZero() {
+				// Never informed, nothing to miss
+				continue
+			}
+			if s.missedInOutage(device, now) {
+				continue
+			}
+			s.raiseOffline(device, now)
+		case device.Status.ConnectionStatus != models.ConnectionStatusOffline && raised:
+			delete(s.offlineFaults, device.ID)
+			s.clearFault(faultID, models.FaultChannelHeartbeat, "Device informed again")
+			logger.FaultLog.Infof("Device %s is back online, cleared fault %s", device.ID, faultID)
+		}
+	}
+}
+
+// missedInOutage checks if a device only missed its informs while GenieACS
+// was unreachable, when CPEs may not have been able to inform either. Informs
+// are counted from the end of the outage for devices that last informed
+// before it.
+func (s *InventoryService) missedInOutage(device *models.Device, now time.Time) bool {
+	if s.recoveredAt.IsZero() || !device.LastInform.Before(s.recoveredAt) {
+		return false
+	}
+	status := device.Status
+	updateHeartbeat(s.config.Heartbeat, &status, s.recoveredAt, now)
+	return status.ConnectionStatus != models.ConnectionStatusOffline
+}
+
+// raiseOffline records an offline fault for a device
+func (s *InventoryService) raiseOffline(device *models.Device, now time.Time) {
+	interval := s.config.Heartbeat.DefaultInterval
+	if device.Status.InformInterval > 0 {
+		interval = time.Duration(device.Status.InformInterval) * time.Second
+	}
+
+	fault := &models.Fault{
+		ID:           models.FaultChannelHeartbeat + "-" + uuid.New().String(),
+		DeviceID:     device.ID,
+		DeviceSerial: device.DeviceID.SerialNumber,
+		DeviceModel:  device.DeviceID.ModelName,
+		Channel:      models.FaultChannelHeartbeat,
+		Code:         models.FaultCodeDeviceOffline,
+		Message:      "Device offline",
+		Detail: fmt.Sprintf("Missed %d informs (interval %s), last inform %s",
+			device.Status.MissedInforms, interval, device.LastInform.Format(time.RFC3339)),
+		Severity:  s.config.Heartbeat.FaultSeverity,
+		Timestamp: now,
+		Status:    models.FaultStatusActive,
+	}
+
+	s.offlineFaults[device.ID] = fault.ID
+	s.appContext.AddGatewayFault(fault)
+
+	logger.FaultLog.Warnf("Device %s missed %d informs, raised offline fault %s",
+		device.ID, device.Status.MissedInforms, fault.ID)
+}
+
+// evaluateRules runs the alarm rules against every device and applies the
+// resulting raise and clear transitions. Alarms of devices no longer in the
+// inventory or no longer matched by their rule are cleared.
+func (s *InventoryService) evaluateRules(devices []*models.Device, now time.Time) {
+	alarmRules := s.appContext.GetAlarmRules()
+
+	for _, transition := range s.engine.Prune(alarmRules, devices) {
+		s.clearAlarm(transition)
+	}
+
+	if len(alarmRules) == 0 {
+		return
+	}
+
+	for _, device := range devices {
+		transitions, errs := s.engine.Evaluate(alarmRules, device, now)
+		for _, err := range errs {
+			logger.FaultLog.Warnf("Alarm rule evaluation failed: %v", err)
+		}
+
+		for _, transition := range transitions {
+			if transition.Raised {
+				s.raiseAlarm(transition, device, now)
+			} else {
+				s.clearAlarm(transition)
+			}
+		}
+	}
+}
+
+// raiseAlarm records a fault for a rule that became active
+func (s *InventoryService) raiseAlarm(transition rules.Transition, device *models.Device, now time.Time) {
+	rule := transition.Rule
+
+	s.appContext.AddGatewayFault(&models.Fault{
+		ID:           transition.FaultID,
+		DeviceID:     device.ID,
+		DeviceSerial: device.DeviceID.SerialNumber,
+		DeviceModel:  device.DeviceID.ModelName,
+		Channel:      models.FaultChannelGatewayRule,
+		Code:         rule.Code,
+		Message:      rule.Name,
+		Detail:       transition.Detail,
+		Severity:     rule.Severity,
+		Timestamp:    now,
+		Status:       models.FaultStatusActive,
+		Tags:         []string{appContext.AlarmRuleTag(rule.ID)},
+	})
+
+	logger.FaultLog.Warnf("Alarm rule %s raised %s fault %s on device %s",
+		rule.Name, rule.Severity, transition.FaultID, device.ID)
+}
+
+// clearAlarm resolves the fault of a rule that is no longer active
+func (s *InventoryService) clearAlarm(transition rules.Transition) {
+	if s.clearFault(transition.FaultID, models.FaultChannelGatewayRule, transition.Detail) {
+		logger.FaultLog.Infof("Alarm rule %s cleared fault %s on device %s",
+			transition.Rule.Name, transition.FaultID, transition.DeviceID)
+	}
+}
+
+// clearFault auto-resolves a fault raised by the gateway. Faults already
+// resolved by an operator are left untouched.
+func (s *InventoryService) clearFault(faultID, clearedBy, detail string) bool {
+	fault, exists := s.appContext.GetFault(faultID)
+	if !exists || fault.Status == models.FaultStatusResolved {
+		return false
+	}
+
+	if err := s.appContext.ResolveFault(faultID, clearedBy, models.ResolutionAutoCleared, detail); err != nil {
+		logger.FaultLog.Errorf("Failed to clear fault %s: %v", faultID, err)
+		return false
+	}
+	return true
+}
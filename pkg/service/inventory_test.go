@@ -0,0 +1,226 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// fakeNBI serves the GenieACS device, fault and task API. Every created
+// task stays queued until the test removes it.
+type fakeNBI struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	devices []map[string]interface{}
+	tasks   []map[string]interface{}
+	// down fails device and fault requests
+	down bool
+}
+
+func newFakeNBI(t *testing.T) *fakeNBI {
+	t.Helper()
+	nbi := &fakeNBI{}
+	nbi.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nbi.mu.Lock()
+		defer nbi.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/tasks"):
+			var task map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			deviceID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/devices/"), "/tasks")
+			task["_id"] = task["name"].(string) + "-" + deviceID
+			task["device"] = deviceID
+			task["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+			nbi.tasks = append(nbi.tasks, task)
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(task)
+		case r.Method == http.MethodGet && r.URL.Path == "/tasks":
+			json.NewEncoder(w).Encode(nbi.tasks)
+		case nbi.down:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		case r.Method == http.MethodGet && r.URL.Path == "/devices":
+			json.NewEncoder(w).Encode(nbi.devices)
+		case r.Method == http.MethodGet && r.URL.Path == "/faults":
+			w.Write([]byte("[]"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(nbi.server.Close)
+	return nbi
+}
+
+// remove drops a task as GenieACS does once the device ran it
+func (nbi *fakeNBI) remove(taskID string) {
+	nbi.mu.Lock()
+	defer nbi.mu.Unlock()
+	for i, task := range nbi.tasks {
+		if task["_id"] == taskID {
+			nbi.tasks = append(nbi.tasks[:i], nbi.tasks[i+1:]...)
+			return
+		}
+	}
+}
+
+// setDevice stores a device that last informed at the given time
+func (nbi *fakeNBI) setDevice(deviceID string, lastInform time.Time) {
+	nbi.mu.Lock()
+	defer nbi.mu.Unlock()
+	nbi.devices = []map[string]interface{}{{
+		"_id":         deviceID,
+		"_deviceId":   map[string]interface{}{"_SerialNumber": deviceID},
+		"_lastInform": lastInform.UTC().Format(time.RFC3339),
+	}}
+}
+
+func (nbi *fakeNBI) setDown(down bool) {
+	nbi.mu.Lock()
+	defer nbi.mu.Unlock()
+	nbi.down = down
+}
+
+func TestFollowQueuedTasks(t *testing.T) {
+	nbi := newFakeNBI(t)
+	appCtx := appContext.NewContext()
+	s := NewInventoryService(&config.GenieACS{NBIURL: nbi.server.URL, Timeout: 5 * time.Second}, appCtx)
+
+	sub, err := appCtx.SubscribeEvents(appContext.EventFilter{Types: []string{"task.*"}}, 16, 0)
+	if err != nil {
+		t.Fatalf("SubscribeEvents: %v", err)
+	}
+	defer sub.Close()
+
+	// Four offline devices get a reboot queued
+	queuedAt := time.Now()
+	for _, deviceID := range []string{"cpe-ran", "cpe-faulted", "cpe-offline", "cpe-deleted"} {
+		if err := s.genieService.CreateTask(deviceID, map[string]interface{}{"name": "reboot"}); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+		event := <-sub.C
+		if event.Type != appContext.EventTaskQueued {
+			t.Fatalf("task on %s announced as %s, want queued", deviceID, event.Type)
+		}
+		s.trackTask(&event)
+	}
+
+	// cpe-ran informed and ran its task, cpe-faulted informed and its task
+	// faulted, cpe-offline is still silent, the task of cpe-deleted was
+	// removed without an inform
+	nbi.remove("reboot-cpe-ran")
+	nbi.remove("reboot-cpe-deleted")
+	informed := queuedAt.Add(time.Second)
+	devices := []*models.Device{
+		{ID: "cpe-ran", LastInform: informed},
+		{ID: "cpe-faulted", LastInform: informed},
+		{ID: "cpe-offline", LastInform: queuedAt.Add(-time.Hour)},
+		{ID: "cpe-deleted", LastInform: queuedAt.Add(-time.Hour)},
+	}
+	faults := []*models.Fault{
+		{ID: "cpe-faulted:task_reboot-cpe-faulted", DeviceID: "cpe-faulted", Channel: "task_reboot-cpe-faulted", Code: "cwmp.9002"},
+	}
+
+	s.followTasks(devices, faults, informed)
+
+	expected := map[string]string{
+		"reboot-cpe-ran":     appContext.EventTaskCompleted,
+		"reboot-cpe-faulted": appContext.EventTaskFailed,
+		"reboot-cpe-deleted": appContext.EventTaskFailed,
+	}
+	for range expected {
+		event := <-sub.C
+		if want := expected[event.Task.ID]; event.Type != want {
+			t.Errorf("task %s settled as %s, want %s", event.Task.ID, event.Type, want)
+		}
+		if event.Task.ID == "reboot-cpe-faulted" && (event.Task.Fault == nil || event.Task.Fault.Code != "cwmp.9002") {
+			t.Errorf("failed task carries fault %+v, want the task fault", event.Task.Fault)
+		}
+		if event.Task.CompletedAt == nil || event.Task.Name != "reboot" {
+			t.Errorf("settled task %+v lacks its name or completion time", event.Task)
+		}
+	}
+
+	// Only the task of the silent device is still followed
+	s.followTasks(devices, faults, informed)
+	select {
+	case event := <-sub.C:
+		t.Errorf("unexpected %s event for task %s", event.Type, event.Task.ID)
+	default:
+	}
+	if len(s.queuedTasks) != 1 || s.queuedTasks["reboot-cpe-offline"] == nil {
+		t.Errorf("following %d tasks, want the task of cpe-offline", len(s.queuedTasks))
+	}
+
+	// It completes once the device informs and GenieACS dropped it
+	nbi.remove("reboot-cpe-offline")
+	devices[2].LastInform = informed
+	s.followTasks(devices, nil, informed)
+	if event := <-sub.C; event.Type != appContext.EventTaskCompleted || event.Task.ID != "reboot-cpe-offline" {
+		t.Errorf("got %s for task %s, want the task of cpe-offline completed", event.Type, event.Task.ID)
+	}
+}
+
+func TestSyncPausedWhileGenieACSUnreachable(t *testing.T) {
+	nbi := newFakeNBI(t)
+	appCtx := appContext.NewContext()
+	heartbeat := &config.Heartbeat{DefaultInterval: 5 * time.Minute, Tolerance: 1.5, MissedInforms: 3, FaultSeverity: models.SeverityMajor}
+	s := NewInventoryService(&config.GenieACS{NBIURL: nbi.server.URL, Timeout: 5 * time.Second, Heartbeat: heartbeat}, appCtx)
+
+	offlineFaults := func() int {
+		count := 0
+		for _, fault := range appCtx.GetActiveFaults() {
+			if fault.Channel == models.FaultChannelHeartbeat {
+				count++
+			}
+		}
+		return count
+	}
+
+	nbi.setDevice("cpe-1", time.Now().Add(-time.Minute))
+	s.sync()
+	if device, _ := appCtx.GetDevice("cpe-1"); device == nil || device.Status.ConnectionStatus != models.ConnectionStatusOnline {
+		t.Fatalf("device not synced online: %+v", device)
+	}
+
+	// The stored inventory is neither aged nor judged during the outage
+	nbi.setDown(true)
+	s.sync()
+	s.sync()
+	if device, _ := appCtx.GetDevice("cpe-1"); device.Status.ConnectionStatus != models.ConnectionStatusOnline {
+		t.Errorf("device went %s while GenieACS was unreachable", device.Status.ConnectionStatus)
+	}
+	if s.unreachableSince.IsZero() {
+		t.Error("outage not recorded")
+	}
+
+	// The CPE could not inform during the outage either, it is not offline
+	// right after GenieACS answers again
+	nbi.setDevice("cpe-1", time.Now().Add(-time.Hour))
+	nbi.setDown(false)
+	s.sync()
+	if !s.unreachableSince.IsZero() || s.recoveredAt.IsZero() {
+		t.Fatal("recovery not recorded")
+	}
+	if count := offlineFaults(); count != 0 {
+		t.Errorf("%d offline faults raised after the outage, want none", count)
+	}
+
+	// It is once it missed its informs after the outage as well
+	device, _ := appCtx.GetDevice("cpe-1")
+	s.checkHeartbeats([]*models.Device{device}, s.recoveredAt.Add(time.Hour))
+	if count := offlineFaults(); count != 1 {
+		t.Errorf("%d offline faults raised once the device stayed silent, want 1", count)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Parameter paths holding the device addresses in the GenieACS document
+const (
+	lanIPPath      = "InternetGatewayDevice.LANDevice.1.LANHostConfigManagement.IPInterface.1.IPInterfaceIPAddress"
+	externalIPPath = "InternetGatewayDevice.WANDevice.1.WANConnectionDevice.1.WANIPConnection.1.ExternalIPAddress"
+)
+
+// ipRangeQuery translates an IP filter into a GenieACS query clause.
+//
+// GenieACS stores addresses as strings, so only single IPv4 addresses and
+// octet aligned IPv4 prefixes can be matched with a regular expression. ok
+// is false for anything else and the caller filters after fetching.
+func ipRangeQuery(r *models.IPRange) (map[string]interface{}, bool) {
+	patterns := make([]string, 0, len(r.CIDRs)+1)
+
+	if r.StartIP != "" || r.EndIP != "" {
+		start, end := strings.TrimSpace(r.StartIP), strings.TrimSpace(r.EndIP)
+		if start == "" {
+			start = end
+		}
+		if end != "" && end != start {
+			return nil, false
+		}
+		addr, err := netip.ParseAddr(start)
+		if err != nil || !addr.Is4() {
+			return nil, false
+		}
+		patterns = append(patterns, "^"+regexp.QuoteMeta(addr.String())+"$")
+	}
+
+	for _, cidr := range r.CIDRs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil || !prefix.Addr().Is4() || prefix.Bits()%8 != 0 {
+			return nil, false
+		}
+		prefix = prefix.Masked()
+
+		octets := strings.Split(prefix.Addr().String(), ".")[:prefix.Bits()/8]
+		switch len(octets) {
+		case 0:
+			patterns = append(patterns, `^\d+\.\d+\.\d+\.\d+$`)
+		case 4:
+			patterns = append(patterns, "^"+strings.Join(octets, `\.`)+"$")
+		default:
+			patterns = append(patterns, "^"+strings.Join(octets, `\.`)+`\.`)
+		}
+	}
+
+	paths := []string{lanIPPath, externalIPPath}
+	switch r.Field {
+	case models.IPFieldLAN:
+		paths = []string{lanIPPath}
+	case models.IPFieldExternal:
+		paths = []string{externalIPPath}
+	}
+
+	clauses := make([]interface{}, 0, len(patterns)*len(paths))
+	for _, pattern := range patterns {
+		for _, path := range paths {
+			clauses = append(clauses, map[string]interface{}{
+				path + "._value": map[string]interface{}{"$regex": pattern},
+			})
+		}
+	}
+
+	operator := "$or"
+	if r.Negate {
+		operator = "$nor"
+	}
+	return map[string]interface{}{operator: clauses}, true
+}
+
+// filterByIPRange keeps the devices matching an IP filter
+func filterByIPRange(devices []*models.Device, r *models.IPRange) []*models.Device {
+	filtered := make([]*models.Device, 0, len(devices))
+	for _, device := range devices {
+		if r.MatchesDevice(device) {
+			filtered = append(filtered, device)
+		}
+	}
+	return filtered
+}
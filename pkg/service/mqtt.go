@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+)
+
+// Availability of the gateway, retained on <prefix>/gateway/status. The
+// broker publishes the offline state itself when the gateway disappears.
+const (
+	mqttGatewayOnline  = "online"
+	mqttGatewayOffline = "offline"
+)
+
+// mqttConnectRetryInterval paces attempts until the first connection, later
+// reconnects back off up to the configured maximum
+const mqttConnectRetryInterval = 5 * time.Second
+
+// MQTTService mirrors gateway events to an MQTT broker. Device status is
+// retained so late subscribers get the last state of every device.
+type MQTTService struct {
+	config     *config.MQTT
+	appContext *appContext.Context
+	client     mqtt.Client
+	filter     appContext.EventFilter
+
+	// queue holds messages until the broker acknowledges them, the oldest
+	// are dropped when it stays unreachable
+	queue     chan *mqttMessage
+	connected chan struct{}
+	dropped   int
+}
+
+// mqttMessage is a message waiting to be published
+type mqttMessage struct {
+	topic    string
+	retained bool
+	payload  []byte
+}
+
+// mqttDeviceStatus is the retained payload of a device status topic
+type mqttDeviceStatus struct {
+	DeviceID         string    `json:"deviceId"`
+	Serial           string    `json:"serial,omitempty"`
+	Model            string    `json:"model,omitempty"`
+	OUI              string    `json:"oui,omitempty"`
+	Online           bool      `json:"online"`
+	ConnectionStatus string    `json:"connectionStatus,omitempty"`
+	LastInform       time.Time `json:"lastInform"`
+	Sequence         uint64    `json:"sequence"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// NewMQTTService creates a new MQTT publisher. It fails on unknown event
+// types and TLS material that cannot be loaded.
+func NewMQTTService(cfg *config.MQTT, ctx *appContext.Context) (*MQTTService, error) {
+	for _, pattern := range cfg.Events {
+		if !appContext.IsValidEventPattern(pattern) {
+			return nil, fmt.Errorf("invalid MQTT event type: %s", pattern)
+		}
+	}
+
+	s := &MQTTService{
+		config:     cfg,
+		appContext: ctx,
+		filter:     appContext.EventFilter{Types: cfg.Events},
+		queue:      make(chan *mqttMessage, cfg.QueueSize),
+		connected:  make(chan struct{}, 1),
+	}
+
+	clientID := cfg.ClientID
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = "nextranet-gateway-" + hostname
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(clientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetKeepAlive(cfg.KeepAlive).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(min(mqttConnectRetryInterval, cfg.MaxReconnectInterval)).
+		SetMaxReconnectInterval(cfg.MaxReconnectInterval).
+		SetWill(s.topic("gateway", "status"), mqttGatewayOffline, 1, true).
+		SetOnConnectHandler(s.onConnect).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.NotifyLog.Warnf("Lost connection to MQTT broker %s, reconnecting: %v", cfg.Broker, err)
+		})
+
+	if strings.HasPrefix(cfg.Broker, "ssl://") || strings.HasPrefix(cfg.Broker, "tls://") ||
+		strings.HasPrefix(cfg.Broker, "mqtts://") || strings.HasPrefix(cfg.Broker, "wss://") {
+		tlsConfig, err := clientTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("MQTT TLS: %w", err)
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	s.client = mqtt.NewClient(opts)
+	return s, nil
+}
+
+// Start publishes events until the context is cancelled. The client keeps
+// reconnecting in the background, messages are queued meanwhile.
+func (s *MQTTService) Start(ctx context.Context) {
+	logger.NotifyLog.Infof("Starting MQTT publishing to %s...", s.config.Broker)
+
+	// With connect retry the token only completes once connected
+	s.client.Connect()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeEvents(ctx, s.appContext, "MQTT publishing", s.filter, func(event *appContext.Event) {
+			if !isNotifiable(event) {
+				return
+			}
+			if message := s.message(event); message != nil {
+				s.enqueue(message)
+			}
+		})
+	}()
+
+	s.publish(ctx)
+	wg.Wait()
+
+	// Leave cleanly, the will is only published on an unexpected disconnect
+	if s.client.IsConnectionOpen() {
+		s.client.Publish(s.topic("gateway", "status"), 1, true, mqttGatewayOffline).WaitTimeout(s.config.PublishTimeout)
+	}
+	s.client.Disconnect(250)
+	logger.NotifyLog.Info("Stopped MQTT publishing")
+}
+
+// onConnect announces the gateway and wakes the publisher, also after every
+// reconnect
+func (s *MQTTService) onConnect(client mqtt.Client) {
+	logger.NotifyLog.Infof("Connected to MQTT broker %s", s.config.Broker)
+	client.Publish(s.topic("gateway", "status"), 1, true, mqttGatewayOnline)
+
+	select {
+	case s.connected <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue queues a message, dropping the oldest one when the queue is full.
+// Only the event consumer enqueues, so the retry cannot race another sender.
+func (s *MQTTService) enqueue(message *mqttMessage) {
+	select {
+	case s.queue <- message:
+		return
+	default:
+	}
+
+	select {
+	case <-s.queue:
+		s.dropped++
+		if s.dropped == 1 || s.dropped%1000 == 0 {
+			logger.NotifyLog.Warnf("MQTT queue full, dropped %d messages", s.dropped)
+		}
+	default:
+	}
+
+	select {
+	case s.queue <- message:
+	default:
+	}
+}
+
+// publish sends queued messages in order while connected. A message the
+// broker did not acknowledge is published again, QoS 1 and 2 subscribers
+// may see it twice.
+func (s *MQTTService) publish(ctx context.Context) {
+	var pending *mqttMessage
+	for {
+		if pending == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case pending = <-s.queue:
+			}
+		}
+
+		if !s.client.IsConnectionOpen() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.connected:
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		token := s.client.Publish(pending.topic, s.config.QoS, pending.retained, pending.payload)
+		if !token.WaitTimeout(s.config.PublishTimeout) {
+			logger.NotifyLog.Warnf("Publishing to MQTT topic %s timed out", pending.topic)
+			continue
+		}
+		if err := token.Error(); err != nil {
+			logger.NotifyLog.Warnf("Failed to publish to MQTT topic %s: %v", pending.topic, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		pending = nil
+	}
+}
+
+// message maps an event to its topic:
+//
+//	<prefix>/devices/{id}/status  retained last state, cleared on removal
+//	<prefix>/faults/{severity}    fault events
+//	<prefix>/tasks/{id}           task events
+//	<prefix>/system/acs           retained ACS connectivity
+func (s *MQTTService) message(event *appContext.Event) *mqttMessage {
+	switch {
+	case event.Device != nil:
+		topic := s.topic("devices", event.DeviceID, "status")
+		if event.Type == appContext.EventDeviceRemoved {
+			// An empty retained message removes the retained status
+			return &mqttMessage{topic: topic, retained: true, payload: []byte{}}
+		}
+		device := event.Device
+		return s.jsonMessage(topic, true, &mqttDeviceStatus{
+			DeviceID:         device.ID,
+			Serial:           device.DeviceID.SerialNumber,
+			Model:            device.DeviceID.ModelName,
+			OUI:              device.DeviceID.OUI,
+			Online:           device.Status.Online,
+			ConnectionStatus: device.Status.ConnectionStatus,
+			LastInform:       device.LastInform,
+			Sequence:         event.Sequence,
+			Timestamp:        event.Timestamp,
+		})
+	case event.Fault != nil || event.Escalation != nil:
+		severity := event.Severity
+		if severity == "" {
+			severity = "indeterminate"
+		}
+		return s.jsonMessage(s.topic("faults", severity), false, event)
+	case event.Task != nil:
+		taskID := event.Task.ID
+		if taskID == "" {
+			// Tasks GenieACS rejected never got an ID
+			taskID = "unknown"
+		}
+		return s.jsonMessage(s.topic("tasks", taskID), false, event)
+	case event.ACSStatus != nil:
+		return s.jsonMessage(s.topic("system", "acs"), true, event)
+	}
+	return nil
+}
+
+// jsonMessage encodes the payload of a message
+func (s *MQTTService) jsonMessage(topic string, retained bool, payload interface{}) *mqttMessage {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logger.NotifyLog.Errorf("Failed to encode MQTT message for %s: %v", topic, err)
+		return nil
+	}
+	return &mqttMessage{topic: topic, retained: retained, payload: data}
+}
+
+// topicLevelEscaper keeps identifiers within a single topic level
+var topicLevelEscaper = strings.NewReplacer("/", "_", "+", "_", "#", "_")
+
+// topic joins the prefix and levels into a topic name
+func (s *MQTTService) topic(levels ...string) string {
+	for i, level := range levels {
+		levels[i] = topicLevelEscaper.Replace(level)
+	}
+	return s.config.TopicPrefix + "/" + strings.Join(levels, "/")
+}
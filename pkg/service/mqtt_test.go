@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// fakeBroker is an MQTT 3.1.1 broker that records what clients publish.
+// It acknowledges QoS 1 and 2 messages and keeps no subscriptions.
+type fakeBroker struct {
+	listener  net.Listener
+	connects  chan *packets.ConnectPacket
+	published chan *packets.PublishPacket
+
+	mu sync.Mutex
+	// dropTopic makes the broker drop the connection instead of
+	// acknowledging the next message to the topic
+	dropTopic string
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	b := &fakeBroker{
+		listener:  listener,
+		connects:  make(chan *packets.ConnectPacket, 16),
+		published: make(chan *packets.PublishPacket, 256),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+// url returns the broker URL for the MQTT configuration
+func (b *fakeBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// serve runs the session of one client connection
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	packet, err := packets.ReadPacket(conn)
+	if err != nil {
+		return
+	}
+	connect, ok := packet.(*packets.ConnectPacket)
+	if !ok {
+		return
+	}
+	b.connects <- connect
+	if err := packets.NewControlPacket(packets.Connack).Write(conn); err != nil {
+		return
+	}
+
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := packet.(type) {
+		case *packets.PublishPacket:
+			b.mu.Lock()
+			drop := b.dropTopic != "" && b.dropTopic == p.TopicName
+			if drop {
+				b.dropTopic = ""
+			}
+			b.mu.Unlock()
+			if drop {
+				return
+			}
+
+			b.published <- p
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				ack.Write(conn)
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				rec.Write(conn)
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			comp.Write(conn)
+		case *packets.PingreqPacket:
+			packets.NewControlPacket(packets.Pingresp).Write(conn)
+		case *packets.DisconnectPacket:
+			return
+		}
+	}
+}
+
+// expectConnect waits for a client to connect
+func (b *fakeBroker) expectConnect(t *testing.T) *packets.ConnectPacket {
+	t.Helper()
+	select {
+	case connect := <-b.connects:
+		return connect
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not connect")
+		return nil
+	}
+}
+
+// expectPublish waits for a message to a topic, skipping other topics
+func (b *fakeBroker) expectPublish(t *testing.T, topic string) *packets.PublishPacket {
+	t.Helper()
+	return b.expectPublishes(t, topic)[topic]
+}
+
+// expectPublishes waits for a message to each of the topics in any order,
+// skipping other topics
+func (b *fakeBroker) expectPublishes(t *testing.T, topics ...string) map[string]*packets.PublishPacket {
+	t.Helper()
+	received := make(map[string]*packets.PublishPacket, len(topics))
+	timeout := time.After(5 * time.Second)
+	for len(received) < len(topics) {
+		select {
+		case p := <-b.published:
+			for _, topic := range topics {
+				if p.TopicName == topic && received[topic] == nil {
+					received[topic] = p
+				}
+			}
+		case <-timeout:
+			for _, topic := range topics {
+				if received[topic] == nil {
+					t.Fatalf("nothing published to %s", topic)
+				}
+			}
+		}
+	}
+	return received
+}
+
+// startMQTT runs an MQTT service against the broker until the test ends,
+// once it is connected and receives events
+func startMQTT(t *testing.T, broker *fakeBroker, qos byte) (*appContext.Context, *MQTTService) {
+	t.Helper()
+	appCtx := appContext.NewContext()
+	s, err := NewMQTTService(&config.MQTT{
+		Enabled:              true,
+		Broker:               broker.url(),
+		ClientID:             "gateway-test",
+		TopicPrefix:          "ntg",
+		QoS:                  qos,
+		QueueSize:            100,
+		KeepAlive:            30 * time.Second,
+		ConnectTimeout:       2 * time.Second,
+		PublishTimeout:       time.Second,
+		MaxReconnectInterval: 100 * time.Millisecond,
+	}, appCtx)
+	if err != nil {
+		t.Fatalf("NewMQTTService: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	broker.expectConnect(t)
+	broker.expectPublish(t, "ntg/gateway/status")
+
+	// The service subscribes to events after connecting, publish the ACS
+	// status until it arrives
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		appCtx.PublishEvent(appContext.Event{
+			Type:      appContext.EventACSConnectivityChanged,
+			ACSStatus: &appContext.GenieACSStatus{NBIConnected: true},
+		})
+		select {
+		case p := <-broker.published:
+			if p.TopicName == "ntg/system/acs" {
+				if !p.Retain {
+					t.Error("ACS status is not retained")
+				}
+				return appCtx, s
+			}
+		case <-time.After(100 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("events are not published")
+		}
+	}
+}
+
+func TestMQTTPublish(t *testing.T) {
+	broker := newFakeBroker(t)
+	appCtx := appContext.NewContext()
+	s, err := NewMQTTService(&config.MQTT{
+		Broker:               broker.url(),
+		TopicPrefix:          "ntg",
+		QueueSize:            1,
+		KeepAlive:            time.Second,
+		ConnectTimeout:       time.Second,
+		MaxReconnectInterval: time.Second,
+	}, appCtx)
+	if err != nil {
+		t.Fatalf("NewMQTTService: %v", err)
+	}
+	if topic := s.topic("devices", "A1B2C3/CPE+1#", "status"); topic != "ntg/devices/A1B2C3_CPE_1_/status" {
+		t.Errorf("device topic %s escapes no topic levels", topic)
+	}
+
+	appCtx, _ = startMQTT(t, broker, 1)
+
+	device := &models.Device{
+		ID:       "A1B2C3-CPE-0001",
+		DeviceID: models.DeviceID{SerialNumber: "0001", ModelName: "G-240W", OUI: "A1B2C3"},
+		Status:   models.DeviceStatus{Online: true},
+		Tags:     map[string]bool{},
+	}
+	appCtx.AddDevice(device)
+
+	status := broker.expectPublish(t, "ntg/devices/A1B2C3-CPE-0001/status")
+	if !status.Retain || status.Qos != 1 {
+		t.Errorf("device status published with retain %t qos %d, want retained qos 1", status.Retain, status.Qos)
+	}
+	var payload mqttDeviceStatus
+	if err := json.Unmarshal(status.Payload, &payload); err != nil {
+		t.Fatalf("device status payload: %v", err)
+	}
+	if payload.DeviceID != device.ID || payload.Serial != "0001" || payload.Model != "G-240W" || !payload.Online || payload.Sequence == 0 {
+		t.Errorf("unexpected device status %+v", payload)
+	}
+
+	appCtx.AddFault(&models.Fault{
+		ID:        "fault-1",
+		DeviceID:  device.ID,
+		Code:      "cwmp.9002",
+		Severity:  models.SeverityCritical,
+		Status:    models.FaultStatusActive,
+		Timestamp: time.Now(),
+	})
+	fault := broker.expectPublish(t, "ntg/faults/critical")
+	if fault.Retain {
+		t.Error("fault event is retained")
+	}
+	var event appContext.Event
+	if err := json.Unmarshal(fault.Payload, &event); err != nil {
+		t.Fatalf("fault payload: %v", err)
+	}
+	if event.Type != appContext.EventFaultRaised || event.Fault == nil || event.Fault.ID != "fault-1" {
+		t.Errorf("unexpected fault event %+v", event)
+	}
+
+	// Removing the device clears its retained status
+	appCtx.RemoveDevice(device.ID)
+	removed := broker.expectPublish(t, "ntg/devices/A1B2C3-CPE-0001/status")
+	if !removed.Retain || len(removed.Payload) != 0 {
+		t.Errorf("removal published %q retained %t, want an empty retained message", removed.Payload, removed.Retain)
+	}
+}
+
+func TestMQTTWill(t *testing.T) {
+	broker := newFakeBroker(t)
+	appCtx := appContext.NewContext()
+	s, err := NewMQTTService(&config.MQTT{
+		Broker:               broker.url(),
+		ClientID:             "gateway-test",
+		TopicPrefix:          "ntg",
+		QueueSize:            1,
+		KeepAlive:            30 * time.Second,
+		ConnectTimeout:       time.Second,
+		PublishTimeout:       time.Second,
+		MaxReconnectInterval: time.Second,
+	}, appCtx)
+	if err != nil {
+		t.Fatalf("NewMQTTService: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+
+	connect := broker.expectConnect(t)
+	if !connect.WillFlag || connect.WillTopic != "ntg/gateway/status" || string(connect.WillMessage) != mqttGatewayOffline || !connect.WillRetain {
+		t.Errorf("connect has will %t %s %q retained %t, want a retained offline status",
+			connect.WillFlag, connect.WillTopic, connect.WillMessage, connect.WillRetain)
+	}
+	if connect.ClientIdentifier != "gateway-test" {
+		t.Errorf("client ID %s, want gateway-test", connect.ClientIdentifier)
+	}
+	if online := broker.expectPublish(t, "ntg/gateway/status"); string(online.Payload) != mqttGatewayOnline || !online.Retain {
+		t.Errorf("gateway status %q retained %t, want retained online", online.Payload, online.Retain)
+	}
+
+	// A clean stop publishes the offline status itself
+	cancel()
+	<-done
+	if offline := broker.expectPublish(t, "ntg/gateway/status"); string(offline.Payload) != mqttGatewayOffline {
+		t.Errorf("gateway status on stop %q, want offline", offline.Payload)
+	}
+}
+
+func TestMQTTQoS2(t *testing.T) {
+	broker := newFakeBroker(t)
+	appCtx, _ := startMQTT(t, broker, 2)
+
+	// Each message completes the QoS 2 handshake before the next is sent
+	for i, severity := range []string{models.SeverityMajor, models.SeverityMinor} {
+		appCtx.AddFault(&models.Fault{
+			ID:        "fault-" + severity,
+			DeviceID:  "A1B2C3-CPE-0001",
+			Severity:  severity,
+			Status:    models.FaultStatusActive,
+			Timestamp: time.Now(),
+		})
+		p := broker.expectPublish(t, "ntg/faults/"+severity)
+		if p.Qos != 2 {
+			t.Errorf("message %d published with qos %d, want 2", i, p.Qos)
+		}
+	}
+}
+
+func TestMQTTReconnect(t *testing.T) {
+	broker := newFakeBroker(t)
+	appCtx, _ := startMQTT(t, broker, 1)
+
+	// The broker goes away before acknowledging the fault
+	broker.mu.Lock()
+	broker.dropTopic = "ntg/faults/critical"
+	broker.mu.Unlock()
+	appCtx.AddFault(&models.Fault{
+		ID:        "fault-1",
+		DeviceID:  "A1B2C3-CPE-0001",
+		Severity:  models.SeverityCritical,
+		Status:    models.FaultStatusActive,
+		Timestamp: time.Now(),
+	})
+
+	// The client reconnects, announces itself again and publishes the
+	// message the broker did not acknowledge
+	broker.expectConnect(t)
+	received := broker.expectPublishes(t, "ntg/gateway/status", "ntg/faults/critical")
+	if online := received["ntg/gateway/status"]; string(online.Payload) != mqttGatewayOnline {
+		t.Errorf("gateway status after reconnect %q, want online", online.Payload)
+	}
+	fault := received["ntg/faults/critical"]
+	var event appContext.Event
+	if err := json.Unmarshal(fault.Payload, &event); err != nil || event.Fault == nil || event.Fault.ID != "fault-1" {
+		t.Errorf("unexpected fault event after reconnect %s", fault.Payload)
+	}
+
+	// Events raised after the reconnect follow
+	appCtx.AddFault(&models.Fault{
+		ID:        "fault-2",
+		DeviceID:  "A1B2C3-CPE-0001",
+		Severity:  models.SeverityMajor,
+		Status:    models.FaultStatusActive,
+		Timestamp: time.Now(),
+	})
+	broker.expectPublish(t, "ntg/faults/major")
+}
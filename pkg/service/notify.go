@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// notificationBuffer is the number of events a notification channel may lag
+// behind before it is resumed from the event history
+const notificationBuffer = 1024
+
+// consumeEvents hands the events matching filter to handle until the context
+// is cancelled. A channel that falls behind resumes after the last event it
+// handled. Events that are no longer retained by then are lost, which is
+// logged.
+func consumeEvents(ctx context.Context, appCtx *appContext.Context, channel string, filter appContext.EventFilter, handle func(*appContext.Event)) {
+	var lastSequence uint64
+	for {
+		sub, err := appCtx.SubscribeEvents(filter, notificationBuffer, lastSequence)
+		if errors.Is(err, models.ErrEventsExpired) {
+			logger.NotifyLog.Errorf("%s missed events after sequence %d, they are no longer retained", channel, lastSequence)
+			sub, err = appCtx.SubscribeEvents(filter, notificationBuffer, 0)
+		}
+		if err != nil {
+			logger.NotifyLog.Errorf("%s cannot subscribe to events: %v", channel, err)
+			return
+		}
+
+		for running := true; running; {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case event, ok := <-sub.C:
+				if !ok {
+					running = false
+					break
+				}
+				lastSequence = event.Sequence
+				handle(&event)
+			}
+		}
+
+		logger.NotifyLog.Warnf("%s fell behind at sequence %d, resuming: %v", channel, lastSequence, sub.Err())
+	}
+}
+
+// isNotifiable checks if an event should reach notification channels. Faults
+// suppressed by a maintenance window are expected and not announced.
+func isNotifiable(event *appContext.Event) bool {
+	return event.Fault == nil || !event.Fault.Suppressed
+}
+
+// eventDeviceSelector returns the model and tags used to match the device of
+// an event against a device selector. Tags are nil for unknown devices.
+func eventDeviceSelector(appCtx *appContext.Context, event *appContext.Event) (string, map[string]bool) {
+	device := event.Device
+	if device == nil && event.DeviceID != "" {
+		device, _ = appCtx.GetDevice(event.DeviceID)
+	}
+
+	var model string
+	if event.Fault != nil {
+		model = event.Fault.DeviceModel
+	}
+
+	if device == nil {
+		return model, nil
+	}
+	if model == "" {
+		model = device.DeviceID.ModelName
+	}
+	return model, device.Tags
+}
+
+// clientTLSConfig builds the TLS configuration for connections to a
+// notification server
+func clientTLSConfig(cfg *config.ClientTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg == nil {
+		return tlsConfig, nil
+	}
+
+	tlsConfig.ServerName = cfg.ServerName
+	tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+
+	if cfg.CA != "" {
+		pem, err := os.ReadFile(cfg.CA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in CA %s", cfg.CA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.Cert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
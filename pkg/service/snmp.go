@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Notification and varbind arcs below the enterprise OID, as defined by
+// mibs/NEXTRANET-GATEWAY-MIB.txt
+const (
+	mibNotifications = ".1.0."
+	mibAlarmObjects  = ".1.1.1."
+
+	notifyAlarmRaised       = 1
+	notifyAlarmAcknowledged = 2
+	notifyAlarmCleared      = 3
+	notifyAlarmResyncStart  = 4
+	notifyAlarmActive       = 5
+	notifyAlarmResyncEnd    = 6
+
+	objAlarmID          = 1
+	objDeviceID         = 2
+	objDeviceSerial     = 3
+	objDeviceModel      = 4
+	objAlarmCode        = 5
+	objAlarmSeverity    = 6
+	objAlarmMessage     = 7
+	objAlarmTime        = 8
+	objAlarmOperator    = 9
+	objEventSequence    = 10
+	objResyncID         = 11
+	objResyncAlarmCount = 12
+)
+
+// Standard varbinds leading every SNMPv2 notification
+const (
+	oidSysUpTime   = ".1.3.6.1.2.1.1.3.0"
+	oidSnmpTrapOID = ".1.3.6.1.6.3.1.1.4.1.0"
+)
+
+// trapSeverities maps fault severities to ntgAlarmSeverity, unknown
+// severities are indeterminate(0)
+var trapSeverities = map[string]int{
+	models.SeverityCritical: 1,
+	models.SeverityMajor:    2,
+	models.SeverityMinor:    3,
+	models.SeverityWarning:  4,
+	models.SeverityInfo:     5,
+}
+
+// SNMPTrapService sends fault lifecycle traps to the configured NMS targets
+// and periodically resynchronises their active alarms
+type SNMPTrapService struct {
+	config     *config.SNMPTraps
+	appContext *appContext.Context
+	targets    []*trapTarget
+	enterprise string
+	started    time.Time
+
+	// mu keeps the notifications of a resynchronisation together
+	mu       sync.Mutex
+	resyncID uint32
+}
+
+// trapTarget is a configured NMS
+type trapTarget struct {
+	name       string
+	host       string
+	port       uint16
+	version    gosnmp.SnmpVersion
+	community  string
+	msgFlags   gosnmp.SnmpV3MsgFlags
+	usm        *gosnmp.UsmSecurityParameters
+	severities map[string]bool
+}
+
+// NewSNMPTrapService creates a new SNMP trap service instance. It fails on
+// targets that cannot be resolved or credentials that cannot be localised.
+func NewSNMPTrapService(cfg *config.SNMPTraps, ctx *appContext.Context) (*SNMPTrapService, error) {
+	s := &SNMPTrapService{
+		config:     cfg,
+		appContext: ctx,
+		enterprise: "." + trimOID(cfg.EnterpriseOID),
+		started:    time.Now(),
+	}
+
+	engineID, err := snmpEngineID(cfg.EngineID)
+	if err != nil {
+		return nil, err
+	}
+	engineBoots, err := nextEngineBoots(cfg.EngineBootsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, targetCfg := range cfg.Targets {
+		target, err := newTrapTarget(targetCfg, engineID, engineBoots)
+		if err != nil {
+			return nil, fmt.Errorf("SNMP target %s: %w", targetCfg.Name, err)
+		}
+		if target.usm != nil {
+			logger.NotifyLog.Infof("SNMPv3 target %s uses engine ID %s, boots %d", target.name, hex.EncodeToString([]byte(engineID)), engineBoots)
+		}
+		s.targets = append(s.targets, target)
+	}
+	return s, nil
+}
+
+// newTrapTarget prepares a target. SNMPv3 keys are localised to the gateway
+// engine ID once, the gateway is the authoritative engine for traps.
+func newTrapTarget(cfg config.SNMPTarget, engineID string, engineBoots uint32) (*trapTarget, error) {
+	host, portValue, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portValue, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", portValue)
+	}
+
+	target := &trapTarget{
+		name:      cfg.Name,
+		host:      host,
+		port:      uint16(port),
+		version:   gosnmp.Version2c,
+		community: cfg.Community,
+	}
+	if len(cfg.Severities) > 0 {
+		target.severities = make(map[string]bool)
+		for _, severity := range cfg.Severities {
+			target.severities[severity] = true
+		}
+	}
+
+	if cfg.Version != "3" {
+		return target, nil
+	}
+
+	target.version = gosnmp.Version3
+	target.usm = &gosnmp.UsmSecurityParameters{
+		UserName:                 cfg.User.Name,
+		AuthoritativeEngineID:    engineID,
+		AuthoritativeEngineBoots: engineBoots,
+		AuthenticationPassphrase: cfg.User.AuthPassword,
+		PrivacyPassphrase:        cfg.User.PrivPassword,
+	}
+
+	switch cfg.User.SecurityLevel {
+	case "authPriv":
+		target.msgFlags = gosnmp.AuthPriv
+		target.usm.PrivacyProtocol = snmpPrivProtocols[cfg.User.PrivProtocol]
+		target.usm.AuthenticationProtocol = snmpAuthProtocols[cfg.User.AuthProtocol]
+	case "authNoPriv":
+		target.msgFlags = gosnmp.AuthNoPriv
+		target.usm.AuthenticationProtocol = snmpAuthProtocols[cfg.User.AuthProtocol]
+	default:
+		target.msgFlags = gosnmp.NoAuthNoPriv
+	}
+
+	if err := target.usm.InitSecurityKeys(); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+var snmpAuthProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"MD5":    gosnmp.MD5,
+	"SHA":    gosnmp.SHA,
+	"SHA224": gosnmp.SHA224,
+	"SHA256": gosnmp.SHA256,
+	"SHA384": gosnmp.SHA384,
+	"SHA512": gosnmp.SHA512,
+}
+
+var snmpPrivProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"DES":    gosnmp.DES,
+	"AES":    gosnmp.AES,
+	"AES192": gosnmp.AES192,
+	"AES256": gosnmp.AES256,
+}
+
+// snmpEngineID decodes the configured engine ID, or derives an RFC 3411 text
+// format engine ID from the host name
+func snmpEngineID(configured string) (string, error) {
+	if configured != "" {
+		engineID, err := hex.DecodeString(configured)
+		if err != nil {
+			return "", fmt.Errorf("invalid SNMP engine ID: %w", err)
+		}
+		return string(engineID), nil
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "nextranet-gateway"
+	}
+	if len(hostname) > 27 {
+		hostname = hostname[:27]
+	}
+	// Enterprise 32473 with the RFC 3411 format bit, then format 4 (text)
+	return string([]byte{0x80, 0x00, 0x7e, 0xd9, 0x04}) + hostname, nil
+}
+
+// nextEngineBoots increments the snmpEngineBoots counter kept in file and
+// returns it. The engine time of traps restarts at zero with the process,
+// receivers only accept that with a higher boots value (RFC 3414 3.2). The
+// counter is not persisted when file is empty.
+func nextEngineBoots(file string) (uint32, error) {
+	if file == "" {
+		return 1, nil
+	}
+
+	var boots uint64
+	data, err := os.ReadFile(file)
+	switch {
+	case err == nil:
+		boots, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid SNMP engine boots in %s: %w", file, err)
+		}
+	case !os.IsNotExist(err):
+		return 0, err
+	}
+
+	// The counter latches at its maximum, RFC 3414 2.2.2
+	if boots < math.MaxInt32 {
+		boots++
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return 0, err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(boots, 10)+"\n"), 0o600); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return 0, err
+	}
+	return uint32(boots), nil
+}
+
+// Start sends traps for fault events and resynchronises the targets until
+// the context is cancelled
+func (s *SNMPTrapService) Start(ctx context.Context) {
+	logger.NotifyLog.Infof("Starting SNMP traps to %d targets...", len(s.targets))
+
+	filter := appContext.EventFilter{Types: []string{
+		appContext.EventFaultRaised,
+		appContext.EventFaultAcknowledged,
+		appContext.EventFaultResolved,
+	}}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeEvents(ctx, s.appContext, "SNMP traps", filter, s.notify)
+	}()
+
+	// Start with a resynchronisation, the NMS may hold alarms from before a
+	// restart
+	s.resync()
+
+	ticker := time.NewTicker(s.config.ResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			logger.NotifyLog.Info("Stopped SNMP traps")
+			return
+		case <-ticker.C:
+			s.resync()
+		}
+	}
+}
+
+// notify sends the trap for a fault lifecycle event
+func (s *SNMPTrapService) notify(event *appContext.Event) {
+	if event.Fault == nil || !isNotifiable(event) {
+		return
+	}
+
+	var notification int
+	var operator string
+	switch event.Type {
+	case appContext.EventFaultRaised:
+		notification = notifyAlarmRaised
+	case appContext.EventFaultAcknowledged:
+		notification = notifyAlarmAcknowledged
+		operator = event.Fault.AcknowledgedBy
+	case appContext.EventFaultResolved:
+		notification = notifyAlarmCleared
+		operator = event.Fault.ResolvedBy
+	default:
+		return
+	}
+
+	varbinds := s.alarmVarbinds(event.Fault)
+	varbinds = append(varbinds, s.varbind(objEventSequence, gosnmp.Gauge32, uint32(event.Sequence)))
+	if notification != notifyAlarmRaised {
+		varbinds = append(varbinds, s.varbind(objAlarmOperator, gosnmp.OctetString, operator))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, target := range s.targets {
+		if target.accepts(event.Fault.Severity) {
+			s.send(target, notification, varbinds)
+		}
+	}
+}
+
+// resync sends every target its active alarms between a start and an end
+// notification sharing one resync ID
+func (s *SNMPTrapService) resync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Events up to this sequence are reflected in the alarms sent
+	sequence := uint32(s.appContext.EventSequence())
+	faults := s.appContext.GetActiveFaults()
+	s.resyncID++
+
+	for _, target := range s.targets {
+		var active []*models.Fault
+		for _, fault := range faults {
+			if !fault.Suppressed && target.accepts(fault.Severity) {
+				active = append(active, fault)
+			}
+		}
+
+		summary := []gosnmp.SnmpPDU{
+			s.varbind(objResyncID, gosnmp.Gauge32, s.resyncID),
+			s.varbind(objResyncAlarmCount, gosnmp.Gauge32, uint32(len(active))),
+			s.varbind(objEventSequence, gosnmp.Gauge32, sequence),
+		}
+
+		s.send(target, notifyAlarmResyncStart, summary)
+		for _, fault := range active {
+			varbinds := append(s.alarmVarbinds(fault), s.varbind(objResyncID, gosnmp.Gauge32, s.resyncID))
+			s.send(target, notifyAlarmActive, varbinds)
+		}
+		s.send(target, notifyAlarmResyncEnd, summary)
+
+		logger.NotifyLog.Debugf("Resynchronised %d active alarms with SNMP target %s", len(active), target.name)
+	}
+}
+
+// alarmVarbinds returns the varbinds describing a fault
+func (s *SNMPTrapService) alarmVarbinds(fault *models.Fault) []gosnmp.SnmpPDU {
+	return []gosnmp.SnmpPDU{
+		s.varbind(objAlarmID, gosnmp.OctetString, fault.ID),
+		s.varbind(objDeviceID, gosnmp.OctetString, fault.DeviceID),
+		s.varbind(objDeviceSerial, gosnmp.OctetString, fault.DeviceSerial),
+		s.varbind(objDeviceModel, gosnmp.OctetString, fault.DeviceModel),
+		s.varbind(objAlarmCode, gosnmp.OctetString, fault.Code),
+		s.varbind(objAlarmSeverity, gosnmp.Integer, trapSeverities[fault.Severity]),
+		s.varbind(objAlarmMessage, gosnmp.OctetString, fault.Message),
+		s.varbind(objAlarmTime, gosnmp.OctetString, fault.Timestamp.UTC().Format(time.RFC3339)),
+	}
+}
+
+// varbind builds a varbind for an object of the alarm object group
+func (s *SNMPTrapService) varbind(object int, asn1Type gosnmp.Asn1BER, value interface{}) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{
+		Name:  s.enterprise + mibAlarmObjects + strconv.Itoa(object),
+		Type:  asn1Type,
+		Value: value,
+	}
+}
+
+// send emits one notification to a target. A new client is used for every
+// trap so SNMPv3 privacy gets a fresh salt, the library does not advance it
+// between packets of a client. Must be called with mu held.
+func (s *SNMPTrapService) send(target *trapTarget, notification int, varbinds []gosnmp.SnmpPDU) {
+	client := &gosnmp.GoSNMP{
+		Target:    target.host,
+		Port:      target.port,
+		Transport: "udp",
+		Version:   target.version,
+		Community: target.community,
+		Timeout:   5 * time.Second,
+		MaxOids:   gosnmp.MaxOids,
+	}
+	if target.usm != nil {
+		usm := target.usm.Copy().(*gosnmp.UsmSecurityParameters)
+		usm.AuthoritativeEngineTime = uint32(time.Since(s.started) / time.Second)
+		client.SecurityModel = gosnmp.UserSecurityModel
+		client.MsgFlags = target.msgFlags
+		client.SecurityParameters = usm
+	}
+
+	if err := client.Connect(); err != nil {
+		logger.NotifyLog.Warnf("Failed to reach SNMP target %s: %v", target.name, err)
+		return
+	}
+	defer client.Conn.Close()
+
+	trap := gosnmp.SnmpTrap{
+		Variables: append([]gosnmp.SnmpPDU{
+			{Name: oidSysUpTime, Type: gosnmp.TimeTicks, Value: uint32(time.Since(s.started) / (10 * time.Millisecond))},
+			{Name: oidSnmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: s.enterprise + mibNotifications + strconv.Itoa(notification)},
+		}, varbinds...),
+	}
+	if _, err := client.SendTrap(trap); err != nil {
+		logger.NotifyLog.Warnf("Failed to send SNMP trap to %s: %v", target.name, err)
+	}
+}
+
+// accepts checks the severity filter of a target
+func (t *trapTarget) accepts(severity string) bool {
+	return t.severities == nil || t.severities[severity]
+}
+
+// trimOID removes the leading dot of an OID
+func trimOID(oid string) string {
+	if len(oid) > 0 && oid[0] == '.' {
+		return oid[1:]
+	}
+	return oid
+}
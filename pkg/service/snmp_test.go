@@ -0,0 +1,205 @@
+package service
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+const testEnterprise = ".1.3.6.1.4.1.32473"
+
+// testEngineID is the engine ID of the gateway in SNMPv3 tests
+const testEngineID = "80007ed9047465737467617465776179"
+
+// listenTraps opens a local UDP trap receiver
+func listenTraps(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receiveTrap reads one trap and decodes it with the receiver parameters
+func receiveTrap(t *testing.T, conn net.PacketConn, receiver *gosnmp.GoSNMP) *gosnmp.SnmpPacket {
+	t.Helper()
+	buf := make([]byte, 65535)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("no trap received: %v", err)
+	}
+	packet, err := receiver.UnmarshalTrap(buf[:n], false)
+	if err != nil {
+		t.Fatalf("UnmarshalTrap: %v", err)
+	}
+	return packet
+}
+
+// trapVariables maps the varbinds of a trap by OID
+func trapVariables(packet *gosnmp.SnmpPacket) map[string]interface{} {
+	variables := make(map[string]interface{}, len(packet.Variables))
+	for _, variable := range packet.Variables {
+		value := variable.Value
+		if bytes, ok := value.([]byte); ok {
+			value = string(bytes)
+		}
+		variables[variable.Name] = value
+	}
+	return variables
+}
+
+// newTestTrapService creates a trap service sending to one target
+func newTestTrapService(t *testing.T, target config.SNMPTarget, bootsFile string) *SNMPTrapService {
+	t.Helper()
+	s, err := NewSNMPTrapService(&config.SNMPTraps{
+		Enabled:         true,
+		EnterpriseOID:   testEnterprise,
+		EngineID:        testEngineID,
+		EngineBootsFile: bootsFile,
+		Targets:         []config.SNMPTarget{target},
+	}, appContext.NewContext())
+	if err != nil {
+		t.Fatalf("NewSNMPTrapService: %v", err)
+	}
+	return s
+}
+
+func testFaultEvent(eventType string) *appContext.Event {
+	return &appContext.Event{
+		Sequence: 42,
+		Type:     eventType,
+		DeviceID: "A1B2C3-CPE-0001",
+		Fault: &models.Fault{
+			ID:             "fault-1",
+			DeviceID:       "A1B2C3-CPE-0001",
+			DeviceSerial:   "0001",
+			Code:           "cwmp.9002",
+			Message:        "Internal error",
+			Severity:       models.SeverityMajor,
+			Status:         models.FaultStatusAcknowledged,
+			AcknowledgedBy: "alice",
+			Timestamp:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+}
+
+func TestSNMPTrapV2c(t *testing.T) {
+	conn := listenTraps(t)
+	s := newTestTrapService(t, config.SNMPTarget{
+		Name:      "noc",
+		Address:   conn.LocalAddr().String(),
+		Version:   "2c",
+		Community: "public",
+	}, "")
+
+	s.notify(testFaultEvent(appContext.EventFaultAcknowledged))
+
+	packet := receiveTrap(t, conn, &gosnmp.GoSNMP{Version: gosnmp.Version2c})
+	if packet.Version != gosnmp.Version2c || packet.Community != "public" {
+		t.Fatalf("got %s trap with community %q, want 2c with public", packet.Version, packet.Community)
+	}
+
+	variables := trapVariables(packet)
+	expected := map[string]interface{}{
+		oidSnmpTrapOID:                          testEnterprise + ".1.0.2",
+		testEnterprise + ".1.1.1.1":             "fault-1",
+		testEnterprise + ".1.1.1.5":             "cwmp.9002",
+		testEnterprise + ".1.1.1.6":             2,
+		testEnterprise + ".1.1.1.8":             "2026-01-02T03:04:05Z",
+		testEnterprise + ".1.1.1.9":             "alice",
+		testEnterprise + mibAlarmObjects + "10": uint(42),
+	}
+	for oid, value := range expected {
+		if variables[oid] != value {
+			t.Errorf("varbind %s = %v (%T), want %v (%T)", oid, variables[oid], variables[oid], value, value)
+		}
+	}
+}
+
+func TestSNMPTrapV3(t *testing.T) {
+	conn := listenTraps(t)
+	bootsFile := filepath.Join(t.TempDir(), "snmp-engine-boots")
+	target := config.SNMPTarget{
+		Name:    "noc-v3",
+		Address: conn.LocalAddr().String(),
+		Version: "3",
+		User: &config.SNMPUser{
+			Name:          "gateway",
+			SecurityLevel: "authPriv",
+			AuthProtocol:  "SHA",
+			AuthPassword:  "change-me-auth",
+			PrivProtocol:  "AES",
+			PrivPassword:  "change-me-priv",
+		},
+	}
+
+	engineID, err := snmpEngineID(testEngineID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := &gosnmp.GoSNMP{
+		Version:       gosnmp.Version3,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 "gateway",
+			AuthoritativeEngineID:    engineID,
+			AuthenticationProtocol:   gosnmp.SHA,
+			AuthenticationPassphrase: "change-me-auth",
+			PrivacyProtocol:          gosnmp.AES,
+			PrivacyPassphrase:        "change-me-priv",
+		},
+	}
+
+	// Every start of the service is a new boot of the engine
+	for boots := uint32(1); boots <= 2; boots++ {
+		s := newTestTrapService(t, target, bootsFile)
+		s.notify(testFaultEvent(appContext.EventFaultRaised))
+
+		packet := receiveTrap(t, conn, receiver)
+		if packet.Version != gosnmp.Version3 {
+			t.Fatalf("got %s trap, want 3", packet.Version)
+		}
+		usm, ok := packet.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		if !ok {
+			t.Fatalf("trap without USM security parameters")
+		}
+		if usm.AuthoritativeEngineID != engineID || usm.UserName != "gateway" {
+			t.Errorf("trap from engine %x user %s, want %x gateway", usm.AuthoritativeEngineID, usm.UserName, engineID)
+		}
+		if usm.AuthoritativeEngineBoots != boots {
+			t.Errorf("trap sent with engine boots %d, want %d", usm.AuthoritativeEngineBoots, boots)
+		}
+
+		variables := trapVariables(packet)
+		if variables[oidSnmpTrapOID] != testEnterprise+".1.0.1" {
+			t.Errorf("snmpTrapOID = %v, want ntgAlarmRaised", variables[oidSnmpTrapOID])
+		}
+		if variables[testEnterprise+".1.1.1.1"] != "fault-1" {
+			t.Errorf("ntgAlarmId = %v, want fault-1", variables[testEnterprise+".1.1.1.1"])
+		}
+	}
+}
+
+func TestNextEngineBoots(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "data", "snmp-engine-boots")
+	for want := uint32(1); want <= 3; want++ {
+		boots, err := nextEngineBoots(file)
+		if err != nil {
+			t.Fatalf("nextEngineBoots: %v", err)
+		}
+		if boots != want {
+			t.Errorf("boots = %d, want %d", boots, want)
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Syslog severities of RFC 5424
+const (
+	syslogCritical      = 2
+	syslogError         = 3
+	syslogWarning       = 4
+	syslogNotice        = 5
+	syslogInformational = 6
+)
+
+// syslogFacilities maps facility keywords to their RFC 5424 codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// faultSyslogSeverities maps fault severities following RFC 5674, which
+// maps ITU perceived severities to syslog. Unknown severities are
+// indeterminate, which is notice.
+var faultSyslogSeverities = map[string]int{
+	models.SeverityCritical: syslogCritical,
+	models.SeverityMajor:    syslogError,
+	models.SeverityMinor:    syslogWarning,
+	models.SeverityWarning:  syslogNotice,
+	models.SeverityInfo:     syslogInformational,
+}
+
+// syslogTimestamp is the RFC 5424 timestamp, limited to microseconds
+const syslogTimestamp = "2006-01-02T15:04:05.000000Z07:00"
+
+// SyslogService forwards events and the operator actions of the audit log
+// to a syslog collector as RFC 5424 messages with structured data
+type SyslogService struct {
+	config     *config.Syslog
+	appContext *appContext.Context
+	tlsConfig  *tls.Config
+	filter     appContext.EventFilter
+	facility   int
+	// auditFacility is the facility of logins and of user and API key
+	// management
+	auditFacility int
+	hostname      string
+	procID        string
+
+	// queue holds the formatted messages, the oldest are dropped when the
+	// collector cannot keep up
+	queue     chan []byte
+	enqueueMu sync.Mutex
+	dropped   int
+}
+
+// NewSyslogService creates a new syslog forwarder. It fails on unknown
+// event types and TLS material that cannot be loaded.
+func NewSyslogService(cfg *config.Syslog, ctx *appContext.Context) (*SyslogService, error) {
+	for _, pattern := range cfg.Events {
+		if !appContext.IsValidEventPattern(pattern) {
+			return nil, fmt.Errorf("invalid syslog event type: %s", pattern)
+		}
+	}
+
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	s := &SyslogService{
+		config:        cfg,
+		appContext:    ctx,
+		filter:        appContext.EventFilter{Types: cfg.Events},
+		facility:      syslogFacilities[cfg.Facility],
+		auditFacility: syslogFacilities[cfg.AuditFacility],
+		hostname:      syslogHeaderField(hostname, 255),
+		procID:        strconv.Itoa(os.Getpid()),
+		queue:         make(chan []byte, cfg.BufferSize),
+	}
+
+	if cfg.Network == "tls" {
+		tlsConfig, err := clientTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("syslog TLS: %w", err)
+		}
+		s.tlsConfig = tlsConfig
+	}
+	return s, nil
+}
+
+// Start forwards events until the context is cancelled. Messages queued
+// while the collector is unreachable are sent once it is back.
+func (s *SyslogService) Start(ctx context.Context) {
+	logger.NotifyLog.Infof("Starting syslog forwarding to %s over %s...", s.config.Address, s.config.Network)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeEvents(ctx, s.appContext, "Syslog forwarding", s.filter, func(event *appContext.Event) {
+			if isNotifiable(event) {
+				s.enqueue(s.format(event))
+			}
+		})
+	}()
+	if s.config.Audit {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.watchAudit(ctx)
+		}()
+	}
+
+	s.forward(ctx)
+	wg.Wait()
+	logger.NotifyLog.Info("Stopped syslog forwarding")
+}
+
+// enqueue queues a message, dropping the oldest one when the buffer is full.
+// Events and audit records are enqueued one at a time, so the retry cannot
+// race another sender.
+func (s *SyslogService) enqueue(message []byte) {
+	s.enqueueMu.Lock()
+	defer s.enqueueMu.Unlock()
+
+	select {
+	case s.queue <- message:
+		return
+	default:
+	}
+
+	select {
+	case <-s.queue:
+		s.dropped++
+		if s.dropped == 1 || s.dropped%1000 == 0 {
+			logger.NotifyLog.Warnf("Syslog buffer full, dropped %d messages", s.dropped)
+		}
+	default:
+	}
+
+	select {
+	case s.queue <- message:
+	default:
+	}
+}
+
+// watchAudit forwards the operator actions of the audit log until the
+// context is cancelled. After falling behind it catches up from the records
+// kept in memory.
+func (s *SyslogService) watchAudit(ctx context.Context) {
+	var lastSeq uint64
+	for {
+		records, cancel := s.appContext.WatchAudit(notificationBuffer)
+		if lastSeq > 0 {
+			lastSeq = s.catchUpAudit(lastSeq)
+		}
+
+		for running := true; running; {
+			select {
+			case <-ctx.Done():
+				cancel()
+				return
+			case record, ok := <-records:
+				if !ok {
+					running = false
+					break
+				}
+				// Records chained while catching up are delivered twice
+				if record.Seq <= lastSeq {
+					continue
+				}
+				lastSeq = record.Seq
+				s.enqueue(s.formatAudit(record))
+			}
+		}
+
+		logger.NotifyLog.Warnf("Syslog forwarding fell behind the audit log at record %d, catching up", lastSeq)
+	}
+}
+
+// catchUpAudit forwards the records in memory chained after lastSeq and
+// returns the last one forwarded. Records no longer in memory are lost,
+// which is logged.
+func (s *SyslogService) catchUpAudit(lastSeq uint64) uint64 {
+	newest := s.appContext.GetAuditRecords(models.AuditFilter{})
+	missed := 0
+	for missed < len(newest) && newest[missed].Seq > lastSeq {
+		missed++
+	}
+	if missed > 0 && newest[missed-1].Seq > lastSeq+1 {
+		logger.NotifyLog.Errorf("Syslog forwarding missed audit records %d to %d, they are no longer in memory", lastSeq+1, newest[missed-1].Seq-1)
+	}
+
+	for i := missed - 1; i >= 0; i-- {
+		s.enqueue(s.formatAudit(newest[i]))
+		lastSeq = newest[i].Seq
+	}
+	return lastSeq
+}
+
+// forward writes queued messages to the collector, reconnecting with
+// backoff. A message that failed to be written is retried on the next
+// connection.
+func (s *SyslogService) forward(ctx context.Context) {
+	var conn net.Conn
+	var pending []byte
+	backoff := s.config.ReconnectBackoff
+
+	defer func() {
+		if conn != nil {
+			s.flush(conn, pending)
+			conn.Close()
+		}
+	}()
+
+	for {
+		if pending == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case pending = <-s.queue:
+			}
+		}
+
+		if conn == nil {
+			var err error
+			conn, err = s.dial(ctx)
+			if err != nil {
+				logger.NotifyLog.Warnf("Failed to connect to syslog collector %s, retrying in %s: %v", s.config.Address, backoff, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				backoff = min(backoff*2, s.config.MaxReconnectBackoff)
+				continue
+			}
+			logger.NotifyLog.Infof("Connected to syslog collector %s", s.config.Address)
+			backoff = s.config.ReconnectBackoff
+		}
+
+		if err := s.write(conn, pending); err != nil {
+			logger.NotifyLog.Warnf("Failed to write to syslog collector %s: %v", s.config.Address, err)
+			conn.Close()
+			conn = nil
+			continue
+		}
+		pending = nil
+	}
+}
+
+// flush sends what is still queued on shutdown, giving up at the first
+// error
+func (s *SyslogService) flush(conn net.Conn, pending []byte) {
+	if pending != nil && s.write(conn, pending) != nil {
+		return
+	}
+	for {
+		select {
+		case message := <-s.queue:
+			if s.write(conn, message) != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// dial connects to the collector
+func (s *SyslogService) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: s.config.WriteTimeout}
+	if s.tlsConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+		return tlsDialer.DialContext(ctx, "tcp", s.config.Address)
+	}
+	return dialer.DialContext(ctx, s.config.Network, s.config.Address)
+}
+
+// write sends one message. Datagrams carry a single message, streams prefix
+// it with its length.
+func (s *SyslogService) write(conn net.Conn, message []byte) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(s.config.WriteTimeout)); err != nil {
+		return err
+	}
+	if s.config.Network == "udp" {
+		_, err := conn.Write(message)
+		return err
+	}
+	frame := append([]byte(strconv.Itoa(len(message))+" "), message...)
+	_, err := conn.Write(frame)
+	return err
+}
+
+// format renders an event as an RFC 5424 message. The MSGID is the event
+// type and the device, fault, operator and task fields go into structured
+// data elements.
+func (s *SyslogService) format(event *appContext.Event) []byte {
+	var sd strings.Builder
+	s.element(&sd, "event",
+		"type", event.Type,
+		"sequence", strconv.FormatUint(event.Sequence, 10))
+
+	var msg string
+	switch {
+	case event.Fault != nil:
+		fault := event.Fault
+		s.element(&sd, "fault",
+			"id", fault.ID,
+			"code", fault.Code,
+			"severity", fault.Severity,
+			"status", fault.Status,
+			"channel", fault.Channel,
+			"incident", fault.IncidentID)
+
+		switch event.Type {
+		case appContext.EventFaultAcknowledged:
+			s.element(&sd, "operator", "user", fault.AcknowledgedBy, "action", "acknowledge")
+			msg = fmt.Sprintf("Fault %s acknowledged: %s", fault.Code, fault.Message)
+		case appContext.EventFaultResolved:
+			s.element(&sd, "operator",
+				"user", fault.ResolvedBy,
+				"action", "resolve",
+				"category", fault.ResolutionCategory)
+			msg = fmt.Sprintf("Fault %s resolved: %s", fault.Code, fault.Message)
+		case appContext.EventFaultEscalated:
+			if escalation := event.Escalation; escalation != nil {
+				s.element(&sd, "escalation",
+					"policy", escalation.PolicyName,
+					"level", strconv.Itoa(escalation.Level))
+				msg = fmt.Sprintf("Fault %s escalated to level %d of %s: %s", fault.Code, escalation.Level, escalation.PolicyName, fault.Message)
+			}
+		default:
+			msg = fmt.Sprintf("Fault %s raised: %s", fault.Code, fault.Message)
+		}
+	case event.Task != nil:
+		s.element(&sd, "task", "id", event.Task.ID, "name", event.Task.Name, "status", event.Task.Status)
+		msg = fmt.Sprintf("Task %s %s", event.Task.Name, event.Task.Status)
+	case event.ACSStatus != nil:
+		status := event.ACSStatus
+		s.element(&sd, "acs",
+			"cwmp", strconv.FormatBool(status.CWMPConnected),
+			"nbi", strconv.FormatBool(status.NBIConnected),
+			"fs", strconv.FormatBool(status.FSConnected))
+		msg = "GenieACS connectivity changed"
+		if status.LastError != "" {
+			msg += ": " + status.LastError
+		}
+	default:
+		msg = "Device " + strings.TrimPrefix(event.Type, "device.")
+	}
+
+	if event.DeviceID != "" {
+		s.deviceElement(&sd, event)
+	}
+
+	return s.message(s.facility*8+syslogSeverity(event), event.Timestamp, event.Type, sd.String(), msg)
+}
+
+// formatAudit renders an operator action of the audit log. The MSGID is
+// audit, logins and user and API key management use the audit facility.
+func (s *SyslogService) formatAudit(record *models.AuditRecord) []byte {
+	var sd strings.Builder
+	s.element(&sd, "audit",
+		"seq", strconv.FormatUint(record.Seq, 10),
+		"requestId", record.RequestID,
+		"source", record.Source)
+	s.element(&sd, "operator",
+		"user", record.Actor,
+		"authMethod", record.AuthMethod,
+		"clientIp", record.ClientIP,
+		"action", record.Action,
+		"outcome", record.Outcome,
+		"status", strconv.Itoa(record.Status))
+
+	// Parameters may repeat within an element, an SD-ID may not
+	if len(record.Targets) > 0 {
+		params := make([]string, 0, 2*len(record.Targets))
+		for _, target := range record.Targets {
+			params = append(params, "id", target)
+		}
+		s.element(&sd, "target", params...)
+	}
+	if len(record.Changes) > 0 {
+		params := make([]string, 0, 2*len(record.Changes))
+		for _, change := range record.Changes {
+			params = append(params, "field", change.Field)
+		}
+		s.element(&sd, "change", params...)
+	}
+
+	msg := fmt.Sprintf("%s by %s: %s", record.Action, record.Actor, record.Outcome)
+	if record.Error != "" {
+		msg += ": " + record.Error
+	}
+
+	facility := s.facility
+	if isSecurityAction(record.Action) {
+		facility = s.auditFacility
+	}
+	return s.message(facility*8+auditSyslogSeverity(record), record.Timestamp, "audit", sd.String(), msg)
+}
+
+// message assembles an RFC 5424 message from its priority, header fields,
+// structured data and text
+func (s *SyslogService) message(priority int, timestamp time.Time, msgID, sd, msg string) []byte {
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s ",
+		priority,
+		timestamp.UTC().Format(syslogTimestamp),
+		nilValue(s.hostname),
+		nilValue(syslogHeaderField(s.config.AppName, 48)),
+		s.procID,
+		nilValue(syslogHeaderField(msgID, 32)))
+
+	// The BOM marks the message as UTF-8, as RFC 5424 asks
+	return []byte(header + sd + " \ufeff" + msg)
+}
+
+// deviceElement adds the device fields of an event, taken from the fault
+// when the device is no longer known
+func (s *SyslogService) deviceElement(sd *strings.Builder, event *appContext.Event) {
+	device := event.Device
+	if device == nil {
+		device, _ = s.appContext.GetDevice(event.DeviceID)
+	}
+
+	params := []string{"id", event.DeviceID}
+	switch {
+	case device != nil:
+		params = append(params,
+			"serial", device.DeviceID.SerialNumber,
+			"model", device.DeviceID.ModelName,
+			"oui", device.DeviceID.OUI,
+			"productClass", device.DeviceID.ProductClass)
+	case event.Fault != nil:
+		params = append(params,
+			"serial", event.Fault.DeviceSerial,
+			"model", event.Fault.DeviceModel)
+	}
+	s.element(sd, "device", params...)
+}
+
+// element appends an SD-ELEMENT with an ID qualified by the enterprise
+// number. Empty parameters are left out.
+func (s *SyslogService) element(sd *strings.Builder, id string, params ...string) {
+	sd.WriteString("[" + id + "@" + s.config.EnterpriseID)
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i+1] == "" {
+			continue
+		}
+		sd.WriteString(" " + params[i] + `="` + escapeParamValue(params[i+1]) + `"`)
+	}
+	sd.WriteString("]")
+}
+
+// syslogSeverity maps an event to a syslog severity. Raised and escalated
+// faults keep their own severity, operator actions are notices.
+func syslogSeverity(event *appContext.Event) int {
+	switch event.Type {
+	case appContext.EventFaultRaised, appContext.EventFaultEscalated:
+		if severity, ok := faultSyslogSeverities[event.Severity]; ok {
+			return severity
+		}
+		return syslogNotice
+	case appContext.EventFaultAcknowledged, appContext.EventFaultResolved, appContext.EventTaskQueued:
+		return syslogNotice
+	case appContext.EventTaskFailed, appContext.EventDeviceWentOffline:
+		return syslogWarning
+	case appContext.EventACSConnectivityChanged:
+		if status := event.ACSStatus; status != nil && !(status.CWMPConnected && status.NBIConnected) {
+			return syslogError
+		}
+		return syslogNotice
+	}
+	return syslogInformational
+}
+
+// securityRoutes are the route fragments of logins and of user, password
+// and API key management
+var securityRoutes = []string{"/login", "/logout", "/auth/", "/users", "/account/"}
+
+// isSecurityAction checks if an audited action is a login or manages users
+// or API keys
+func isSecurityAction(action string) bool {
+	for _, route := range securityRoutes {
+		if strings.Contains(action, route) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditSyslogSeverity maps an operator action to a syslog severity.
+// Actions are notices like the fault operator actions, denied and failed
+// ones are warnings.
+func auditSyslogSeverity(record *models.AuditRecord) int {
+	if record.Outcome == models.AuditOutcomeSuccess {
+		return syslogNotice
+	}
+	return syslogWarning
+}
+
+var paramValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// escapeParamValue escapes the characters RFC 5424 reserves in PARAM-VALUE
+func escapeParamValue(value string) string {
+	return paramValueEscaper.Replace(value)
+}
+
+// syslogHeaderField keeps the printable US-ASCII characters header fields
+// allow, up to max of them
+func syslogHeaderField(value string, max int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < '!' || r > '~' {
+			return -1
+		}
+		return r
+	}, value)
+	if len(field) > max {
+		field = field[:max]
+	}
+	return field
+}
+
+// nilValue returns the RFC 5424 NILVALUE for empty header fields
+func nilValue(field string) string {
+	if field == "" {
+		return "-"
+	}
+	return field
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// newTestSyslogService creates a forwarder of the audit log to a local UDP
+// collector
+func newTestSyslogService(t *testing.T, appCtx *appContext.Context, address string) *SyslogService {
+	t.Helper()
+	s, err := NewSyslogService(&config.Syslog{
+		Enabled:          true,
+		Network:          "udp",
+		Address:          address,
+		Facility:         "local0",
+		AuditFacility:    "authpriv",
+		AppName:          "gateway",
+		EnterpriseID:     "32473",
+		Events:           []string{"fault.*"},
+		Audit:            true,
+		BufferSize:       16,
+		WriteTimeout:     time.Second,
+		ReconnectBackoff: 10 * time.Millisecond,
+	}, appCtx)
+	if err != nil {
+		t.Fatalf("NewSyslogService: %v", err)
+	}
+	return s
+}
+
+func TestSyslogAuditFormat(t *testing.T) {
+	s := newTestSyslogService(t, appContext.NewContext(), "127.0.0.1:514")
+	timestamp := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		record   *models.AuditRecord
+		priority string
+		contains []string
+	}{
+		{
+			name: "reboot",
+			record: &models.AuditRecord{
+				Seq: 7, Timestamp: timestamp, Actor: "alice", AuthMethod: "session", ClientIP: "10.0.0.5",
+				Source: models.AuditSourceUI, Action: "POST /api/devices/:deviceId/reboot",
+				Targets: []string{"cpe-1"}, Status: 202, Outcome: models.AuditOutcomeSuccess,
+			},
+			// local0 notice
+			priority: "<133>1 2026-10-18T09:30:00.000000Z ",
+			contains: []string{
+				` gateway ` + s.procID + ` audit [audit@32473 seq="7" source="ui"]`,
+				`[operator@32473 user="alice" authMethod="session" clientIp="10.0.0.5" action="POST /api/devices/:deviceId/reboot" outcome="success" status="202"]`,
+				`[target@32473 id="cpe-1"]`,
+				"\ufeffPOST /api/devices/:deviceId/reboot by alice: success",
+			},
+		},
+		{
+			name: "parameter change",
+			record: &models.AuditRecord{
+				Seq: 8, Timestamp: timestamp, Actor: "ops", Source: models.AuditSourceNBI,
+				Action: "PUT /api/v1/devices/:deviceId/parameters", Targets: []string{"cpe-1"}, Status: 200,
+				Outcome: models.AuditOutcomeSuccess,
+				Changes: []models.AuditChange{
+					{Target: "cpe-1", Field: "Device.WiFi.SSID.1.SSID"},
+					{Target: "cpe-1", Field: "Device.WiFi.SSID.1.Enable"},
+				},
+			},
+			priority: "<133>1 ",
+			contains: []string{`[change@32473 field="Device.WiFi.SSID.1.SSID" field="Device.WiFi.SSID.1.Enable"]`},
+		},
+		{
+			name: "denied API key creation",
+			record: &models.AuditRecord{
+				Seq: 9, Timestamp: timestamp, Actor: "mallory", Source: models.AuditSourceNBI,
+				Action: "POST /api/v1/auth/keys", Status: 403, Outcome: models.AuditOutcomeDenied,
+				Error: "permission denied",
+			},
+			// authpriv warning
+			priority: "<84>1 ",
+			contains: []string{"by mallory: denied: permission denied"},
+		},
+		{
+			name: "user deletion",
+			record: &models.AuditRecord{
+				Seq: 10, Timestamp: timestamp, Actor: "admin", Source: models.AuditSourceUI,
+				Action: "DELETE /api/users/:userId", Targets: []string{"user-2"}, Status: 200,
+				Outcome: models.AuditOutcomeSuccess,
+			},
+			// authpriv notice
+			priority: "<85>1 ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := string(s.formatAudit(tt.record))
+			if !strings.HasPrefix(message, tt.priority) {
+				t.Errorf("message %q does not start with %q", message, tt.priority)
+			}
+			for _, part := range tt.contains {
+				if !strings.Contains(message, part) {
+					t.Errorf("message %q lacks %q", message, part)
+				}
+			}
+		})
+	}
+}
+
+func TestSyslogAuditForwarding(t *testing.T) {
+	conn := listenTraps(t)
+	appCtx := appContext.NewContext()
+	s := newTestSyslogService(t, appCtx, conn.LocalAddr().String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Start(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Records chained before the audit log is watched are not forwarded,
+	// append until one arrives
+	buf := make([]byte, 65535)
+	var message string
+	for attempt := 0; attempt < 50 && message == ""; attempt++ {
+		record := &models.AuditRecord{
+			Timestamp: time.Now(), Actor: "alice", Source: models.AuditSourceNBI,
+			Action: "POST /api/v1/devices/:deviceId/factory-reset", Targets: []string{"cpe-1"},
+			Status: 202, Outcome: models.AuditOutcomeSuccess,
+		}
+		if err := appCtx.AppendAuditRecord(record); err != nil {
+			t.Fatalf("AppendAuditRecord: %v", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if n, _, err := conn.ReadFrom(buf); err == nil {
+			message = string(buf[:n])
+		}
+	}
+	if !strings.Contains(message, " audit [audit@32473") || !strings.Contains(message, "factory-reset by alice: success") {
+		t.Fatalf("got %q, want the factory reset forwarded", message)
+	}
+}
+
+func TestSyslogAuditCatchUp(t *testing.T) {
+	appCtx := appContext.NewContext()
+	s := newTestSyslogService(t, appCtx, "127.0.0.1:514")
+
+	for _, actor := range []string{"alice", "bob", "carol"} {
+		record := &models.AuditRecord{Timestamp: time.Now(), Actor: actor, Action: "POST /api/v1/devices/:deviceId/reboot", Outcome: models.AuditOutcomeSuccess}
+		if err := appCtx.AppendAuditRecord(record); err != nil {
+			t.Fatalf("AppendAuditRecord: %v", err)
+		}
+	}
+
+	// The records after the first are forwarded in order
+	if lastSeq := s.catchUpAudit(1); lastSeq != 3 {
+		t.Errorf("caught up to record %d, want 3", lastSeq)
+	}
+	for _, actor := range []string{"bob", "carol"} {
+		select {
+		case message := <-s.queue:
+			if !strings.Contains(string(message), "by "+actor+":") {
+				t.Errorf("got %q, want the record of %s", message, actor)
+			}
+		default:
+			t.Fatalf("record of %s not forwarded", actor)
+		}
+	}
+	if len(s.queue) != 0 {
+		t.Errorf("%d records forwarded twice", len(s.queue))
+	}
+}
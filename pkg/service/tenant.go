@@ -0,0 +1,46 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// tenantQuery returns the GenieACS query clause selecting the devices of a
+// tenant, so results stay paginated by GenieACS. It is wrapped in $and as
+// the IP range clause may use $or too. A nil tenant selects no device.
+func tenantQuery(tenant *models.Tenant) map[string]interface{} {
+	if tenant == nil || tenant.Selector.IsEmpty() {
+		return map[string]interface{}{
+			"_id": map[string]interface{}{"$in": []string{}},
+		}
+	}
+
+	var clauses []interface{}
+	if len(tenant.Selector.OUIs) > 0 {
+		// GenieACS compares exactly, the gateway ignores case
+		ouis := make([]string, 0, 2*len(tenant.Selector.OUIs))
+		for _, oui := range tenant.Selector.OUIs {
+			ouis = append(ouis, strings.ToUpper(oui), strings.ToLower(oui))
+		}
+		clauses = append(clauses, map[string]interface{}{
+			"_deviceId._OUI": map[string]interface{}{"$in": ouis},
+		})
+	}
+	if len(tenant.Selector.Tags) > 0 {
+		clauses = append(clauses, map[string]interface{}{
+			"_tags": map[string]interface{}{"$in": tenant.Selector.Tags},
+		})
+	}
+	if len(tenant.Selector.ProvisioningCodes) > 0 {
+		clauses = append(clauses, map[string]interface{}{
+			"_deviceId._ProvisioningCode": map[string]interface{}{"$in": tenant.Selector.ProvisioningCodes},
+		})
+	}
+
+	return map[string]interface{}{
+		"$and": []interface{}{
+			map[string]interface{}{"$or": clauses},
+		},
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nextranet/gateway/c-plane/config"
+	appContext "github.com/nextranet/gateway/c-plane/internal/context"
+	"github.com/nextranet/gateway/c-plane/internal/logger"
+	"github.com/nextranet/gateway/c-plane/internal/models"
+)
+
+// Webhook request headers. The signature is the hex encoded HMAC-SHA256 of
+// the timestamp, a dot and the request body, keyed with the webhook secret:
+//
+//	X-Nextranet-Signature: sha256=<hex(HMAC(secret, timestamp + "." + body))>
+//
+// Receivers should reject requests whose timestamp is too old to be fresh.
+const (
+	HeaderWebhookEvent     = "X-Nextranet-Event"
+	HeaderWebhookDelivery  = "X-Nextranet-Delivery"
+	HeaderWebhookTimestamp = "X-Nextranet-Timestamp"
+	HeaderWebhookSignature = "X-Nextranet-Signature"
+)
+
+// webhookDispatchInterval is how often due retries are looked for
+const webhookDispatchInterval = time.Second
+
+// WebhookService posts bus events to the webhooks kept in the application
+// context, retrying failed deliveries with exponential backoff
+type WebhookService struct {
+	config     *config.Webhooks
+	appContext *appContext.Context
+	httpClient *http.Client
+
+	// wake triggers a dispatch as soon as a delivery is queued
+	wake chan struct{}
+}
+
+// NewWebhookService creates a new webhook service instance
+func NewWebhookService(cfg *config.Webhooks, ctx *appContext.Context) *WebhookService {
+	return &WebhookService{
+		config:     cfg,
+		appContext: ctx,
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Start queues deliveries for bus events and runs the delivery workers until
+// the context is cancelled
+func (s *WebhookService) Start(ctx context.Context) {
+	logger.NotifyLog.Infof("Starting webhook delivery with %d workers...", s.config.Workers)
+
+	jobs := make(chan *models.WebhookDelivery)
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				s.attempt(delivery)
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeEvents(ctx, s.appContext, "Webhooks", appContext.EventFilter{}, s.queue)
+	}()
+
+	s.dispatch(ctx, jobs)
+	close(jobs)
+	wg.Wait()
+	logger.NotifyLog.Info("Stopped webhook delivery")
+}
+
+// queue creates a delivery for every enabled webhook matching the event
+func (s *WebhookService) queue(event *appContext.Event) {
+	if !isNotifiable(event) {
+		return
+	}
+
+	var payload []byte
+	queued := false
+	for _, webhook := range s.appContext.GetWebhooks() {
+		if !s.matches(webhook, event) {
+			continue
+		}
+
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(event); err != nil {
+				logger.NotifyLog.Errorf("Failed to encode event %d for webhooks: %v", event.Sequence, err)
+				return
+			}
+		}
+
+		delivery := &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventType:     event.Type,
+			EventSequence: event.Sequence,
+			DeviceID:      event.DeviceID,
+			Payload:       payload,
+		}
+		if err := s.appContext.AddWebhookDelivery(delivery); err != nil {
+			// Deleted since it was listed
+			continue
+		}
+		queued = true
+	}
+
+	if queued {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// matches checks the event type and device selector of a webhook
+func (s *WebhookService) matches(webhook *models.Webhook, event *appContext.Event) bool {
+	if !webhook.Enabled {
+		return false
+	}
+
+	filter := appContext.EventFilter{Types: webhook.Events}
+	if !filter.Matches(event) {
+		return false
+	}
+
+	if event.DeviceID == "" {
+		return true
+	}
+	model, tags := eventDeviceSelector(s.appContext, event)
+	return webhook.MatchesDevice(event.DeviceID, model, tags)
+}
+
+// dispatch hands due deliveries to the workers until the context is
+// cancelled
+func (s *WebhookService) dispatch(ctx context.Context, jobs chan<- *models.WebhookDelivery) {
+	ticker := time.NewTicker(webhookDispatchInterval)
+	defer ticker.Stop()
+
+	// A claimed delivery waits for a worker at most one request timeout per
+	// delivery ahead of it, the lease must outlast that
+	lease := 2*s.config.Timeout + time.Minute
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+
+		for {
+			due := s.appContext.ClaimDueWebhookDeliveries(time.Now(), lease, s.config.Workers)
+			for _, delivery := range due {
+				select {
+				case jobs <- delivery:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(due) < s.config.Workers {
+				break
+			}
+		}
+	}
+}
+
+// attempt makes one delivery attempt and schedules the next one on failure.
+// A delivery that used up its attempts is dead.
+func (s *WebhookService) attempt(delivery *models.WebhookDelivery) {
+	webhook, exists := s.appContext.GetWebhook(delivery.WebhookID)
+	if !exists {
+		return
+	}
+
+	attempt := models.DeliveryAttempt{Timestamp: time.Now()}
+	if !webhook.Enabled {
+		attempt.Error = "webhook is disabled"
+		s.record(delivery, attempt, models.DeliveryStatusDead, nil)
+		return
+	}
+
+	statusCode, err := s.post(webhook, delivery)
+	attempt.StatusCode = statusCode
+	attempt.DurationMs = time.Since(attempt.Timestamp).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	if err == nil {
+		s.record(delivery, attempt, models.DeliveryStatusDelivered, nil)
+		return
+	}
+
+	attempts := len(delivery.Attempts) + 1
+	if attempts >= s.config.MaxAttempts {
+		logger.NotifyLog.Warnf("Webhook %s delivery %s of %s is dead after %d attempts: %v",
+			webhook.Name, delivery.ID, delivery.EventType, attempts, err)
+		s.record(delivery, attempt, models.DeliveryStatusDead, nil)
+		return
+	}
+
+	next := time.Now().Add(s.backoff(attempts))
+	logger.NotifyLog.Debugf("Webhook %s delivery %s attempt %d failed, retrying at %s: %v",
+		webhook.Name, delivery.ID, attempts, next.Format(time.RFC3339), err)
+	s.record(delivery, attempt, models.DeliveryStatusRetrying, &next)
+}
+
+// record stores the outcome of an attempt
+func (s *WebhookService) record(delivery *models.WebhookDelivery, attempt models.DeliveryAttempt, status string, next *time.Time) {
+	if err := s.appContext.RecordWebhookDeliveryAttempt(delivery.ID, attempt, status, next); err != nil {
+		// The webhook was deleted or the delivery left the history
+		logger.NotifyLog.Debugf("Dropped webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// post sends the signed payload. Any status other than 2xx is a failure.
+func (s *WebhookService) post(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Nextranet-Gateway-Webhook/1.0")
+	req.Header.Set(HeaderWebhookEvent, delivery.EventType)
+	req.Header.Set(HeaderWebhookDelivery, delivery.ID)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, "sha256="+SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a bounded amount so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts,
+// doubling from the initial backoff up to the maximum with 10% jitter
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	delay := s.config.InitialBackoff
+	for i := 1; i < attempts && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	if delay >= 10 {
+		delay += time.Duration(rand.Int63n(int64(delay / 10)))
+	}
+	return delay
+}
+
+// SignWebhookPayload returns the hex encoded signature of a webhook request
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}